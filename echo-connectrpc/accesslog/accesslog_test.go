@@ -0,0 +1,186 @@
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/server"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestID   string
+		traceparent string
+		wantID      string
+		wantTraceID string
+	}{
+		{name: "request id", requestID: "abc-123", wantID: "abc-123"},
+		{name: "request id and traceparent", requestID: "abc-123", traceparent: traceparent, wantID: "abc-123", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "traceparent", traceparent: traceparent, wantID: "4bf92f3577b34da6a3ce929d0e0e4736", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "invalid request id", requestID: "bad id", traceparent: traceparent, wantID: "4bf92f3577b34da6a3ce929d0e0e4736", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "zero trace id", traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "uppercase trace id", traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "extra fields in version 00", traceparent: traceparent + "-extra"},
+		{name: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, traceID := RequestID(tt.requestID, tt.traceparent)
+			if traceID != tt.wantTraceID {
+				t.Errorf("expected trace ID %q, got %q", tt.wantTraceID, traceID)
+			}
+			if tt.wantID != "" {
+				if id != tt.wantID {
+					t.Errorf("expected ID %q, got %q", tt.wantID, id)
+				}
+			} else if len(id) != 32 {
+				t.Errorf("expected a generated ID, got %q", id)
+			}
+		})
+	}
+}
+
+// lineWriter hands each log line to the test, which may read it after the
+// response has reached the client.
+type lineWriter chan []byte
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- append([]byte(nil), p...)
+	return len(p), nil
+}
+
+func (w lineWriter) next(t *testing.T) map[string]any {
+	t.Helper()
+
+	select {
+	case data := <-w:
+		var line map[string]any
+		if err := json.Unmarshal(data, &line); err != nil {
+			t.Fatalf("invalid log line %q: %v", data, err)
+		}
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("no log line written")
+		return nil
+	}
+}
+
+func setupTestServer(t *testing.T) (*httptest.Server, lineWriter) {
+	t.Helper()
+
+	lines := make(lineWriter, 10)
+	l := New(lines)
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewEchoHandler(server.NewEchoServer(), connect.WithInterceptors(l.Interceptor())))
+
+	srv := httptest.NewServer(l.Middleware(mux))
+	t.Cleanup(srv.Close)
+	return srv, lines
+}
+
+func checkLine(t *testing.T, line, want map[string]any) {
+	t.Helper()
+
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("expected %s %v, got %v", key, value, line[key])
+		}
+	}
+	if _, ok := line["duration_ms"].(float64); !ok {
+		t.Errorf("expected duration_ms, got %v", line)
+	}
+}
+
+func TestMiddleware_RPC(t *testing.T) {
+	srv, lines := setupTestServer(t)
+	client := protoconnect.NewEchoClient(srv.Client(), srv.URL, connect.WithGRPC())
+
+	req := connect.NewRequest(&pb.EchoErrorRequest{Code: int32(connect.CodeNotFound)})
+	req.Header().Set(RequestIDHeader, "abc-123")
+	_, err := client.EchoError(context.Background(), req)
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("expected a Connect error, got %v", err)
+	}
+	if got := connectErr.Meta().Get(RequestIDHeader); got != "abc-123" {
+		t.Errorf("expected the request ID echoed back, got %q", got)
+	}
+
+	line := lines.next(t)
+	checkLine(t, line, map[string]any{
+		"msg":          "access",
+		"request_id":   "abc-123",
+		"protocol":     "HTTP/1.1",
+		"rpc_protocol": "grpc",
+		"method":       "POST",
+		"route":        "/echo.v1.Echo/EchoError",
+		"status":       float64(200),
+		"code":         "not_found",
+	})
+	// The error is sent in a trailers-only response, without a body
+	if line["bytes_in"].(float64) == 0 {
+		t.Errorf("expected request bytes counted, got %v", line)
+	}
+}
+
+func TestMiddleware_ResetStream(t *testing.T) {
+	srv, lines := setupTestServer(t)
+	client := protoconnect.NewEchoClient(srv.Client(), srv.URL)
+
+	stream, err := client.ServerStream(context.Background(), connect.NewRequest(&pb.ServerStreamRequest{
+		Message: "ping",
+		Count:   3,
+		Failure: &pb.StreamFailure{After: 1, RstStream: true},
+	}))
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	for stream.Receive() {
+	}
+	_ = stream.Close()
+
+	checkLine(t, lines.next(t), map[string]any{
+		"rpc_protocol": "connect",
+		"route":        "/echo.v1.Echo/ServerStream",
+		"code":         "internal",
+	})
+}
+
+func TestMiddleware_NotFound(t *testing.T) {
+	srv, lines := setupTestServer(t)
+
+	resp, err := srv.Client().Get(srv.URL + "/missing")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	id := resp.Header.Get(RequestIDHeader)
+	if id == "" {
+		t.Error("expected a generated request ID")
+	}
+
+	line := lines.next(t)
+	checkLine(t, line, map[string]any{
+		"request_id": id,
+		"method":     "GET",
+		"route":      "/missing",
+		"status":     float64(404),
+	})
+	if _, ok := line["code"]; ok {
+		t.Errorf("expected no code outside RPCs, got %v", line)
+	}
+}
@@ -0,0 +1,145 @@
+package accesslog
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+type recordKey struct{}
+
+// Middleware logs each request once it has been served and sets
+// X-Request-ID on the response. RPCs are logged with the procedure, protocol
+// and code recorded by Interceptor; other requests with their path.
+func (l *Logger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id, traceID := RequestID(r.Header.Get(RequestIDHeader), r.Header.Get("Traceparent"))
+		w.Header().Set(RequestIDHeader, id)
+
+		body := &countingReader{ReadCloser: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+
+		rec := &record{
+			requestID: id,
+			traceID:   traceID,
+			protocol:  r.Proto,
+			method:    r.Method,
+			route:     r.URL.Path,
+			peer:      r.RemoteAddr,
+		}
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			rec.status = rw.status
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			rec.duration = time.Since(start)
+			rec.bytesIn = body.n.Load()
+			rec.bytesOut = rw.bytes
+			l.write(r.Context(), rec)
+		}()
+
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), recordKey{}, rec)))
+	})
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// responseWriter captures the status and body size of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Flush is required by Connect for streaming RPCs.
+func (rw *responseWriter) Flush() {
+	_ = http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Interceptor returns a connect.Interceptor adding the procedure, protocol
+// and code of RPCs to the records of Middleware.
+func (l *Logger) Interceptor() connect.Interceptor {
+	return &interceptor{}
+}
+
+type interceptor struct{}
+
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		annotate(ctx, req.Spec(), req.Peer(), err)
+		return resp, err
+	}
+}
+
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		// Deferred so that streams reset with panic(http.ErrAbortHandler)
+		// are logged with their procedure too, as internal errors
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				annotate(ctx, conn.Spec(), conn.Peer(), connect.NewError(connect.CodeInternal, nil))
+				panic(r)
+			}
+			annotate(ctx, conn.Spec(), conn.Peer(), err)
+		}()
+
+		err = next(ctx, conn)
+		return err
+	}
+}
+
+func annotate(ctx context.Context, spec connect.Spec, peer connect.Peer, err error) {
+	rec, ok := ctx.Value(recordKey{}).(*record)
+	if !ok {
+		return
+	}
+	rec.route = spec.Procedure
+	rec.rpcProtocol = peer.Protocol
+	rec.code = "ok"
+	if err != nil {
+		rec.code = connect.CodeOf(err).String()
+	}
+}
@@ -1,20 +1,46 @@
 package main
 
 import (
+	"log"
+	"math"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"connectrpc.com/connect"
 	"github.com/joho/godotenv"
+	"golang.org/x/net/http2"
 )
 
 type Config struct {
 	Host                     string
 	Port                     string
+	Listen                   string
 	DisableConnectRPC        bool
 	DisableGRPC              bool
 	DisableGRPCWeb           bool
 	ReflectionIncludeDeps    bool
 	DisableReflectionV1      bool
 	DisableReflectionV1Alpha bool
+	TLSCertFile              string
+	TLSKeyFile               string
+	TLSClientCAFile          string
+	TLSClientAuth            string
+	TLSSelfSigned            bool
+	TLSSelfSignedDir         string
+	TLSSelfSignedHosts       string
+	ShutdownDrainPeriod      time.Duration
+	ShutdownTimeout          time.Duration
+	Compressors              []string
+	CompressMinBytes         int
+	MockRulesFile            string
+	MaxRecvMsgSize           int
+	MaxSendMsgSize           int
+	MaxConcurrentStreams     int
+	MaxHeaderListSize        int
+	AccessLog                bool
 }
 
 func LoadConfig() *Config {
@@ -24,12 +50,30 @@ func LoadConfig() *Config {
 	return &Config{
 		Host:                     getEnv("HOST", "0.0.0.0"),
 		Port:                     getEnv("PORT", "8080"),
+		Listen:                   getEnv("LISTEN", ""),
 		DisableConnectRPC:        getEnvBool("DISABLE_CONNECTRPC", false),
 		DisableGRPC:              getEnvBool("DISABLE_GRPC", false),
 		DisableGRPCWeb:           getEnvBool("DISABLE_GRPC_WEB", false),
 		ReflectionIncludeDeps:    getEnvBool("REFLECTION_INCLUDE_DEPENDENCIES", false),
 		DisableReflectionV1:      getEnvBool("DISABLE_REFLECTION_V1", false),
 		DisableReflectionV1Alpha: getEnvBool("DISABLE_REFLECTION_V1ALPHA", false),
+		TLSCertFile:              getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:               getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:          getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:            getEnv("TLS_CLIENT_AUTH", ""),
+		TLSSelfSigned:            getEnvBool("TLS_SELF_SIGNED", false),
+		TLSSelfSignedDir:         getEnv("TLS_SELF_SIGNED_DIR", "tls"),
+		TLSSelfSignedHosts:       getEnv("TLS_SELF_SIGNED_HOSTS", ""),
+		ShutdownDrainPeriod:      getEnvDuration("SHUTDOWN_DRAIN_PERIOD", 0),
+		ShutdownTimeout:          getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		Compressors:              getEnvList("COMPRESSORS", "gzip,deflate,zstd,snappy"),
+		CompressMinBytes:         getEnvInt("COMPRESS_MIN_BYTES", 0),
+		MockRulesFile:            getEnv("MOCK_RULES_FILE", ""),
+		MaxRecvMsgSize:           getEnvInt("MAX_RECV_MSG_SIZE", 0),
+		MaxSendMsgSize:           getEnvInt("MAX_SEND_MSG_SIZE", 0),
+		MaxConcurrentStreams:     getEnvInt("MAX_CONCURRENT_STREAMS", 0),
+		MaxHeaderListSize:        getEnvInt("MAX_HEADER_LIST_SIZE", 0),
+		AccessLog:                getEnvBool("ACCESS_LOG", true),
 	}
 }
 
@@ -37,6 +81,30 @@ func (c *Config) Addr() string {
 	return c.Host + ":" + c.Port
 }
 
+// LimitOptions caps the size of request and response messages. Zero values
+// keep the connect-go defaults, which set no limit.
+func (c *Config) LimitOptions() []connect.HandlerOption {
+	var opts []connect.HandlerOption
+	if c.MaxRecvMsgSize > 0 {
+		opts = append(opts, connect.WithReadMaxBytes(c.MaxRecvMsgSize))
+	}
+	if c.MaxSendMsgSize > 0 {
+		opts = append(opts, connect.WithSendMaxBytes(c.MaxSendMsgSize))
+	}
+	return opts
+}
+
+// ConfigureLimits applies the concurrent stream and header list limits to
+// the HTTP/1.1 and HTTP/2 servers. Zero values keep the net/http defaults.
+func (c *Config) ConfigureLimits(srv *http.Server, h2s *http2.Server) {
+	if c.MaxConcurrentStreams > 0 {
+		h2s.MaxConcurrentStreams = uint32(min(c.MaxConcurrentStreams, math.MaxUint32))
+	}
+	if c.MaxHeaderListSize > 0 {
+		srv.MaxHeaderBytes = c.MaxHeaderListSize
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -59,3 +127,42 @@ func getEnvBool(key string, defaultValue bool) bool {
 		return defaultValue
 	}
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		log.Fatalf("Invalid %s %q: want a non-negative integer", key, value)
+	}
+	return parsed
+}
+
+// getEnvList splits a comma-separated value, dropping empty entries and
+// "identity", which is always supported.
+func getEnvList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && item != "identity" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return defaultValue
+	}
+	return parsed
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/server"
+)
+
+func TestGetEnvInt(t *testing.T) {
+	t.Setenv("ECHO_TEST_INT", "")
+	if got := getEnvInt("ECHO_TEST_INT", 7); got != 7 {
+		t.Errorf("expected the default for an empty value, got %d", got)
+	}
+	t.Setenv("ECHO_TEST_INT", "1024")
+	if got := getEnvInt("ECHO_TEST_INT", 7); got != 1024 {
+		t.Errorf("expected 1024, got %d", got)
+	}
+}
+
+func TestGetEnvInt_Invalid(t *testing.T) {
+	// log.Fatal exits, so the invalid values are parsed in a child process
+	if key := os.Getenv("ECHO_TEST_INT_KEY"); key != "" {
+		getEnvInt(key, 0)
+		return
+	}
+
+	for _, value := range []string{"1k", "-1", "0x10"} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestGetEnvInt_Invalid$")
+		cmd.Env = append(os.Environ(), "ECHO_TEST_INT_KEY=MAX_RECV_MSG_SIZE", "MAX_RECV_MSG_SIZE="+value)
+		out, err := cmd.CombinedOutput()
+		if err == nil {
+			t.Errorf("%q: expected the process to exit with an error", value)
+			continue
+		}
+		if want := `Invalid MAX_RECV_MSG_SIZE "` + value + `"`; !strings.Contains(string(out), want) {
+			t.Errorf("%q: expected output containing %q, got %s", value, want, out)
+		}
+	}
+}
+
+func TestConfig_LimitOptions_MaxRecvMsgSize(t *testing.T) {
+	cfg := &Config{MaxRecvMsgSize: 1024}
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewEchoHandler(server.NewEchoServer(), cfg.LimitOptions()...))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	for _, protocol := range []struct {
+		name string
+		opts []connect.ClientOption
+	}{
+		{name: "connect"},
+		{name: "grpc", opts: []connect.ClientOption{connect.WithGRPC()}},
+		{name: "grpc-web", opts: []connect.ClientOption{connect.WithGRPCWeb()}},
+	} {
+		t.Run(protocol.name, func(t *testing.T) {
+			client := protoconnect.NewEchoClient(srv.Client(), srv.URL, protocol.opts...)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if _, err := client.Echo(ctx, connect.NewRequest(&pb.EchoRequest{Message: "small"})); err != nil {
+				t.Fatalf("expected a message under the limit to succeed, got %v", err)
+			}
+			_, err := client.Echo(ctx, connect.NewRequest(&pb.EchoRequest{Message: strings.Repeat("x", 2048)}))
+			var connectErr *connect.Error
+			if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeResourceExhausted {
+				t.Errorf("expected resource_exhausted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_ConfigureLimits(t *testing.T) {
+	cfg := &Config{MaxConcurrentStreams: 3, MaxHeaderListSize: 4096}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	// Set up as main does for h2c listeners
+	h2s := &http2.Server{}
+	srv := &http.Server{Handler: h2c.NewHandler(http.NotFoundHandler(), h2s)}
+	cfg.ConfigureLimits(srv, h2s)
+	if err := http2.ConfigureServer(srv, h2s); err != nil {
+		t.Fatalf("failed to configure HTTP/2: %v", err)
+	}
+	if srv.MaxHeaderBytes != 4096 {
+		t.Errorf("expected MaxHeaderBytes 4096, got %d", srv.MaxHeaderBytes)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	settings := readServerSettings(t, lis.Addr().String())
+	if got := settings[http2.SettingMaxConcurrentStreams]; got != 3 {
+		t.Errorf("expected SETTINGS_MAX_CONCURRENT_STREAMS 3, got %d", got)
+	}
+}
+
+// readServerSettings opens an HTTP/2 connection without TLS and returns the
+// settings the server announces first.
+func readServerSettings(t *testing.T, addr string) map[http2.SettingID]uint32 {
+	t.Helper()
+
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := conn.Write([]byte(http2.ClientPreface)); err != nil {
+		t.Fatalf("failed to write preface: %v", err)
+	}
+	framer := http2.NewFramer(conn, conn)
+	if err := framer.WriteSettings(); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+	for {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			t.Fatalf("failed to read settings: %v", err)
+		}
+		sf, ok := frame.(*http2.SettingsFrame)
+		if !ok || sf.IsAck() {
+			continue
+		}
+		settings := map[http2.SettingID]uint32{}
+		_ = sf.ForeachSetting(func(s http2.Setting) error {
+			settings[s.ID] = s.Val
+			return nil
+		})
+		return settings
+	}
+}
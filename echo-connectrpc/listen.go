@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Listener is one address the server listens on.
+type Listener struct {
+	Name    string // the address as configured, for logs
+	Network string
+	Address string
+	TLS     bool
+	Proto   string // "h1", "h2" or "h2c"; empty serves HTTP/1.1, and HTTP/2 over TLS
+}
+
+// Listeners parses LISTEN, a comma-separated list of addresses with optional
+// tls and proto options, or returns HOST and PORT when it is unset. Listeners
+// serve TLS when it is configured unless tls=false is given.
+func (c *Config) Listeners() ([]Listener, error) {
+	if c.Listen == "" {
+		return []Listener{{Name: c.Addr(), Network: "tcp", Address: c.Addr(), TLS: c.TLSEnabled()}}, nil
+	}
+
+	var listeners []Listener
+	for _, entry := range strings.Split(c.Listen, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		l, err := c.parseListener(entry)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	if len(listeners) == 0 {
+		return nil, errors.New("LISTEN has no addresses")
+	}
+	return listeners, nil
+}
+
+// parseListener parses ADDRESS[?tls=BOOL&proto=h1|h2|h2c].
+func (c *Config) parseListener(entry string) (Listener, error) {
+	value, query, _ := strings.Cut(entry, "?")
+	network, address, err := parseListen(value)
+	if err != nil {
+		return Listener{}, err
+	}
+	options, err := url.ParseQuery(query)
+	if err != nil {
+		return Listener{}, fmt.Errorf("invalid LISTEN %q: %w", entry, err)
+	}
+
+	l := Listener{Name: value, Network: network, Address: address, TLS: c.TLSEnabled()}
+	for key, values := range options {
+		option := values[len(values)-1]
+		switch key {
+		case "tls":
+			enabled, err := strconv.ParseBool(option)
+			if err != nil {
+				return Listener{}, fmt.Errorf("invalid LISTEN %q: tls must be true or false", entry)
+			}
+			if enabled && !c.TLSEnabled() {
+				return Listener{}, fmt.Errorf("invalid LISTEN %q: tls=true needs TLS_CERT_FILE or TLS_SELF_SIGNED", entry)
+			}
+			l.TLS = enabled
+		case "proto":
+			if option != "h1" && option != "h2" && option != "h2c" {
+				return Listener{}, fmt.Errorf("invalid LISTEN %q: proto must be h1, h2 or h2c", entry)
+			}
+			l.Proto = option
+		default:
+			return Listener{}, fmt.Errorf("invalid LISTEN %q: unknown option %q", entry, key)
+		}
+	}
+	if l.Proto == "h2" && !l.TLS {
+		return Listener{}, fmt.Errorf("invalid LISTEN %q: proto=h2 needs TLS, use h2c without it", entry)
+	}
+	if l.Proto == "h2c" && l.TLS {
+		return Listener{}, fmt.Errorf("invalid LISTEN %q: proto=h2c is for cleartext, use h2 with TLS", entry)
+	}
+	return l, nil
+}
+
+// parseListen splits an address of LISTEN into the network and address of
+// net.Listen. It accepts the gRPC naming forms tcp://HOST:PORT,
+// unix:PATH, unix:///ABSOLUTE_PATH and unix-abstract:NAME, the latter being a
+// Linux abstract socket.
+func parseListen(value string) (network, address string, err error) {
+	switch {
+	case strings.HasPrefix(value, "tcp://"):
+		address = strings.TrimPrefix(value, "tcp://")
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return "", "", fmt.Errorf("invalid LISTEN %q: %w", value, err)
+		}
+		return "tcp", address, nil
+	case strings.HasPrefix(value, "unix-abstract:"):
+		name := strings.TrimPrefix(value, "unix-abstract:")
+		if name == "" {
+			return "", "", fmt.Errorf("invalid LISTEN %q: missing socket name", value)
+		}
+		return "unix", "@" + name, nil
+	case strings.HasPrefix(value, "unix://"):
+		path := strings.TrimPrefix(value, "unix://")
+		if !strings.HasPrefix(path, "/") {
+			return "", "", fmt.Errorf("invalid LISTEN %q: unix:// needs an absolute path", value)
+		}
+		return "unix", path, nil
+	case strings.HasPrefix(value, "unix:"):
+		path := strings.TrimPrefix(value, "unix:")
+		if path == "" {
+			return "", "", fmt.Errorf("invalid LISTEN %q: missing socket path", value)
+		}
+		return "unix", path, nil
+	default:
+		return "", "", fmt.Errorf("invalid LISTEN %q: want tcp://HOST:PORT, unix:PATH or unix-abstract:NAME", value)
+	}
+}
+
+// String describes the listener for logs.
+func (l Listener) String() string {
+	var attrs []string
+	if l.TLS {
+		attrs = append(attrs, "TLS")
+	}
+	if l.Proto != "" {
+		attrs = append(attrs, l.Proto)
+	}
+	if len(attrs) == 0 {
+		return l.Name
+	}
+	return l.Name + " (" + strings.Join(attrs, ", ") + ")"
+}
+
+// Listen opens the listener.
+func (l Listener) Listen() (net.Listener, error) {
+	if l.Network == "unix" && !strings.HasPrefix(l.Address, "@") {
+		// Remove the socket left behind by a previous run that did not exit
+		// cleanly; other files are kept and make Listen fail
+		if info, err := os.Stat(l.Address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(l.Address)
+		}
+	}
+	return net.Listen(l.Network, l.Address)
+}
+
+// Protocols returns the HTTP versions served by the listener, or nil for the
+// net/http defaults.
+func (l Listener) Protocols() *http.Protocols {
+	if l.Proto == "" {
+		return nil
+	}
+	protocols := new(http.Protocols)
+	switch l.Proto {
+	case "h1":
+		protocols.SetHTTP1(true)
+	case "h2":
+		protocols.SetHTTP2(true)
+	case "h2c":
+		protocols.SetUnencryptedHTTP2(true)
+	}
+	return protocols
+}
+
+// httpServers serves the same handlers on several listeners, with an
+// http.Server for each since TLS and HTTP versions are set per server.
+type httpServers []httpServer
+
+type httpServer struct {
+	*http.Server
+	listener Listener
+	lis      net.Listener
+}
+
+// newHTTPServers opens every listener and creates its server with newServer.
+func newHTTPServers(listeners []Listener, newServer func(Listener) (*http.Server, error)) (httpServers, error) {
+	var servers httpServers
+	fail := func(l Listener, err error) (httpServers, error) {
+		for _, srv := range servers {
+			_ = srv.lis.Close()
+		}
+		return nil, fmt.Errorf("%s: %w", l, err)
+	}
+
+	for _, l := range listeners {
+		srv, err := newServer(l)
+		if err != nil {
+			return fail(l, err)
+		}
+		lis, err := l.Listen()
+		if err != nil {
+			return fail(l, err)
+		}
+		servers = append(servers, httpServer{Server: srv, listener: l, lis: lis})
+	}
+	return servers, nil
+}
+
+// Serve serves every listener and returns once all have been shut down, or
+// with the first error of a listener that failed.
+func (s httpServers) Serve() error {
+	errc := make(chan error, len(s))
+	for _, srv := range s {
+		go func() {
+			log.Printf("Listening on %s", srv.listener)
+			var err error
+			if srv.listener.TLS {
+				err = srv.ServeTLS(srv.lis, "", "")
+			} else {
+				err = srv.Serve(srv.lis)
+			}
+			if errors.Is(err, http.ErrServerClosed) {
+				err = nil
+			} else if err != nil {
+				err = fmt.Errorf("%s: %w", srv.listener, err)
+			}
+			errc <- err
+		}()
+	}
+	for range s {
+		if err := <-errc; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown shuts every server down at once and returns their errors.
+func (s httpServers) Shutdown(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(s))
+	for i, srv := range s {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = srv.Shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Close closes every server immediately.
+func (s httpServers) Close() {
+	for _, srv := range s {
+		_ = srv.Close()
+	}
+}
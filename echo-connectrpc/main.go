@@ -2,20 +2,22 @@ package main
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"connectrpc.com/connect"
-	"connectrpc.com/grpchealth"
 	"connectrpc.com/grpcreflect"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/accesslog"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/metrics"
 	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
 	"github.com/jsr-probitas/echo-servers/echo-connectrpc/server"
 )
@@ -29,9 +31,38 @@ func main() {
 	}
 
 	mux := http.NewServeMux()
+	m := metrics.New()
 
-	// Prepare handler options for protocol control
-	var handlerOpts []connect.HandlerOption
+	// Prepare handler options: metrics, compression, X-Echo-* headers
+	// reporting the protocol, codec and compression of each call, and mocks
+	compressionOpts, err := server.CompressionOptions(cfg.Compressors, cfg.CompressMinBytes)
+	if err != nil {
+		log.Fatalf("Failed to configure compression: %v", err)
+	}
+	interceptors := []connect.Interceptor{m.Interceptor(), server.NewCallInfoInterceptor(cfg.Compressors)}
+
+	// Log each request as a JSON line correlated by X-Request-ID, with the
+	// procedure and code of RPCs
+	var accessLog *accesslog.Logger
+	if cfg.AccessLog {
+		accessLog = accesslog.New(os.Stdout)
+		interceptors = append([]connect.Interceptor{accessLog.Interceptor()}, interceptors...)
+	}
+
+	// Answer calls matching mock rules with canned responses
+	if cfg.MockRulesFile != "" {
+		rules, err := server.LoadMockRules(cfg.MockRulesFile)
+		if err != nil {
+			log.Fatalf("Failed to load mock rules: %v", err)
+		}
+		interceptors = append(interceptors, rules.Interceptor())
+		log.Printf("Loaded mock rules from %s", cfg.MockRulesFile)
+	}
+
+	handlerOpts := append([]connect.HandlerOption{
+		connect.WithInterceptors(interceptors...),
+	}, compressionOpts...)
+	handlerOpts = append(handlerOpts, cfg.LimitOptions()...)
 
 	// Determine which protocols to support
 	protocols := []string{}
@@ -47,6 +78,7 @@ func main() {
 
 	// Log enabled protocols
 	log.Printf("Enabled protocols: %v", protocols)
+	log.Printf("Compressors: %v (min %d bytes)", cfg.Compressors, cfg.CompressMinBytes)
 
 	// Register echo service
 	echoServer := server.NewEchoServer()
@@ -54,24 +86,19 @@ func main() {
 	mux.Handle(path, protocolFilterMiddleware(cfg, handler))
 
 	// Register health check service
-	checker := grpchealth.NewStaticChecker(
-		protoconnect.EchoName,
-	)
-	healthPath, healthHandler := grpchealth.NewHandler(checker, handlerOpts...)
+	healthServer := server.NewHealthServer()
+	healthPath, healthHandler := server.NewHealthHandler(healthServer, handlerOpts...)
 	mux.Handle(healthPath, protocolFilterMiddleware(cfg, healthHandler))
 
-	// Register reflection service
-	if !cfg.ReflectionIncludeDeps {
-		// By default, grpcreflect includes dependencies
-		// We need to use custom options if we want to exclude them
-		// For now, we'll document this limitation
-		log.Printf("Note: REFLECTION_INCLUDE_DEPENDENCIES is set to %v", cfg.ReflectionIncludeDeps)
-	}
+	// Register admin service (runtime health control)
+	adminPath, adminHandler := protoconnect.NewAdminHandler(server.NewAdminServer(healthServer), handlerOpts...)
+	mux.Handle(adminPath, protocolFilterMiddleware(cfg, adminHandler))
 
 	// Build list of services for reflection
 	reflectionServices := []string{
 		protoconnect.EchoName,
-		grpchealth.HealthV1ServiceName,
+		protoconnect.AdminName,
+		server.HealthServiceName,
 	}
 
 	if !cfg.DisableReflectionV1 {
@@ -81,11 +108,13 @@ func main() {
 		reflectionServices = append(reflectionServices, grpcreflect.ReflectV1AlphaServiceName)
 	}
 
-	// Create reflector
-	reflector := grpcreflect.NewStaticReflector(reflectionServices...)
+	// Register reflection service. Like echo-grpc, dependencies are omitted
+	// unless REFLECTION_INCLUDE_DEPENDENCIES is set
+	reflector := server.NewReflectionServer(reflectionServices, cfg.ReflectionIncludeDeps)
+	v1Path, v1Handler := server.NewReflectionHandlerV1(reflector, handlerOpts...)
+	v1AlphaPath, v1AlphaHandler := server.NewReflectionHandlerV1Alpha(reflector, handlerOpts...)
 
 	if !cfg.DisableReflectionV1 {
-		v1Path, v1Handler := grpcreflect.NewHandlerV1(reflector, handlerOpts...)
 		mux.Handle(v1Path, protocolFilterMiddleware(cfg, v1Handler))
 		log.Printf("Registered reflection v1")
 	} else {
@@ -93,46 +122,128 @@ func main() {
 	}
 
 	if !cfg.DisableReflectionV1Alpha {
-		v1AlphaPath, v1AlphaHandler := grpcreflect.NewHandlerV1Alpha(reflector, handlerOpts...)
 		mux.Handle(v1AlphaPath, protocolFilterMiddleware(cfg, v1AlphaHandler))
 		log.Printf("Registered reflection v1alpha")
 	} else {
 		log.Printf("Reflection v1alpha disabled")
 	}
 
-	// Create server with h2c support (HTTP/2 without TLS)
-	srv := &http.Server{
-		Addr:              cfg.Addr(),
-		Handler:           h2c.NewHandler(mux, &http2.Server{}),
-		ReadHeaderTimeout: 10 * time.Second,
+	// Prometheus metrics endpoint
+	mux.Handle("/metrics", m.Handler())
+
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		log.Fatalf("Failed to configure TLS: %v", err)
+	}
+
+	// Create a server for each listener, with h2c support (HTTP/2 without TLS)
+	// unless the listener is HTTP/1.1 only; HTTP/2 over TLS is negotiated via
+	// ALPN when TLS is enabled
+	listeners, err := cfg.Listeners()
+	if err != nil {
+		log.Fatalf("Failed to configure listeners: %v", err)
+	}
+	tracker := &requestTracker{}
+	root := tracker.Middleware(server.TLSStateMiddleware(m.Middleware(server.WireStatusMiddleware(mux))))
+	if accessLog != nil {
+		root = accessLog.Middleware(root)
+	}
+	servers, err := newHTTPServers(listeners, func(l Listener) (*http.Server, error) {
+		h2s := &http2.Server{}
+		srv := &http.Server{
+			Handler:           root,
+			ReadHeaderTimeout: 10 * time.Second,
+			Protocols:         l.Protocols(),
+		}
+		if l.Proto != "h1" {
+			srv.Handler = h2c.NewHandler(root, h2s)
+		}
+		if l.TLS {
+			srv.TLSConfig = tlsConfig.Clone()
+		}
+		cfg.ConfigureLimits(srv, h2s)
+
+		// Let Shutdown send GOAWAY on HTTP/2 connections, including h2c ones
+		if err := http2.ConfigureServer(srv, h2s); err != nil {
+			return nil, fmt.Errorf("configure HTTP/2: %w", err)
+		}
+		return srv, nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	// Graceful shutdown
+	// Graceful shutdown: report NOT_SERVING, drain, then send GOAWAY and wait
+	// for in-flight requests
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
+
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		<-sigChan
 
 		log.Println("Shutting down server...")
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		healthServer.Shutdown()
+		if cfg.ShutdownDrainPeriod > 0 {
+			log.Printf("Draining for %s", cfg.ShutdownDrainPeriod)
+			time.Sleep(cfg.ShutdownDrainPeriod)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 		defer cancel()
 
-		if err := srv.Shutdown(ctx); err != nil {
+		// Shutdown does not wait for h2c connections, which are hijacked from
+		// net/http, so wait for the tracked requests as well
+		err := servers.Shutdown(ctx)
+		if err == nil {
+			err = tracker.Wait(ctx)
+		}
+		if err != nil {
 			log.Printf("Server shutdown error: %v", err)
+			servers.Close()
 		}
 	}()
 
-	log.Printf("Starting Connect RPC server on %s", cfg.Addr())
 	log.Printf("Protocol configuration: ConnectRPC=%v, gRPC=%v, gRPC-Web=%v",
 		!cfg.DisableConnectRPC, !cfg.DisableGRPC, !cfg.DisableGRPCWeb)
 
-	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if err := servers.Serve(); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 
+	<-stopped
 	log.Println("Server stopped")
 }
 
+// requestTracker counts the requests currently being served.
+type requestTracker struct {
+	active atomic.Int64
+}
+
+func (t *requestTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.active.Add(1)
+		defer t.active.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Wait blocks until no request is being served or ctx is done.
+func (t *requestTracker) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for t.active.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
 // protocolFilterMiddleware filters requests based on the Connect protocol header
 func protocolFilterMiddleware(cfg *Config, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -148,7 +259,9 @@ func protocolFilterMiddleware(cfg *Config, next http.Handler) http.Handler {
 			contentType == "application/json" ||
 			contentType == "application/proto" ||
 			contains(contentType, "application/json;") ||
-			contains(contentType, "application/proto;")
+			contains(contentType, "application/proto;") ||
+			// Only Connect uses GET, for RPCs without side effects
+			r.Method == http.MethodGet
 
 		// If it's a recognized protocol, check if it's disabled
 		if isGRPC && cfg.DisableGRPC {
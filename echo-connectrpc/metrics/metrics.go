@@ -0,0 +1,281 @@
+// Package metrics exposes Prometheus metrics for the echo Connect RPC server.
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Metrics records per-procedure RPC metrics through Interceptor and
+// per-request transport metrics through Middleware.
+type Metrics struct {
+	registry      *prometheus.Registry
+	started       *prometheus.CounterVec
+	handled       *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	activeStreams *prometheus.GaugeVec
+	msgReceived   *prometheus.CounterVec
+	msgSent       *prometheus.CounterVec
+	inFlight      *prometheus.GaugeVec
+	requestBytes  *prometheus.CounterVec
+	responseBytes *prometheus.CounterVec
+}
+
+// New creates a Metrics instance backed by its own registry, which also
+// includes the Go runtime and process collectors.
+func New() *Metrics {
+	labels := []string{"service", "method", "type", "protocol"}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "connect_server_started_total",
+			Help: "Total number of RPCs started on the server.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "connect_server_handled_total",
+			Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+		}, append(labels, "code")),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "connect_server_handling_seconds",
+			Help:    "Latency of RPCs handled by the server.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		activeStreams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "connect_server_active_streams",
+			Help: "Number of streaming RPCs currently open.",
+		}, labels),
+		msgReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "connect_server_msg_received_total",
+			Help: "Total number of messages received from clients.",
+		}, labels),
+		msgSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "connect_server_msg_sent_total",
+			Help: "Total number of messages sent to clients.",
+		}, labels),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served by procedure.",
+		}, []string{"procedure"}),
+		requestBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_request_bytes_total",
+			Help: "Total request body bytes received by procedure.",
+		}, []string{"procedure"}),
+		responseBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_response_bytes_total",
+			Help: "Total response body bytes sent by procedure.",
+		}, []string{"procedure"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.started,
+		m.handled,
+		m.duration,
+		m.activeStreams,
+		m.msgReceived,
+		m.msgSent,
+		m.inFlight,
+		m.requestBytes,
+		m.responseBytes,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func rpcLabels(spec connect.Spec, peer connect.Peer) []string {
+	name := strings.TrimPrefix(spec.Procedure, "/")
+	service, method, ok := strings.Cut(name, "/")
+	if !ok {
+		service, method = "unknown", name
+	}
+	return []string{service, method, spec.StreamType.String(), peer.Protocol}
+}
+
+func codeLabel(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return connect.CodeOf(err).String()
+}
+
+// Interceptor returns a connect.Interceptor recording RPC metrics.
+func (m *Metrics) Interceptor() connect.Interceptor {
+	return &interceptor{metrics: m}
+}
+
+type interceptor struct {
+	metrics *Metrics
+}
+
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		m := i.metrics
+		labels := rpcLabels(req.Spec(), req.Peer())
+		start := time.Now()
+		m.started.WithLabelValues(labels...).Inc()
+		m.msgReceived.WithLabelValues(labels...).Inc()
+
+		resp, err := next(ctx, req)
+
+		if err == nil {
+			m.msgSent.WithLabelValues(labels...).Inc()
+		}
+		m.handled.WithLabelValues(append(labels, codeLabel(err))...).Inc()
+		m.duration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		m := i.metrics
+		labels := rpcLabels(conn.Spec(), conn.Peer())
+		start := time.Now()
+		m.started.WithLabelValues(labels...).Inc()
+		m.activeStreams.WithLabelValues(labels...).Inc()
+
+		// Deferred so that streams reset with panic(http.ErrAbortHandler)
+		// are recorded too, as internal errors
+		var err error
+		defer func() {
+			r := recover()
+			code := codeLabel(err)
+			if r != nil {
+				code = connect.CodeInternal.String()
+			}
+			m.activeStreams.WithLabelValues(labels...).Dec()
+			m.handled.WithLabelValues(append(labels, code)...).Inc()
+			m.duration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+			if r != nil {
+				panic(r)
+			}
+		}()
+
+		err = next(ctx, &countingConn{
+			StreamingHandlerConn: conn,
+			received:             m.msgReceived.WithLabelValues(labels...),
+			sent:                 m.msgSent.WithLabelValues(labels...),
+		})
+		return err
+	}
+}
+
+type countingConn struct {
+	connect.StreamingHandlerConn
+	received prometheus.Counter
+	sent     prometheus.Counter
+}
+
+func (c *countingConn) Receive(msg any) error {
+	err := c.StreamingHandlerConn.Receive(msg)
+	if err == nil {
+		c.received.Inc()
+	}
+	return err
+}
+
+func (c *countingConn) Send(msg any) error {
+	err := c.StreamingHandlerConn.Send(msg)
+	if err == nil {
+		c.sent.Inc()
+	}
+	return err
+}
+
+// Middleware records in-flight requests and bytes on the wire per procedure.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		procedure := procedureLabel(r.URL.Path)
+		inFlight := m.inFlight.WithLabelValues(procedure)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		body := &countingReader{ReadCloser: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+		rw := &countingWriter{ResponseWriter: w}
+
+		defer func() {
+			m.requestBytes.WithLabelValues(procedure).Add(float64(body.n.Load()))
+			m.responseBytes.WithLabelValues(procedure).Add(float64(rw.n))
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// procedureLabel returns path when it names a method of a registered
+// service, and "unmatched" otherwise, so that probes of arbitrary paths do not
+// each create a time series.
+func procedureLabel(path string) string {
+	service, method, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if !ok {
+		return "unmatched"
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return "unmatched"
+	}
+	if sd, ok := desc.(protoreflect.ServiceDescriptor); !ok || sd.Methods().ByName(protoreflect.Name(method)) == nil {
+		return "unmatched"
+	}
+	return path
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.ResponseWriter.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Flush is required by Connect for streaming RPCs.
+func (c *countingWriter) Flush() {
+	_ = http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+func (c *countingWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
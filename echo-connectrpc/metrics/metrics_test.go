@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/server"
+)
+
+func setupTestServer(t *testing.T, m *Metrics) (protoconnect.EchoClient, *httptest.Server) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	path, handler := protoconnect.NewEchoHandler(server.NewEchoServer(), connect.WithInterceptors(m.Interceptor()))
+	mux.Handle(path, handler)
+
+	srv := httptest.NewServer(m.Middleware(mux))
+	client := protoconnect.NewEchoClient(http.DefaultClient, srv.URL)
+
+	return client, srv
+}
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestMetrics_RecordsUnaryRPCs(t *testing.T) {
+	m := New()
+	client, srv := setupTestServer(t, m)
+	defer srv.Close()
+
+	if _, err := client.Echo(context.Background(), connect.NewRequest(&pb.EchoRequest{Message: "hello"})); err != nil {
+		t.Fatalf("Echo failed: %v", err)
+	}
+	_, _ = client.EchoError(context.Background(), connect.NewRequest(&pb.EchoErrorRequest{Code: 5}))
+
+	body := scrape(t, m)
+
+	expected := []string{
+		`connect_server_started_total{method="Echo",protocol="connect",service="echo.v1.Echo",type="unary"} 1`,
+		`connect_server_handled_total{code="ok",method="Echo",protocol="connect",service="echo.v1.Echo",type="unary"} 1`,
+		`connect_server_handled_total{code="not_found",method="EchoError",protocol="connect",service="echo.v1.Echo",type="unary"} 1`,
+		`connect_server_handling_seconds_count{method="Echo",protocol="connect",service="echo.v1.Echo",type="unary"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected metrics to contain %q", line)
+		}
+	}
+}
+
+func TestMetrics_RecordsStreamingRPCs(t *testing.T) {
+	m := New()
+	client, srv := setupTestServer(t, m)
+	defer srv.Close()
+
+	stream, err := client.ServerStream(context.Background(), connect.NewRequest(&pb.ServerStreamRequest{
+		Message: "ping",
+		Count:   3,
+	}))
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+
+	active := `connect_server_active_streams{method="ServerStream",protocol="connect",service="echo.v1.Echo",type="server"}`
+	if !stream.Receive() {
+		t.Fatalf("expected a message, got %v", stream.Err())
+	}
+	for stream.Receive() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	_ = stream.Close()
+
+	body := scrape(t, m)
+	if !strings.Contains(body, `connect_server_msg_sent_total{method="ServerStream",protocol="connect",service="echo.v1.Echo",type="server"} 3`) {
+		t.Error("expected 3 streamed messages to be counted")
+	}
+	if !strings.Contains(body, active+" 0") {
+		t.Errorf("expected %s 0 after the stream closed", active)
+	}
+}
+
+func TestMetrics_LabelsTransportMetricsByProcedure(t *testing.T) {
+	m := New()
+	client, srv := setupTestServer(t, m)
+	defer srv.Close()
+
+	if _, err := client.Echo(context.Background(), connect.NewRequest(&pb.EchoRequest{Message: "hello"})); err != nil {
+		t.Fatalf("Echo failed: %v", err)
+	}
+	for _, path := range []string{"/wp-login.php", "/echo.v1.Echo/Unknown", "/no.such.Service/Echo"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	body := scrape(t, m)
+	for _, line := range []string{
+		`http_request_bytes_total{procedure="/echo.v1.Echo/Echo"}`,
+		`http_response_bytes_total{procedure="unmatched"}`,
+		`http_requests_in_flight{procedure="unmatched"} 0`,
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected metrics to contain %q", line)
+		}
+	}
+	for _, path := range []string{"wp-login", "Unknown", "no.such.Service"} {
+		if strings.Contains(body, path) {
+			t.Errorf("expected no series labeled with %q", path)
+		}
+	}
+}
+
+func TestMetrics_RecordsResetStreams(t *testing.T) {
+	m := New()
+	client, srv := setupTestServer(t, m)
+	defer srv.Close()
+
+	stream, err := client.ServerStream(context.Background(), connect.NewRequest(&pb.ServerStreamRequest{
+		Message: "ping",
+		Count:   3,
+		Failure: &pb.StreamFailure{After: 1, RstStream: true},
+	}))
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	for stream.Receive() {
+	}
+	if stream.Err() == nil {
+		t.Fatal("expected the stream to be reset")
+	}
+	_ = stream.Close()
+
+	body := scrape(t, m)
+	for _, line := range []string{
+		`connect_server_active_streams{method="ServerStream",protocol="connect",service="echo.v1.Echo",type="server"} 0`,
+		`connect_server_handled_total{code="internal",method="ServerStream",protocol="connect",service="echo.v1.Echo",type="server"} 1`,
+		`connect_server_handling_seconds_count{method="ServerStream",protocol="connect",service="echo.v1.Echo",type="server"} 1`,
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected metrics to contain %q", line)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: admin_health.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ServingStatus mirrors grpc.health.v1.HealthCheckResponse.ServingStatus
+type ServingStatus int32
+
+const (
+	ServingStatus_SERVING_STATUS_UNKNOWN         ServingStatus = 0
+	ServingStatus_SERVING_STATUS_SERVING         ServingStatus = 1
+	ServingStatus_SERVING_STATUS_NOT_SERVING     ServingStatus = 2
+	ServingStatus_SERVING_STATUS_SERVICE_UNKNOWN ServingStatus = 3
+)
+
+// Enum value maps for ServingStatus.
+var (
+	ServingStatus_name = map[int32]string{
+		0: "SERVING_STATUS_UNKNOWN",
+		1: "SERVING_STATUS_SERVING",
+		2: "SERVING_STATUS_NOT_SERVING",
+		3: "SERVING_STATUS_SERVICE_UNKNOWN",
+	}
+	ServingStatus_value = map[string]int32{
+		"SERVING_STATUS_UNKNOWN":         0,
+		"SERVING_STATUS_SERVING":         1,
+		"SERVING_STATUS_NOT_SERVING":     2,
+		"SERVING_STATUS_SERVICE_UNKNOWN": 3,
+	}
+)
+
+func (x ServingStatus) Enum() *ServingStatus {
+	p := new(ServingStatus)
+	*p = x
+	return p
+}
+
+func (x ServingStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ServingStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_admin_health_proto_enumTypes[0].Descriptor()
+}
+
+func (ServingStatus) Type() protoreflect.EnumType {
+	return &file_admin_health_proto_enumTypes[0]
+}
+
+func (x ServingStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ServingStatus.Descriptor instead.
+func (ServingStatus) EnumDescriptor() ([]byte, []int) {
+	return file_admin_health_proto_rawDescGZIP(), []int{0}
+}
+
+type HealthStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        ServingStatus          `protobuf:"varint,1,opt,name=status,proto3,enum=echo.v1.ServingStatus" json:"status,omitempty"`
+	DurationMs    int32                  `protobuf:"varint,2,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"` // How long to hold the status before the next step
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthStep) Reset() {
+	*x = HealthStep{}
+	mi := &file_admin_health_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthStep) ProtoMessage() {}
+
+func (x *HealthStep) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_health_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthStep.ProtoReflect.Descriptor instead.
+func (*HealthStep) Descriptor() ([]byte, []int) {
+	return file_admin_health_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HealthStep) GetStatus() ServingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ServingStatus_SERVING_STATUS_UNKNOWN
+}
+
+func (x *HealthStep) GetDurationMs() int32 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+// SetHealth - Change the status reported by grpc.health.v1.Health
+type SetHealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`                           // Service name ("" = overall server status)
+	Status        ServingStatus          `protobuf:"varint,2,opt,name=status,proto3,enum=echo.v1.ServingStatus" json:"status,omitempty"` // Status to set now (ignored if schedule is set)
+	Schedule      []*HealthStep          `protobuf:"bytes,3,rep,name=schedule,proto3" json:"schedule,omitempty"`                         // Statuses to step through, e.g. to flap
+	Repeat        bool                   `protobuf:"varint,4,opt,name=repeat,proto3" json:"repeat,omitempty"`                            // Loop the schedule until the next SetHealth for the service
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetHealthRequest) Reset() {
+	*x = SetHealthRequest{}
+	mi := &file_admin_health_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetHealthRequest) ProtoMessage() {}
+
+func (x *SetHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_health_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetHealthRequest.ProtoReflect.Descriptor instead.
+func (*SetHealthRequest) Descriptor() ([]byte, []int) {
+	return file_admin_health_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetHealthRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *SetHealthRequest) GetStatus() ServingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ServingStatus_SERVING_STATUS_UNKNOWN
+}
+
+func (x *SetHealthRequest) GetSchedule() []*HealthStep {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+func (x *SetHealthRequest) GetRepeat() bool {
+	if x != nil {
+		return x.Repeat
+	}
+	return false
+}
+
+type SetHealthResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Service        string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	PreviousStatus ServingStatus          `protobuf:"varint,2,opt,name=previous_status,json=previousStatus,proto3,enum=echo.v1.ServingStatus" json:"previous_status,omitempty"`
+	Status         ServingStatus          `protobuf:"varint,3,opt,name=status,proto3,enum=echo.v1.ServingStatus" json:"status,omitempty"` // Status after the call (first step of a schedule)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetHealthResponse) Reset() {
+	*x = SetHealthResponse{}
+	mi := &file_admin_health_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetHealthResponse) ProtoMessage() {}
+
+func (x *SetHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_health_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetHealthResponse.ProtoReflect.Descriptor instead.
+func (*SetHealthResponse) Descriptor() ([]byte, []int) {
+	return file_admin_health_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetHealthResponse) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *SetHealthResponse) GetPreviousStatus() ServingStatus {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return ServingStatus_SERVING_STATUS_UNKNOWN
+}
+
+func (x *SetHealthResponse) GetStatus() ServingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ServingStatus_SERVING_STATUS_UNKNOWN
+}
+
+var File_admin_health_proto protoreflect.FileDescriptor
+
+const file_admin_health_proto_rawDesc = "" +
+	"\n" +
+	"\x12admin_health.proto\x12\aecho.v1\"]\n" +
+	"\n" +
+	"HealthStep\x12.\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x16.echo.v1.ServingStatusR\x06status\x12\x1f\n" +
+	"\vduration_ms\x18\x02 \x01(\x05R\n" +
+	"durationMs\"\xa5\x01\n" +
+	"\x10SetHealthRequest\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.echo.v1.ServingStatusR\x06status\x12/\n" +
+	"\bschedule\x18\x03 \x03(\v2\x13.echo.v1.HealthStepR\bschedule\x12\x16\n" +
+	"\x06repeat\x18\x04 \x01(\bR\x06repeat\"\x9e\x01\n" +
+	"\x11SetHealthResponse\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12?\n" +
+	"\x0fprevious_status\x18\x02 \x01(\x0e2\x16.echo.v1.ServingStatusR\x0epreviousStatus\x12.\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x16.echo.v1.ServingStatusR\x06status*\x8b\x01\n" +
+	"\rServingStatus\x12\x1a\n" +
+	"\x16SERVING_STATUS_UNKNOWN\x10\x00\x12\x1a\n" +
+	"\x16SERVING_STATUS_SERVING\x10\x01\x12\x1e\n" +
+	"\x1aSERVING_STATUS_NOT_SERVING\x10\x02\x12\"\n" +
+	"\x1eSERVING_STATUS_SERVICE_UNKNOWN\x10\x03B<Z:github.com/jsr-probitas/echo-servers/echo-connectrpc/protob\x06proto3"
+
+var (
+	file_admin_health_proto_rawDescOnce sync.Once
+	file_admin_health_proto_rawDescData []byte
+)
+
+func file_admin_health_proto_rawDescGZIP() []byte {
+	file_admin_health_proto_rawDescOnce.Do(func() {
+		file_admin_health_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_health_proto_rawDesc), len(file_admin_health_proto_rawDesc)))
+	})
+	return file_admin_health_proto_rawDescData
+}
+
+var file_admin_health_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_admin_health_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_admin_health_proto_goTypes = []any{
+	(ServingStatus)(0),        // 0: echo.v1.ServingStatus
+	(*HealthStep)(nil),        // 1: echo.v1.HealthStep
+	(*SetHealthRequest)(nil),  // 2: echo.v1.SetHealthRequest
+	(*SetHealthResponse)(nil), // 3: echo.v1.SetHealthResponse
+}
+var file_admin_health_proto_depIdxs = []int32{
+	0, // 0: echo.v1.HealthStep.status:type_name -> echo.v1.ServingStatus
+	0, // 1: echo.v1.SetHealthRequest.status:type_name -> echo.v1.ServingStatus
+	1, // 2: echo.v1.SetHealthRequest.schedule:type_name -> echo.v1.HealthStep
+	0, // 3: echo.v1.SetHealthResponse.previous_status:type_name -> echo.v1.ServingStatus
+	0, // 4: echo.v1.SetHealthResponse.status:type_name -> echo.v1.ServingStatus
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_admin_health_proto_init() }
+func file_admin_health_proto_init() {
+	if File_admin_health_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_health_proto_rawDesc), len(file_admin_health_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_admin_health_proto_goTypes,
+		DependencyIndexes: file_admin_health_proto_depIdxs,
+		EnumInfos:         file_admin_health_proto_enumTypes,
+		MessageInfos:      file_admin_health_proto_msgTypes,
+	}.Build()
+	File_admin_health_proto = out.File
+	file_admin_health_proto_goTypes = nil
+	file_admin_health_proto_depIdxs = nil
+}
@@ -0,0 +1,216 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: echo_compression.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// EchoCompression - Report how the call's messages were compressed
+type EchoCompressionRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Message            string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	ResponseCompressor string                 `protobuf:"bytes,2,opt,name=response_compressor,json=responseCompressor,proto3" json:"response_compressor,omitempty"` // Compressor for the response ("" = server default, "identity" = none)
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *EchoCompressionRequest) Reset() {
+	*x = EchoCompressionRequest{}
+	mi := &file_echo_compression_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EchoCompressionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EchoCompressionRequest) ProtoMessage() {}
+
+func (x *EchoCompressionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_compression_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EchoCompressionRequest.ProtoReflect.Descriptor instead.
+func (*EchoCompressionRequest) Descriptor() ([]byte, []int) {
+	return file_echo_compression_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EchoCompressionRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EchoCompressionRequest) GetResponseCompressor() string {
+	if x != nil {
+		return x.ResponseCompressor
+	}
+	return ""
+}
+
+type EchoCompressionResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Message            string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	RequestEncoding    string                 `protobuf:"bytes,2,opt,name=request_encoding,json=requestEncoding,proto3" json:"request_encoding,omitempty"`          // Compressor of the request message ("identity" = none)
+	AcceptEncodings    []string               `protobuf:"bytes,3,rep,name=accept_encodings,json=acceptEncodings,proto3" json:"accept_encodings,omitempty"`          // Compressors the client advertised it accepts
+	ResponseEncoding   string                 `protobuf:"bytes,4,opt,name=response_encoding,json=responseEncoding,proto3" json:"response_encoding,omitempty"`       // Compressor negotiated for responses ("identity" = none)
+	SupportedEncodings []string               `protobuf:"bytes,5,rep,name=supported_encodings,json=supportedEncodings,proto3" json:"supported_encodings,omitempty"` // Compressors registered on the server
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *EchoCompressionResponse) Reset() {
+	*x = EchoCompressionResponse{}
+	mi := &file_echo_compression_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EchoCompressionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EchoCompressionResponse) ProtoMessage() {}
+
+func (x *EchoCompressionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_compression_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EchoCompressionResponse.ProtoReflect.Descriptor instead.
+func (*EchoCompressionResponse) Descriptor() ([]byte, []int) {
+	return file_echo_compression_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EchoCompressionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EchoCompressionResponse) GetRequestEncoding() string {
+	if x != nil {
+		return x.RequestEncoding
+	}
+	return ""
+}
+
+func (x *EchoCompressionResponse) GetAcceptEncodings() []string {
+	if x != nil {
+		return x.AcceptEncodings
+	}
+	return nil
+}
+
+func (x *EchoCompressionResponse) GetResponseEncoding() string {
+	if x != nil {
+		return x.ResponseEncoding
+	}
+	return ""
+}
+
+func (x *EchoCompressionResponse) GetSupportedEncodings() []string {
+	if x != nil {
+		return x.SupportedEncodings
+	}
+	return nil
+}
+
+var File_echo_compression_proto protoreflect.FileDescriptor
+
+const file_echo_compression_proto_rawDesc = "" +
+	"\n" +
+	"\x16echo_compression.proto\x12\aecho.v1\"i\n" +
+	"\x16EchoCompressionRequest\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12/\n" +
+	"\x13response_compressor\x18\x02 \x01(\tR\x12responseCompressorJ\x04\b\x03\x10\x04\"\xe7\x01\n" +
+	"\x17EchoCompressionResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12)\n" +
+	"\x10request_encoding\x18\x02 \x01(\tR\x0frequestEncoding\x12)\n" +
+	"\x10accept_encodings\x18\x03 \x03(\tR\x0facceptEncodings\x12+\n" +
+	"\x11response_encoding\x18\x04 \x01(\tR\x10responseEncoding\x12/\n" +
+	"\x13supported_encodings\x18\x05 \x03(\tR\x12supportedEncodingsB<Z:github.com/jsr-probitas/echo-servers/echo-connectrpc/protob\x06proto3"
+
+var (
+	file_echo_compression_proto_rawDescOnce sync.Once
+	file_echo_compression_proto_rawDescData []byte
+)
+
+func file_echo_compression_proto_rawDescGZIP() []byte {
+	file_echo_compression_proto_rawDescOnce.Do(func() {
+		file_echo_compression_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_echo_compression_proto_rawDesc), len(file_echo_compression_proto_rawDesc)))
+	})
+	return file_echo_compression_proto_rawDescData
+}
+
+var file_echo_compression_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_echo_compression_proto_goTypes = []any{
+	(*EchoCompressionRequest)(nil),  // 0: echo.v1.EchoCompressionRequest
+	(*EchoCompressionResponse)(nil), // 1: echo.v1.EchoCompressionResponse
+}
+var file_echo_compression_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_echo_compression_proto_init() }
+func file_echo_compression_proto_init() {
+	if File_echo_compression_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_echo_compression_proto_rawDesc), len(file_echo_compression_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_echo_compression_proto_goTypes,
+		DependencyIndexes: file_echo_compression_proto_depIdxs,
+		MessageInfos:      file_echo_compression_proto_msgTypes,
+	}.Build()
+	File_echo_compression_proto = out.File
+	file_echo_compression_proto_goTypes = nil
+	file_echo_compression_proto_depIdxs = nil
+}
@@ -0,0 +1,227 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: echo_flaky.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// EchoFlaky - Return a scripted sequence of status codes for retry testing
+type EchoFlakyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SequenceId    string                 `protobuf:"bytes,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"` // Client-chosen key that groups attempts
+	Codes         []int32                `protobuf:"varint,2,rep,packed,name=codes,proto3" json:"codes,omitempty"`                     // Status code per attempt (0 = OK), last one repeats
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	TtlMs         int32                  `protobuf:"varint,4,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"` // Forget the sequence after this idle time (default 60000)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EchoFlakyRequest) Reset() {
+	*x = EchoFlakyRequest{}
+	mi := &file_echo_flaky_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EchoFlakyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EchoFlakyRequest) ProtoMessage() {}
+
+func (x *EchoFlakyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_flaky_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EchoFlakyRequest.ProtoReflect.Descriptor instead.
+func (*EchoFlakyRequest) Descriptor() ([]byte, []int) {
+	return file_echo_flaky_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EchoFlakyRequest) GetSequenceId() string {
+	if x != nil {
+		return x.SequenceId
+	}
+	return ""
+}
+
+func (x *EchoFlakyRequest) GetCodes() []int32 {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+func (x *EchoFlakyRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EchoFlakyRequest) GetTtlMs() int32 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+type EchoFlakyResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Message             string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	SequenceId          string                 `protobuf:"bytes,2,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
+	Attempt             int32                  `protobuf:"varint,3,opt,name=attempt,proto3" json:"attempt,omitempty"`                                                      // 1-based attempt number within the sequence
+	PreviousRpcAttempts int32                  `protobuf:"varint,4,opt,name=previous_rpc_attempts,json=previousRpcAttempts,proto3" json:"previous_rpc_attempts,omitempty"` // grpc-previous-rpc-attempts header value (0 if absent)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *EchoFlakyResponse) Reset() {
+	*x = EchoFlakyResponse{}
+	mi := &file_echo_flaky_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EchoFlakyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EchoFlakyResponse) ProtoMessage() {}
+
+func (x *EchoFlakyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_flaky_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EchoFlakyResponse.ProtoReflect.Descriptor instead.
+func (*EchoFlakyResponse) Descriptor() ([]byte, []int) {
+	return file_echo_flaky_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EchoFlakyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EchoFlakyResponse) GetSequenceId() string {
+	if x != nil {
+		return x.SequenceId
+	}
+	return ""
+}
+
+func (x *EchoFlakyResponse) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *EchoFlakyResponse) GetPreviousRpcAttempts() int32 {
+	if x != nil {
+		return x.PreviousRpcAttempts
+	}
+	return 0
+}
+
+var File_echo_flaky_proto protoreflect.FileDescriptor
+
+const file_echo_flaky_proto_rawDesc = "" +
+	"\n" +
+	"\x10echo_flaky.proto\x12\aecho.v1\"z\n" +
+	"\x10EchoFlakyRequest\x12\x1f\n" +
+	"\vsequence_id\x18\x01 \x01(\tR\n" +
+	"sequenceId\x12\x14\n" +
+	"\x05codes\x18\x02 \x03(\x05R\x05codes\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x15\n" +
+	"\x06ttl_ms\x18\x04 \x01(\x05R\x05ttlMs\"\x9c\x01\n" +
+	"\x11EchoFlakyResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1f\n" +
+	"\vsequence_id\x18\x02 \x01(\tR\n" +
+	"sequenceId\x12\x18\n" +
+	"\aattempt\x18\x03 \x01(\x05R\aattempt\x122\n" +
+	"\x15previous_rpc_attempts\x18\x04 \x01(\x05R\x13previousRpcAttemptsB<Z:github.com/jsr-probitas/echo-servers/echo-connectrpc/protob\x06proto3"
+
+var (
+	file_echo_flaky_proto_rawDescOnce sync.Once
+	file_echo_flaky_proto_rawDescData []byte
+)
+
+func file_echo_flaky_proto_rawDescGZIP() []byte {
+	file_echo_flaky_proto_rawDescOnce.Do(func() {
+		file_echo_flaky_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_echo_flaky_proto_rawDesc), len(file_echo_flaky_proto_rawDesc)))
+	})
+	return file_echo_flaky_proto_rawDescData
+}
+
+var file_echo_flaky_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_echo_flaky_proto_goTypes = []any{
+	(*EchoFlakyRequest)(nil),  // 0: echo.v1.EchoFlakyRequest
+	(*EchoFlakyResponse)(nil), // 1: echo.v1.EchoFlakyResponse
+}
+var file_echo_flaky_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_echo_flaky_proto_init() }
+func file_echo_flaky_proto_init() {
+	if File_echo_flaky_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_echo_flaky_proto_rawDesc), len(file_echo_flaky_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_echo_flaky_proto_goTypes,
+		DependencyIndexes: file_echo_flaky_proto_depIdxs,
+		MessageInfos:      file_echo_flaky_proto_msgTypes,
+	}.Build()
+	File_echo_flaky_proto = out.File
+	file_echo_flaky_proto_goTypes = nil
+	file_echo_flaky_proto_depIdxs = nil
+}
@@ -0,0 +1,111 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: admin.proto
+
+package protoconnect
+
+import (
+	context "context"
+	errors "errors"
+	http "net/http"
+	strings "strings"
+
+	connect "connectrpc.com/connect"
+	proto "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AdminName is the fully-qualified name of the Admin service.
+	AdminName = "echo.v1.Admin"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AdminSetHealthProcedure is the fully-qualified name of the Admin's SetHealth RPC.
+	AdminSetHealthProcedure = "/echo.v1.Admin/SetHealth"
+)
+
+// AdminClient is a client for the echo.v1.Admin service.
+type AdminClient interface {
+	// Health RPCs
+	SetHealth(context.Context, *connect.Request[proto.SetHealthRequest]) (*connect.Response[proto.SetHealthResponse], error)
+}
+
+// NewAdminClient constructs a client for the echo.v1.Admin service. By default, it uses the Connect
+// protocol with the binary Protobuf Codec, asks for gzipped responses, and sends uncompressed
+// requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAdminClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AdminClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	adminMethods := proto.File_admin_proto.Services().ByName("Admin").Methods()
+	return &adminClient{
+		setHealth: connect.NewClient[proto.SetHealthRequest, proto.SetHealthResponse](
+			httpClient,
+			baseURL+AdminSetHealthProcedure,
+			connect.WithSchema(adminMethods.ByName("SetHealth")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// adminClient implements AdminClient.
+type adminClient struct {
+	setHealth *connect.Client[proto.SetHealthRequest, proto.SetHealthResponse]
+}
+
+// SetHealth calls echo.v1.Admin.SetHealth.
+func (c *adminClient) SetHealth(ctx context.Context, req *connect.Request[proto.SetHealthRequest]) (*connect.Response[proto.SetHealthResponse], error) {
+	return c.setHealth.CallUnary(ctx, req)
+}
+
+// AdminHandler is an implementation of the echo.v1.Admin service.
+type AdminHandler interface {
+	// Health RPCs
+	SetHealth(context.Context, *connect.Request[proto.SetHealthRequest]) (*connect.Response[proto.SetHealthResponse], error)
+}
+
+// NewAdminHandler builds an HTTP handler from the service implementation. It returns the path on
+// which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAdminHandler(svc AdminHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	adminMethods := proto.File_admin_proto.Services().ByName("Admin").Methods()
+	adminSetHealthHandler := connect.NewUnaryHandler(
+		AdminSetHealthProcedure,
+		svc.SetHealth,
+		connect.WithSchema(adminMethods.ByName("SetHealth")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/echo.v1.Admin/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AdminSetHealthProcedure:
+			adminSetHealthHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAdminHandler returns CodeUnimplemented from all methods.
+type UnimplementedAdminHandler struct{}
+
+func (UnimplementedAdminHandler) SetHealth(context.Context, *connect.Request[proto.SetHealthRequest]) (*connect.Response[proto.SetHealthResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Admin.SetHealth is not implemented"))
+}
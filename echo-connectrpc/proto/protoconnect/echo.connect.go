@@ -45,13 +45,19 @@ const (
 	EchoEchoRequestMetadataProcedure = "/echo.v1.Echo/EchoRequestMetadata"
 	// EchoEchoWithTrailersProcedure is the fully-qualified name of the Echo's EchoWithTrailers RPC.
 	EchoEchoWithTrailersProcedure = "/echo.v1.Echo/EchoWithTrailers"
+	// EchoEchoWithMetadataProcedure is the fully-qualified name of the Echo's EchoWithMetadata RPC.
+	EchoEchoWithMetadataProcedure = "/echo.v1.Echo/EchoWithMetadata"
 	// EchoEchoLargePayloadProcedure is the fully-qualified name of the Echo's EchoLargePayload RPC.
 	EchoEchoLargePayloadProcedure = "/echo.v1.Echo/EchoLargePayload"
+	// EchoEchoCompressionProcedure is the fully-qualified name of the Echo's EchoCompression RPC.
+	EchoEchoCompressionProcedure = "/echo.v1.Echo/EchoCompression"
 	// EchoEchoDeadlineProcedure is the fully-qualified name of the Echo's EchoDeadline RPC.
 	EchoEchoDeadlineProcedure = "/echo.v1.Echo/EchoDeadline"
 	// EchoEchoErrorWithDetailsProcedure is the fully-qualified name of the Echo's EchoErrorWithDetails
 	// RPC.
 	EchoEchoErrorWithDetailsProcedure = "/echo.v1.Echo/EchoErrorWithDetails"
+	// EchoEchoFlakyProcedure is the fully-qualified name of the Echo's EchoFlaky RPC.
+	EchoEchoFlakyProcedure = "/echo.v1.Echo/EchoFlaky"
 	// EchoServerStreamProcedure is the fully-qualified name of the Echo's ServerStream RPC.
 	EchoServerStreamProcedure = "/echo.v1.Echo/ServerStream"
 	// EchoClientStreamProcedure is the fully-qualified name of the Echo's ClientStream RPC.
@@ -59,6 +65,10 @@ const (
 	// EchoBidirectionalStreamProcedure is the fully-qualified name of the Echo's BidirectionalStream
 	// RPC.
 	EchoBidirectionalStreamProcedure = "/echo.v1.Echo/BidirectionalStream"
+	// EchoFloodStreamProcedure is the fully-qualified name of the Echo's FloodStream RPC.
+	EchoFloodStreamProcedure = "/echo.v1.Echo/FloodStream"
+	// EchoSlowReadStreamProcedure is the fully-qualified name of the Echo's SlowReadStream RPC.
+	EchoSlowReadStreamProcedure = "/echo.v1.Echo/SlowReadStream"
 )
 
 // EchoClient is a client for the echo.v1.Echo service.
@@ -70,16 +80,24 @@ type EchoClient interface {
 	// Metadata/Headers RPCs
 	EchoRequestMetadata(context.Context, *connect.Request[proto.EchoRequestMetadataRequest]) (*connect.Response[proto.EchoRequestMetadataResponse], error)
 	EchoWithTrailers(context.Context, *connect.Request[proto.EchoWithTrailersRequest]) (*connect.Response[proto.EchoResponse], error)
+	EchoWithMetadata(context.Context, *connect.Request[proto.EchoWithMetadataRequest]) (*connect.Response[proto.EchoResponse], error)
 	// Payload Testing RPCs
 	EchoLargePayload(context.Context, *connect.Request[proto.EchoLargePayloadRequest]) (*connect.Response[proto.EchoLargePayloadResponse], error)
+	// Compression Testing RPCs
+	EchoCompression(context.Context, *connect.Request[proto.EchoCompressionRequest]) (*connect.Response[proto.EchoCompressionResponse], error)
 	// Deadline/Timeout RPCs
 	EchoDeadline(context.Context, *connect.Request[proto.EchoDeadlineRequest]) (*connect.Response[proto.EchoDeadlineResponse], error)
 	// Error Scenarios RPCs
 	EchoErrorWithDetails(context.Context, *connect.Request[proto.EchoErrorWithDetailsRequest]) (*connect.Response[proto.EchoResponse], error)
+	// Retry Testing RPCs
+	EchoFlaky(context.Context, *connect.Request[proto.EchoFlakyRequest]) (*connect.Response[proto.EchoFlakyResponse], error)
 	// Streaming RPCs
 	ServerStream(context.Context, *connect.Request[proto.ServerStreamRequest]) (*connect.ServerStreamForClient[proto.EchoResponse], error)
 	ClientStream(context.Context) *connect.ClientStreamForClient[proto.EchoRequest, proto.EchoResponse]
 	BidirectionalStream(context.Context) *connect.BidiStreamForClient[proto.EchoRequest, proto.EchoResponse]
+	// Flow Control Testing RPCs
+	FloodStream(context.Context, *connect.Request[proto.FloodRequest]) (*connect.ServerStreamForClient[proto.FloodResponse], error)
+	SlowReadStream(context.Context) *connect.BidiStreamForClient[proto.SlowReadRequest, proto.SlowReadResponse]
 }
 
 // NewEchoClient constructs a client for the echo.v1.Echo service. By default, it uses the Connect
@@ -97,48 +115,76 @@ func NewEchoClient(httpClient connect.HTTPClient, baseURL string, opts ...connec
 			httpClient,
 			baseURL+EchoEchoProcedure,
 			connect.WithSchema(echoMethods.ByName("Echo")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		echoWithDelay: connect.NewClient[proto.EchoWithDelayRequest, proto.EchoResponse](
 			httpClient,
 			baseURL+EchoEchoWithDelayProcedure,
 			connect.WithSchema(echoMethods.ByName("EchoWithDelay")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		echoError: connect.NewClient[proto.EchoErrorRequest, proto.EchoResponse](
 			httpClient,
 			baseURL+EchoEchoErrorProcedure,
 			connect.WithSchema(echoMethods.ByName("EchoError")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		echoRequestMetadata: connect.NewClient[proto.EchoRequestMetadataRequest, proto.EchoRequestMetadataResponse](
 			httpClient,
 			baseURL+EchoEchoRequestMetadataProcedure,
 			connect.WithSchema(echoMethods.ByName("EchoRequestMetadata")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		echoWithTrailers: connect.NewClient[proto.EchoWithTrailersRequest, proto.EchoResponse](
 			httpClient,
 			baseURL+EchoEchoWithTrailersProcedure,
 			connect.WithSchema(echoMethods.ByName("EchoWithTrailers")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		echoWithMetadata: connect.NewClient[proto.EchoWithMetadataRequest, proto.EchoResponse](
+			httpClient,
+			baseURL+EchoEchoWithMetadataProcedure,
+			connect.WithSchema(echoMethods.ByName("EchoWithMetadata")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		echoLargePayload: connect.NewClient[proto.EchoLargePayloadRequest, proto.EchoLargePayloadResponse](
 			httpClient,
 			baseURL+EchoEchoLargePayloadProcedure,
 			connect.WithSchema(echoMethods.ByName("EchoLargePayload")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		echoCompression: connect.NewClient[proto.EchoCompressionRequest, proto.EchoCompressionResponse](
+			httpClient,
+			baseURL+EchoEchoCompressionProcedure,
+			connect.WithSchema(echoMethods.ByName("EchoCompression")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		echoDeadline: connect.NewClient[proto.EchoDeadlineRequest, proto.EchoDeadlineResponse](
 			httpClient,
 			baseURL+EchoEchoDeadlineProcedure,
 			connect.WithSchema(echoMethods.ByName("EchoDeadline")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		echoErrorWithDetails: connect.NewClient[proto.EchoErrorWithDetailsRequest, proto.EchoResponse](
 			httpClient,
 			baseURL+EchoEchoErrorWithDetailsProcedure,
 			connect.WithSchema(echoMethods.ByName("EchoErrorWithDetails")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		echoFlaky: connect.NewClient[proto.EchoFlakyRequest, proto.EchoFlakyResponse](
+			httpClient,
+			baseURL+EchoEchoFlakyProcedure,
+			connect.WithSchema(echoMethods.ByName("EchoFlaky")),
 			connect.WithClientOptions(opts...),
 		),
 		serverStream: connect.NewClient[proto.ServerStreamRequest, proto.EchoResponse](
@@ -159,6 +205,18 @@ func NewEchoClient(httpClient connect.HTTPClient, baseURL string, opts ...connec
 			connect.WithSchema(echoMethods.ByName("BidirectionalStream")),
 			connect.WithClientOptions(opts...),
 		),
+		floodStream: connect.NewClient[proto.FloodRequest, proto.FloodResponse](
+			httpClient,
+			baseURL+EchoFloodStreamProcedure,
+			connect.WithSchema(echoMethods.ByName("FloodStream")),
+			connect.WithClientOptions(opts...),
+		),
+		slowReadStream: connect.NewClient[proto.SlowReadRequest, proto.SlowReadResponse](
+			httpClient,
+			baseURL+EchoSlowReadStreamProcedure,
+			connect.WithSchema(echoMethods.ByName("SlowReadStream")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -169,12 +227,17 @@ type echoClient struct {
 	echoError            *connect.Client[proto.EchoErrorRequest, proto.EchoResponse]
 	echoRequestMetadata  *connect.Client[proto.EchoRequestMetadataRequest, proto.EchoRequestMetadataResponse]
 	echoWithTrailers     *connect.Client[proto.EchoWithTrailersRequest, proto.EchoResponse]
+	echoWithMetadata     *connect.Client[proto.EchoWithMetadataRequest, proto.EchoResponse]
 	echoLargePayload     *connect.Client[proto.EchoLargePayloadRequest, proto.EchoLargePayloadResponse]
+	echoCompression      *connect.Client[proto.EchoCompressionRequest, proto.EchoCompressionResponse]
 	echoDeadline         *connect.Client[proto.EchoDeadlineRequest, proto.EchoDeadlineResponse]
 	echoErrorWithDetails *connect.Client[proto.EchoErrorWithDetailsRequest, proto.EchoResponse]
+	echoFlaky            *connect.Client[proto.EchoFlakyRequest, proto.EchoFlakyResponse]
 	serverStream         *connect.Client[proto.ServerStreamRequest, proto.EchoResponse]
 	clientStream         *connect.Client[proto.EchoRequest, proto.EchoResponse]
 	bidirectionalStream  *connect.Client[proto.EchoRequest, proto.EchoResponse]
+	floodStream          *connect.Client[proto.FloodRequest, proto.FloodResponse]
+	slowReadStream       *connect.Client[proto.SlowReadRequest, proto.SlowReadResponse]
 }
 
 // Echo calls echo.v1.Echo.Echo.
@@ -202,11 +265,21 @@ func (c *echoClient) EchoWithTrailers(ctx context.Context, req *connect.Request[
 	return c.echoWithTrailers.CallUnary(ctx, req)
 }
 
+// EchoWithMetadata calls echo.v1.Echo.EchoWithMetadata.
+func (c *echoClient) EchoWithMetadata(ctx context.Context, req *connect.Request[proto.EchoWithMetadataRequest]) (*connect.Response[proto.EchoResponse], error) {
+	return c.echoWithMetadata.CallUnary(ctx, req)
+}
+
 // EchoLargePayload calls echo.v1.Echo.EchoLargePayload.
 func (c *echoClient) EchoLargePayload(ctx context.Context, req *connect.Request[proto.EchoLargePayloadRequest]) (*connect.Response[proto.EchoLargePayloadResponse], error) {
 	return c.echoLargePayload.CallUnary(ctx, req)
 }
 
+// EchoCompression calls echo.v1.Echo.EchoCompression.
+func (c *echoClient) EchoCompression(ctx context.Context, req *connect.Request[proto.EchoCompressionRequest]) (*connect.Response[proto.EchoCompressionResponse], error) {
+	return c.echoCompression.CallUnary(ctx, req)
+}
+
 // EchoDeadline calls echo.v1.Echo.EchoDeadline.
 func (c *echoClient) EchoDeadline(ctx context.Context, req *connect.Request[proto.EchoDeadlineRequest]) (*connect.Response[proto.EchoDeadlineResponse], error) {
 	return c.echoDeadline.CallUnary(ctx, req)
@@ -217,6 +290,11 @@ func (c *echoClient) EchoErrorWithDetails(ctx context.Context, req *connect.Requ
 	return c.echoErrorWithDetails.CallUnary(ctx, req)
 }
 
+// EchoFlaky calls echo.v1.Echo.EchoFlaky.
+func (c *echoClient) EchoFlaky(ctx context.Context, req *connect.Request[proto.EchoFlakyRequest]) (*connect.Response[proto.EchoFlakyResponse], error) {
+	return c.echoFlaky.CallUnary(ctx, req)
+}
+
 // ServerStream calls echo.v1.Echo.ServerStream.
 func (c *echoClient) ServerStream(ctx context.Context, req *connect.Request[proto.ServerStreamRequest]) (*connect.ServerStreamForClient[proto.EchoResponse], error) {
 	return c.serverStream.CallServerStream(ctx, req)
@@ -232,6 +310,16 @@ func (c *echoClient) BidirectionalStream(ctx context.Context) *connect.BidiStrea
 	return c.bidirectionalStream.CallBidiStream(ctx)
 }
 
+// FloodStream calls echo.v1.Echo.FloodStream.
+func (c *echoClient) FloodStream(ctx context.Context, req *connect.Request[proto.FloodRequest]) (*connect.ServerStreamForClient[proto.FloodResponse], error) {
+	return c.floodStream.CallServerStream(ctx, req)
+}
+
+// SlowReadStream calls echo.v1.Echo.SlowReadStream.
+func (c *echoClient) SlowReadStream(ctx context.Context) *connect.BidiStreamForClient[proto.SlowReadRequest, proto.SlowReadResponse] {
+	return c.slowReadStream.CallBidiStream(ctx)
+}
+
 // EchoHandler is an implementation of the echo.v1.Echo service.
 type EchoHandler interface {
 	// Unary RPCs
@@ -241,16 +329,24 @@ type EchoHandler interface {
 	// Metadata/Headers RPCs
 	EchoRequestMetadata(context.Context, *connect.Request[proto.EchoRequestMetadataRequest]) (*connect.Response[proto.EchoRequestMetadataResponse], error)
 	EchoWithTrailers(context.Context, *connect.Request[proto.EchoWithTrailersRequest]) (*connect.Response[proto.EchoResponse], error)
+	EchoWithMetadata(context.Context, *connect.Request[proto.EchoWithMetadataRequest]) (*connect.Response[proto.EchoResponse], error)
 	// Payload Testing RPCs
 	EchoLargePayload(context.Context, *connect.Request[proto.EchoLargePayloadRequest]) (*connect.Response[proto.EchoLargePayloadResponse], error)
+	// Compression Testing RPCs
+	EchoCompression(context.Context, *connect.Request[proto.EchoCompressionRequest]) (*connect.Response[proto.EchoCompressionResponse], error)
 	// Deadline/Timeout RPCs
 	EchoDeadline(context.Context, *connect.Request[proto.EchoDeadlineRequest]) (*connect.Response[proto.EchoDeadlineResponse], error)
 	// Error Scenarios RPCs
 	EchoErrorWithDetails(context.Context, *connect.Request[proto.EchoErrorWithDetailsRequest]) (*connect.Response[proto.EchoResponse], error)
+	// Retry Testing RPCs
+	EchoFlaky(context.Context, *connect.Request[proto.EchoFlakyRequest]) (*connect.Response[proto.EchoFlakyResponse], error)
 	// Streaming RPCs
 	ServerStream(context.Context, *connect.Request[proto.ServerStreamRequest], *connect.ServerStream[proto.EchoResponse]) error
 	ClientStream(context.Context, *connect.ClientStream[proto.EchoRequest]) (*connect.Response[proto.EchoResponse], error)
 	BidirectionalStream(context.Context, *connect.BidiStream[proto.EchoRequest, proto.EchoResponse]) error
+	// Flow Control Testing RPCs
+	FloodStream(context.Context, *connect.Request[proto.FloodRequest], *connect.ServerStream[proto.FloodResponse]) error
+	SlowReadStream(context.Context, *connect.BidiStream[proto.SlowReadRequest, proto.SlowReadResponse]) error
 }
 
 // NewEchoHandler builds an HTTP handler from the service implementation. It returns the path on
@@ -264,48 +360,76 @@ func NewEchoHandler(svc EchoHandler, opts ...connect.HandlerOption) (string, htt
 		EchoEchoProcedure,
 		svc.Echo,
 		connect.WithSchema(echoMethods.ByName("Echo")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	echoEchoWithDelayHandler := connect.NewUnaryHandler(
 		EchoEchoWithDelayProcedure,
 		svc.EchoWithDelay,
 		connect.WithSchema(echoMethods.ByName("EchoWithDelay")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	echoEchoErrorHandler := connect.NewUnaryHandler(
 		EchoEchoErrorProcedure,
 		svc.EchoError,
 		connect.WithSchema(echoMethods.ByName("EchoError")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	echoEchoRequestMetadataHandler := connect.NewUnaryHandler(
 		EchoEchoRequestMetadataProcedure,
 		svc.EchoRequestMetadata,
 		connect.WithSchema(echoMethods.ByName("EchoRequestMetadata")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	echoEchoWithTrailersHandler := connect.NewUnaryHandler(
 		EchoEchoWithTrailersProcedure,
 		svc.EchoWithTrailers,
 		connect.WithSchema(echoMethods.ByName("EchoWithTrailers")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	echoEchoWithMetadataHandler := connect.NewUnaryHandler(
+		EchoEchoWithMetadataProcedure,
+		svc.EchoWithMetadata,
+		connect.WithSchema(echoMethods.ByName("EchoWithMetadata")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	echoEchoLargePayloadHandler := connect.NewUnaryHandler(
 		EchoEchoLargePayloadProcedure,
 		svc.EchoLargePayload,
 		connect.WithSchema(echoMethods.ByName("EchoLargePayload")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	echoEchoCompressionHandler := connect.NewUnaryHandler(
+		EchoEchoCompressionProcedure,
+		svc.EchoCompression,
+		connect.WithSchema(echoMethods.ByName("EchoCompression")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	echoEchoDeadlineHandler := connect.NewUnaryHandler(
 		EchoEchoDeadlineProcedure,
 		svc.EchoDeadline,
 		connect.WithSchema(echoMethods.ByName("EchoDeadline")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	echoEchoErrorWithDetailsHandler := connect.NewUnaryHandler(
 		EchoEchoErrorWithDetailsProcedure,
 		svc.EchoErrorWithDetails,
 		connect.WithSchema(echoMethods.ByName("EchoErrorWithDetails")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	echoEchoFlakyHandler := connect.NewUnaryHandler(
+		EchoEchoFlakyProcedure,
+		svc.EchoFlaky,
+		connect.WithSchema(echoMethods.ByName("EchoFlaky")),
 		connect.WithHandlerOptions(opts...),
 	)
 	echoServerStreamHandler := connect.NewServerStreamHandler(
@@ -326,6 +450,18 @@ func NewEchoHandler(svc EchoHandler, opts ...connect.HandlerOption) (string, htt
 		connect.WithSchema(echoMethods.ByName("BidirectionalStream")),
 		connect.WithHandlerOptions(opts...),
 	)
+	echoFloodStreamHandler := connect.NewServerStreamHandler(
+		EchoFloodStreamProcedure,
+		svc.FloodStream,
+		connect.WithSchema(echoMethods.ByName("FloodStream")),
+		connect.WithHandlerOptions(opts...),
+	)
+	echoSlowReadStreamHandler := connect.NewBidiStreamHandler(
+		EchoSlowReadStreamProcedure,
+		svc.SlowReadStream,
+		connect.WithSchema(echoMethods.ByName("SlowReadStream")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/echo.v1.Echo/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case EchoEchoProcedure:
@@ -338,18 +474,28 @@ func NewEchoHandler(svc EchoHandler, opts ...connect.HandlerOption) (string, htt
 			echoEchoRequestMetadataHandler.ServeHTTP(w, r)
 		case EchoEchoWithTrailersProcedure:
 			echoEchoWithTrailersHandler.ServeHTTP(w, r)
+		case EchoEchoWithMetadataProcedure:
+			echoEchoWithMetadataHandler.ServeHTTP(w, r)
 		case EchoEchoLargePayloadProcedure:
 			echoEchoLargePayloadHandler.ServeHTTP(w, r)
+		case EchoEchoCompressionProcedure:
+			echoEchoCompressionHandler.ServeHTTP(w, r)
 		case EchoEchoDeadlineProcedure:
 			echoEchoDeadlineHandler.ServeHTTP(w, r)
 		case EchoEchoErrorWithDetailsProcedure:
 			echoEchoErrorWithDetailsHandler.ServeHTTP(w, r)
+		case EchoEchoFlakyProcedure:
+			echoEchoFlakyHandler.ServeHTTP(w, r)
 		case EchoServerStreamProcedure:
 			echoServerStreamHandler.ServeHTTP(w, r)
 		case EchoClientStreamProcedure:
 			echoClientStreamHandler.ServeHTTP(w, r)
 		case EchoBidirectionalStreamProcedure:
 			echoBidirectionalStreamHandler.ServeHTTP(w, r)
+		case EchoFloodStreamProcedure:
+			echoFloodStreamHandler.ServeHTTP(w, r)
+		case EchoSlowReadStreamProcedure:
+			echoSlowReadStreamHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -379,10 +525,18 @@ func (UnimplementedEchoHandler) EchoWithTrailers(context.Context, *connect.Reque
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.EchoWithTrailers is not implemented"))
 }
 
+func (UnimplementedEchoHandler) EchoWithMetadata(context.Context, *connect.Request[proto.EchoWithMetadataRequest]) (*connect.Response[proto.EchoResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.EchoWithMetadata is not implemented"))
+}
+
 func (UnimplementedEchoHandler) EchoLargePayload(context.Context, *connect.Request[proto.EchoLargePayloadRequest]) (*connect.Response[proto.EchoLargePayloadResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.EchoLargePayload is not implemented"))
 }
 
+func (UnimplementedEchoHandler) EchoCompression(context.Context, *connect.Request[proto.EchoCompressionRequest]) (*connect.Response[proto.EchoCompressionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.EchoCompression is not implemented"))
+}
+
 func (UnimplementedEchoHandler) EchoDeadline(context.Context, *connect.Request[proto.EchoDeadlineRequest]) (*connect.Response[proto.EchoDeadlineResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.EchoDeadline is not implemented"))
 }
@@ -391,6 +545,10 @@ func (UnimplementedEchoHandler) EchoErrorWithDetails(context.Context, *connect.R
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.EchoErrorWithDetails is not implemented"))
 }
 
+func (UnimplementedEchoHandler) EchoFlaky(context.Context, *connect.Request[proto.EchoFlakyRequest]) (*connect.Response[proto.EchoFlakyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.EchoFlaky is not implemented"))
+}
+
 func (UnimplementedEchoHandler) ServerStream(context.Context, *connect.Request[proto.ServerStreamRequest], *connect.ServerStream[proto.EchoResponse]) error {
 	return connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.ServerStream is not implemented"))
 }
@@ -402,3 +560,11 @@ func (UnimplementedEchoHandler) ClientStream(context.Context, *connect.ClientStr
 func (UnimplementedEchoHandler) BidirectionalStream(context.Context, *connect.BidiStream[proto.EchoRequest, proto.EchoResponse]) error {
 	return connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.BidirectionalStream is not implemented"))
 }
+
+func (UnimplementedEchoHandler) FloodStream(context.Context, *connect.Request[proto.FloodRequest], *connect.ServerStream[proto.FloodResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.FloodStream is not implemented"))
+}
+
+func (UnimplementedEchoHandler) SlowReadStream(context.Context, *connect.BidiStream[proto.SlowReadRequest, proto.SlowReadResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("echo.v1.Echo.SlowReadStream is not implemented"))
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+)
+
+// maxHealthStepDuration caps how long a single SetHealth step may last.
+const maxHealthStepDuration = time.Hour
+
+// AdminServer implements echo.v1.Admin for changing server behavior at runtime.
+type AdminServer struct {
+	protoconnect.UnimplementedAdminHandler
+	health *HealthServer
+}
+
+// NewAdminServer creates an Admin service that controls health.
+func NewAdminServer(health *HealthServer) *AdminServer {
+	return &AdminServer{health: health}
+}
+
+func (s *AdminServer) SetHealth(_ context.Context, req *connect.Request[pb.SetHealthRequest]) (*connect.Response[pb.SetHealthResponse], error) {
+	steps, err := healthSteps(req.Msg)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	previous, err := s.health.Schedule(req.Msg.Service, steps, req.Msg.Repeat)
+	if errors.Is(err, ErrHealthShutdown) {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+
+	return connect.NewResponse(&pb.SetHealthResponse{
+		Service:        req.Msg.Service,
+		PreviousStatus: pb.ServingStatus(previous),
+		Status:         pb.ServingStatus(steps[0].Status),
+	}), nil
+}
+
+// healthSteps converts a SetHealth request into schedule steps. A request
+// without a schedule becomes a single step holding status.
+func healthSteps(req *pb.SetHealthRequest) ([]HealthStep, error) {
+	if len(req.Schedule) == 0 {
+		if req.Repeat {
+			return nil, errors.New("repeat requires a schedule")
+		}
+		if _, ok := pb.ServingStatus_name[int32(req.Status)]; !ok {
+			return nil, errors.New("unknown status")
+		}
+		return []HealthStep{{Status: healthpb.HealthCheckResponse_ServingStatus(req.Status)}}, nil
+	}
+
+	steps := make([]HealthStep, 0, len(req.Schedule))
+	for _, step := range req.Schedule {
+		if _, ok := pb.ServingStatus_name[int32(step.Status)]; !ok {
+			return nil, errors.New("unknown status in schedule")
+		}
+		duration := time.Duration(step.DurationMs) * time.Millisecond
+		if duration < 0 || duration > maxHealthStepDuration {
+			return nil, errors.New("duration_ms must be between 0 and 3600000")
+		}
+		// A zero-length step would make a repeating schedule spin
+		if req.Repeat && duration == 0 {
+			return nil, errors.New("duration_ms must be positive for every step of a repeating schedule")
+		}
+		steps = append(steps, HealthStep{
+			Status:   healthpb.HealthCheckResponse_ServingStatus(step.Status),
+			Duration: duration,
+		})
+	}
+	return steps, nil
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+)
+
+type healthClients struct {
+	check *connect.Client[healthpb.HealthCheckRequest, healthpb.HealthCheckResponse]
+	watch *connect.Client[healthpb.HealthCheckRequest, healthpb.HealthCheckResponse]
+}
+
+func setupAdminTestServer(t *testing.T) (protoconnect.AdminClient, healthClients, *HealthServer, *httptest.Server) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	healthServer := NewHealthServer()
+	mux.Handle(NewHealthHandler(healthServer))
+	mux.Handle(protoconnect.NewAdminHandler(NewAdminServer(healthServer)))
+
+	server := httptest.NewServer(mux)
+	clients := healthClients{
+		check: connect.NewClient[healthpb.HealthCheckRequest, healthpb.HealthCheckResponse](http.DefaultClient, server.URL+healthCheckProcedure),
+		watch: connect.NewClient[healthpb.HealthCheckRequest, healthpb.HealthCheckResponse](http.DefaultClient, server.URL+healthWatchProcedure),
+	}
+
+	return protoconnect.NewAdminClient(http.DefaultClient, server.URL), clients, healthServer, server
+}
+
+func TestAdmin_SetHealth(t *testing.T) {
+	admin, health, _, server := setupAdminTestServer(t)
+	defer server.Close()
+
+	resp, err := admin.SetHealth(context.Background(), connect.NewRequest(&pb.SetHealthRequest{
+		Service: "echo.v1.Echo",
+		Status:  pb.ServingStatus_SERVING_STATUS_NOT_SERVING,
+	}))
+	if err != nil {
+		t.Fatalf("SetHealth failed: %v", err)
+	}
+	if resp.Msg.PreviousStatus != pb.ServingStatus_SERVING_STATUS_SERVING {
+		t.Errorf("expected previous status SERVING, got %v", resp.Msg.PreviousStatus)
+	}
+
+	check, err := health.check.CallUnary(context.Background(), connect.NewRequest(&healthpb.HealthCheckRequest{Service: "echo.v1.Echo"}))
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if check.Msg.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING, got %v", check.Msg.Status)
+	}
+
+	_, err = health.check.CallUnary(context.Background(), connect.NewRequest(&healthpb.HealthCheckRequest{Service: "unknown"}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("expected NotFound for unknown service, got %v", err)
+	}
+}
+
+func TestAdmin_SetHealth_WatchSeesFlapping(t *testing.T) {
+	admin, health, _, server := setupAdminTestServer(t)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := health.watch.CallServerStream(ctx, connect.NewRequest(&healthpb.HealthCheckRequest{Service: "flappy"}))
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	// Unknown services are reported as SERVICE_UNKNOWN until they are set
+	if !stream.Receive() {
+		t.Fatalf("Receive failed: %v", stream.Err())
+	}
+	if stream.Msg().Status != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Fatalf("expected SERVICE_UNKNOWN, got %v", stream.Msg().Status)
+	}
+
+	_, err = admin.SetHealth(ctx, connect.NewRequest(&pb.SetHealthRequest{
+		Service: "flappy",
+		Schedule: []*pb.HealthStep{
+			{Status: pb.ServingStatus_SERVING_STATUS_SERVING, DurationMs: 20},
+			{Status: pb.ServingStatus_SERVING_STATUS_NOT_SERVING, DurationMs: 20},
+		},
+		Repeat: true,
+	}))
+	if err != nil {
+		t.Fatalf("SetHealth failed: %v", err)
+	}
+
+	expected := []healthpb.HealthCheckResponse_ServingStatus{
+		healthpb.HealthCheckResponse_SERVING,
+		healthpb.HealthCheckResponse_NOT_SERVING,
+		healthpb.HealthCheckResponse_SERVING,
+		healthpb.HealthCheckResponse_NOT_SERVING,
+	}
+	for i, want := range expected {
+		if !stream.Receive() {
+			t.Fatalf("Receive %d failed: %v", i, stream.Err())
+		}
+		if stream.Msg().Status != want {
+			t.Errorf("transition %d: expected %v, got %v", i, want, stream.Msg().Status)
+		}
+	}
+}
+
+func TestAdmin_SetHealth_InvalidArgument(t *testing.T) {
+	admin, _, _, server := setupAdminTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		req  *pb.SetHealthRequest
+	}{
+		{
+			name: "unknown status",
+			req:  &pb.SetHealthRequest{Status: 42},
+		},
+		{
+			name: "repeat without schedule",
+			req:  &pb.SetHealthRequest{Repeat: true},
+		},
+		{
+			name: "zero duration in repeating schedule",
+			req: &pb.SetHealthRequest{
+				Schedule: []*pb.HealthStep{{Status: pb.ServingStatus_SERVING_STATUS_SERVING}},
+				Repeat:   true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := admin.SetHealth(context.Background(), connect.NewRequest(tt.req))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAdmin_SetHealth_AfterShutdown(t *testing.T) {
+	admin, _, healthServer, server := setupAdminTestServer(t)
+	defer server.Close()
+
+	healthServer.Shutdown()
+
+	_, err := admin.SetHealth(context.Background(), connect.NewRequest(&pb.SetHealthRequest{
+		Status: pb.ServingStatus_SERVING_STATUS_SERVING,
+	}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("expected FailedPrecondition, got %v", err)
+	}
+}
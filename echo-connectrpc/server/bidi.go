@@ -0,0 +1,125 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata keys selecting BidirectionalStream behavior
+const (
+	bidiBatchKey      = "x-bidi-batch"
+	bidiRepeatKey     = "x-bidi-repeat"
+	bidiReorderKey    = "x-bidi-reorder"
+	bidiDelayKey      = "x-bidi-delay-ms"
+	bidiAfterCloseKey = "x-bidi-after-close"
+
+	maxBidiFactor = 1000
+	maxBidiDelay  = time.Minute
+)
+
+// bidiMode transforms the messages of a BidirectionalStream: inputs are
+// joined in batches, each batch is repeated, and the outputs are sent in
+// reverse order per reorder window. The zero mode echoes verbatim.
+type bidiMode struct {
+	batch      int
+	repeat     int
+	reorder    int
+	delay      time.Duration
+	afterClose int
+
+	batched []string // inputs waiting for a full batch
+	held    []string // outputs waiting for a full reorder window
+}
+
+// parseBidiMode reads the mode from request metadata. get returns the first
+// value of a key, or "" if it is not set.
+func parseBidiMode(get func(key string) string) (*bidiMode, error) {
+	m := &bidiMode{batch: 1, repeat: 1, reorder: 1}
+
+	factors := []struct {
+		key   string
+		value *int
+		min   int
+	}{
+		{bidiBatchKey, &m.batch, 1},
+		{bidiRepeatKey, &m.repeat, 1},
+		{bidiReorderKey, &m.reorder, 1},
+		{bidiAfterCloseKey, &m.afterClose, 0},
+	}
+	for _, f := range factors {
+		v := get(f.key)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < f.min || n > maxBidiFactor {
+			return nil, fmt.Errorf("%s must be between %d and %d", f.key, f.min, maxBidiFactor)
+		}
+		*f.value = n
+	}
+
+	if v := get(bidiDelayKey); v != "" {
+		ms, err := strconv.Atoi(v)
+		if err != nil || ms < 0 || ms > int(maxBidiDelay.Milliseconds()) {
+			return nil, fmt.Errorf("%s must be between 0 and %d", bidiDelayKey, maxBidiDelay.Milliseconds())
+		}
+		m.delay = time.Duration(ms) * time.Millisecond
+	}
+
+	return m, nil
+}
+
+// push takes an input message and returns the outputs that are ready.
+func (m *bidiMode) push(message string) []string {
+	m.batched = append(m.batched, message)
+	if len(m.batched) < m.batch {
+		return nil
+	}
+	return m.emit()
+}
+
+// flush returns the outputs still held back when the client half-closes,
+// followed by the messages sent after the close.
+func (m *bidiMode) flush() []string {
+	var out []string
+	if len(m.batched) > 0 {
+		out = m.emit()
+	}
+	slices.Reverse(m.held)
+	out = append(out, m.held...)
+	m.held = nil
+
+	for i := 1; i <= m.afterClose; i++ {
+		out = append(out, fmt.Sprintf("after close [%d/%d]", i, m.afterClose))
+	}
+	return out
+}
+
+func (m *bidiMode) emit() []string {
+	message := strings.Join(m.batched, ", ")
+	m.batched = m.batched[:0]
+
+	outputs := []string{message}
+	if m.repeat > 1 {
+		outputs = make([]string, m.repeat)
+		for i := range outputs {
+			outputs[i] = fmt.Sprintf("%s [%d/%d]", message, i+1, m.repeat)
+		}
+	}
+	if m.reorder == 1 {
+		return outputs
+	}
+
+	m.held = append(m.held, outputs...)
+	var out []string
+	for len(m.held) >= m.reorder {
+		window := slices.Clone(m.held[:m.reorder])
+		slices.Reverse(window)
+		out = append(out, window...)
+		m.held = m.held[m.reorder:]
+	}
+	return out
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+)
+
+// runBidi sends inputs with the given headers, half-closes and returns all
+// messages received until the server ends the stream.
+func runBidi(t *testing.T, client protoconnect.EchoClient, header http.Header, inputs []string) []string {
+	t.Helper()
+
+	stream := client.BidirectionalStream(context.Background())
+	for key, values := range header {
+		stream.RequestHeader()[key] = values
+	}
+
+	for _, input := range inputs {
+		if err := stream.Send(&pb.EchoRequest{Message: input}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if err := stream.CloseRequest(); err != nil {
+		t.Fatalf("CloseRequest failed: %v", err)
+	}
+
+	var outputs []string
+	for {
+		resp, err := stream.Receive()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Receive failed: %v", err)
+		}
+		outputs = append(outputs, resp.Message)
+	}
+	if err := stream.CloseResponse(); err != nil {
+		t.Fatalf("CloseResponse failed: %v", err)
+	}
+	return outputs
+}
+
+func TestBidirectionalStream_Modes(t *testing.T) {
+	client := setupHTTP2TestServer(t)
+
+	inputs := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected []string
+	}{
+		{
+			name:     "echo",
+			expected: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:     "batch",
+			header:   http.Header{"X-Bidi-Batch": {"2"}},
+			expected: []string{"a, b", "c, d", "e"},
+		},
+		{
+			name:     "repeat",
+			header:   http.Header{"X-Bidi-Repeat": {"2"}},
+			expected: []string{"a [1/2]", "a [2/2]", "b [1/2]", "b [2/2]", "c [1/2]", "c [2/2]", "d [1/2]", "d [2/2]", "e [1/2]", "e [2/2]"},
+		},
+		{
+			name:     "reorder",
+			header:   http.Header{"X-Bidi-Reorder": {"2"}},
+			expected: []string{"b", "a", "d", "c", "e"},
+		},
+		{
+			name:     "after close",
+			header:   http.Header{"X-Bidi-After-Close": {"2"}},
+			expected: []string{"a", "b", "c", "d", "e", "after close [1/2]", "after close [2/2]"},
+		},
+		{
+			name:     "batch and reorder",
+			header:   http.Header{"X-Bidi-Batch": {"2"}, "X-Bidi-Reorder": {"3"}},
+			expected: []string{"e", "c, d", "a, b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs := runBidi(t, client, tt.header, inputs)
+			if !slices.Equal(outputs, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, outputs)
+			}
+		})
+	}
+}
+
+func TestBidirectionalStream_Delay(t *testing.T) {
+	client := setupHTTP2TestServer(t)
+
+	start := time.Now()
+	outputs := runBidi(t, client, http.Header{"X-Bidi-Delay-Ms": {"50"}}, []string{"a", "b"})
+
+	if len(outputs) != 2 {
+		t.Fatalf("expected 2 outputs, got %q", outputs)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected replies to be delayed by 50ms each, took %s", elapsed)
+	}
+}
+
+func TestBidirectionalStream_InvalidMode(t *testing.T) {
+	client := setupHTTP2TestServer(t)
+
+	tests := []http.Header{
+		{"X-Bidi-Batch": {"0"}},
+		{"X-Bidi-Repeat": {"abc"}},
+		{"X-Bidi-Reorder": {"1001"}},
+		{"X-Bidi-Delay-Ms": {"-1"}},
+		{"X-Bidi-After-Close": {"-1"}},
+	}
+
+	for _, header := range tests {
+		stream := client.BidirectionalStream(context.Background())
+		for key, values := range header {
+			stream.RequestHeader()[key] = values
+		}
+		if err := stream.Send(nil); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		if _, err := stream.Receive(); connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Errorf("%v: expected InvalidArgument, got %v", header, err)
+		}
+		_ = stream.CloseResponse()
+	}
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// Response headers reporting how a call reached the server.
+const (
+	protocolHeader            = "X-Echo-Protocol"
+	httpMethodHeader          = "X-Echo-Http-Method"
+	codecHeader               = "X-Echo-Codec"
+	requestCompressionHeader  = "X-Echo-Request-Compression"
+	responseCompressionHeader = "X-Echo-Response-Compression"
+)
+
+const compressionIdentity = "identity"
+
+type callInfoKey struct{}
+
+// callInfo describes the protocol, codec and compression of a call.
+type callInfo struct {
+	protocol            string
+	httpMethod          string
+	codec               string
+	requestCompression  string
+	acceptCompression   []string
+	responseCompression string // negotiated; small messages may still be sent uncompressed
+	compressors         []string
+}
+
+// newCallInfo works out the codec and compression from the request headers,
+// or the query of Connect GET requests. The response compression is
+// negotiated the way connect-go does among the handler's compressors.
+func newCallInfo(spec connect.Spec, peer connect.Peer, header http.Header, httpMethod string, compressors []string) callInfo {
+	info := callInfo{
+		protocol:    peer.Protocol,
+		httpMethod:  httpMethod,
+		codec:       requestCodec(header.Get("Content-Type")),
+		compressors: compressors,
+	}
+
+	requestKey, acceptKey := "Grpc-Encoding", "Grpc-Accept-Encoding"
+	if peer.Protocol == connect.ProtocolConnect {
+		requestKey, acceptKey = "Connect-Content-Encoding", "Connect-Accept-Encoding"
+		if spec.StreamType == connect.StreamTypeUnary {
+			requestKey, acceptKey = "Content-Encoding", "Accept-Encoding"
+		}
+	}
+	info.requestCompression = header.Get(requestKey)
+	if httpMethod == http.MethodGet {
+		info.codec = peer.Query.Get("encoding")
+		info.requestCompression = peer.Query.Get("compression")
+	}
+	if info.requestCompression == "" {
+		info.requestCompression = compressionIdentity
+	}
+	info.acceptCompression = strings.FieldsFunc(header.Get(acceptKey), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+
+	// Responses use the request's compressor, or else the first accepted one
+	// the handler supports
+	info.responseCompression = info.requestCompression
+	if info.responseCompression == compressionIdentity {
+		for _, name := range info.acceptCompression {
+			if slices.Contains(compressors, name) {
+				info.responseCompression = name
+				break
+			}
+		}
+	}
+
+	return info
+}
+
+// requestCodec extracts the codec name from a Connect, gRPC or gRPC-Web
+// content type.
+func requestCodec(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	for _, prefix := range []string{"application/grpc-web", "application/grpc"} {
+		if rest, ok := strings.CutPrefix(mediaType, prefix); ok {
+			if codec, ok := strings.CutPrefix(rest, "+"); ok {
+				return codec
+			}
+			return "proto"
+		}
+	}
+	if codec, ok := strings.CutPrefix(mediaType, "application/connect+"); ok {
+		return codec
+	}
+	return strings.TrimPrefix(mediaType, "application/")
+}
+
+func (info callInfo) setHeaders(header http.Header) {
+	header.Set(protocolHeader, info.protocol)
+	header.Set(httpMethodHeader, info.httpMethod)
+	header.Set(codecHeader, info.codec)
+	header.Set(requestCompressionHeader, info.requestCompression)
+	header.Set(responseCompressionHeader, info.responseCompression)
+}
+
+func callInfoFromContext(ctx context.Context) (callInfo, bool) {
+	info, ok := ctx.Value(callInfoKey{}).(callInfo)
+	return info, ok
+}
+
+// NewCallInfoInterceptor reports the protocol, HTTP method, codec and
+// compression of every call in X-Echo-* response headers. compressors are
+// the ones the handler supports.
+func NewCallInfoInterceptor(compressors []string) connect.Interceptor {
+	return &callInfoInterceptor{compressors: compressors}
+}
+
+type callInfoInterceptor struct {
+	compressors []string
+}
+
+func (i *callInfoInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		info := newCallInfo(req.Spec(), req.Peer(), req.Header(), req.HTTPMethod(), i.compressors)
+		resp, err := next(context.WithValue(ctx, callInfoKey{}, info), req)
+		// Failed handlers return a typed nil response, which is not a nil AnyResponse
+		if err == nil {
+			info.setHeaders(resp.Header())
+		}
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			info.setHeaders(connectErr.Meta())
+		}
+		return resp, err
+	}
+}
+
+func (i *callInfoInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *callInfoInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		// Streaming calls are always POST requests
+		info := newCallInfo(conn.Spec(), conn.Peer(), conn.RequestHeader(), http.MethodPost, i.compressors)
+		info.setHeaders(conn.ResponseHeader())
+		return next(context.WithValue(ctx, callInfoKey{}, info), conn)
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"compress/zlib"
+	"fmt"
+	"io"
+	"slices"
+
+	"connectrpc.com/connect"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxZstdWindow caps the memory a zstd request message may make the server
+// allocate for its decoding window.
+const maxZstdWindow = 64 * 1024 * 1024
+
+// Compressors lists the message compressors the server can be configured
+// with, in addition to identity.
+var Compressors = []string{"gzip", "deflate", "zstd", "snappy"}
+
+// defaultCompressors are the compressors of a handler built without
+// CompressionOptions; connect-go always registers gzip.
+var defaultCompressors = []string{"gzip"}
+
+// CompressionOptions configures handlers to support exactly the named
+// compressors and to send messages smaller than minBytes uncompressed.
+func CompressionOptions(names []string, minBytes int) ([]connect.HandlerOption, error) {
+	for _, name := range names {
+		if !slices.Contains(Compressors, name) {
+			return nil, fmt.Errorf("unknown compressor %q", name)
+		}
+	}
+
+	opts := []connect.HandlerOption{connect.WithCompressMinBytes(minBytes)}
+	if !slices.Contains(names, "gzip") {
+		// Remove the gzip support connect-go registers by default
+		opts = append(opts, connect.WithCompression("gzip", nil, nil))
+	}
+	if slices.Contains(names, "deflate") {
+		opts = append(opts, connect.WithCompression("deflate", newDeflateDecompressor, newDeflateCompressor))
+	}
+	if slices.Contains(names, "zstd") {
+		opts = append(opts, connect.WithCompression("zstd", newZstdDecompressor, newZstdCompressor))
+	}
+	if slices.Contains(names, "snappy") {
+		opts = append(opts, connect.WithCompression("snappy", newSnappyDecompressor, newSnappyCompressor))
+	}
+	return opts, nil
+}
+
+// The "deflate" encoding, like HTTP Content-Encoding, means the zlib format.
+func newDeflateCompressor() connect.Compressor {
+	return zlib.NewWriter(nil)
+}
+
+func newDeflateDecompressor() connect.Decompressor {
+	return &deflateDecompressor{}
+}
+
+// deflateDecompressor creates its zlib reader on the first Reset, since
+// zlib.NewReader needs the stream header.
+type deflateDecompressor struct {
+	io.ReadCloser
+}
+
+func (d *deflateDecompressor) Reset(r io.Reader) error {
+	if d.ReadCloser == nil {
+		reader, err := zlib.NewReader(r)
+		if err != nil {
+			return err
+		}
+		d.ReadCloser = reader
+		return nil
+	}
+	return d.ReadCloser.(zlib.Resetter).Reset(r, nil)
+}
+
+func newZstdCompressor() connect.Compressor {
+	// Options are valid, so NewWriter cannot fail
+	encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	return encoder
+}
+
+func newZstdDecompressor() connect.Decompressor {
+	decoder, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxZstdWindow))
+	return &zstdDecompressor{decoder}
+}
+
+// zstdDecompressor keeps its decoder open between messages. A
+// single-threaded decoder starts no goroutines, so it never needs closing.
+type zstdDecompressor struct {
+	*zstd.Decoder
+}
+
+func (d *zstdDecompressor) Close() error { return nil }
+
+// The "snappy" encoding uses the framed Snappy stream format.
+func newSnappyCompressor() connect.Compressor {
+	return s2.NewWriter(nil, s2.WriterSnappyCompat(), s2.WriterConcurrency(1))
+}
+
+func newSnappyDecompressor() connect.Decompressor {
+	return &snappyDecompressor{s2.NewReader(nil)}
+}
+
+type snappyDecompressor struct {
+	*s2.Reader
+}
+
+func (d *snappyDecompressor) Reset(r io.Reader) error {
+	d.Reader.Reset(r)
+	return nil
+}
+
+func (d *snappyDecompressor) Close() error { return nil }
@@ -0,0 +1,266 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+)
+
+// setupCompressionTestServer serves the Echo handler over HTTP/2 with the
+// given compressors and the call info interceptor.
+func setupCompressionTestServer(t *testing.T, compressors []string, minBytes int) *httptest.Server {
+	t.Helper()
+
+	opts, err := CompressionOptions(compressors, minBytes)
+	if err != nil {
+		t.Fatalf("CompressionOptions failed: %v", err)
+	}
+	opts = append(opts, connect.WithInterceptors(NewCallInfoInterceptor(compressors)))
+
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewEchoHandler(NewEchoServer(), opts...))
+
+	server := httptest.NewUnstartedServer(mux)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// acceptAllCompressors lets a client send and receive every compressor.
+func acceptAllCompressors() []connect.ClientOption {
+	return []connect.ClientOption{
+		connect.WithAcceptCompression("deflate", newDeflateDecompressor, newDeflateCompressor),
+		connect.WithAcceptCompression("zstd", newZstdDecompressor, newZstdCompressor),
+		connect.WithAcceptCompression("snappy", newSnappyDecompressor, newSnappyCompressor),
+	}
+}
+
+func TestEchoCompression_Protocols(t *testing.T) {
+	server := setupCompressionTestServer(t, Compressors, 0)
+	message := strings.Repeat("compressible ", 1000)
+
+	tests := []struct {
+		name        string
+		opts        []connect.ClientOption
+		protocol    string
+		httpMethod  string
+		codec       string
+		compression string
+	}{
+		{
+			name:        "connect proto zstd",
+			opts:        []connect.ClientOption{connect.WithSendCompression("zstd")},
+			protocol:    "connect",
+			httpMethod:  http.MethodPost,
+			codec:       "proto",
+			compression: "zstd",
+		},
+		{
+			name:        "connect json get",
+			opts:        []connect.ClientOption{connect.WithProtoJSON(), connect.WithHTTPGet()},
+			protocol:    "connect",
+			httpMethod:  http.MethodGet,
+			codec:       "json",
+			compression: "identity",
+		},
+		{
+			name:        "grpc snappy",
+			opts:        []connect.ClientOption{connect.WithGRPC(), connect.WithSendCompression("snappy")},
+			protocol:    "grpc",
+			httpMethod:  http.MethodPost,
+			codec:       "proto",
+			compression: "snappy",
+		},
+		{
+			name:        "grpc-web json deflate",
+			opts:        []connect.ClientOption{connect.WithGRPCWeb(), connect.WithProtoJSON(), connect.WithSendCompression("deflate")},
+			protocol:    "grpcweb",
+			httpMethod:  http.MethodPost,
+			codec:       "json",
+			compression: "deflate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := protoconnect.NewEchoClient(server.Client(), server.URL, append(acceptAllCompressors(), tt.opts...)...)
+
+			resp, err := client.EchoCompression(context.Background(), connect.NewRequest(&pb.EchoCompressionRequest{Message: message}))
+			if err != nil {
+				t.Fatalf("EchoCompression failed: %v", err)
+			}
+			if resp.Msg.Message != message {
+				t.Error("expected message to round-trip")
+			}
+			if resp.Msg.RequestEncoding != tt.compression {
+				t.Errorf("expected request encoding %q, got %q", tt.compression, resp.Msg.RequestEncoding)
+			}
+			if !slices.Equal(resp.Msg.SupportedEncodings, Compressors) {
+				t.Errorf("expected supported encodings %v, got %v", Compressors, resp.Msg.SupportedEncodings)
+			}
+
+			expected := map[string]string{
+				"X-Echo-Protocol":             tt.protocol,
+				"X-Echo-Http-Method":          tt.httpMethod,
+				"X-Echo-Codec":                tt.codec,
+				"X-Echo-Request-Compression":  tt.compression,
+				"X-Echo-Response-Compression": resp.Msg.ResponseEncoding,
+			}
+			for key, value := range expected {
+				if got := resp.Header().Get(key); got != value {
+					t.Errorf("expected %s %q, got %q", key, value, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEchoCompression_ResponseFollowsRequest(t *testing.T) {
+	server := setupCompressionTestServer(t, Compressors, 0)
+	client := protoconnect.NewEchoClient(server.Client(), server.URL,
+		append(acceptAllCompressors(), connect.WithSendCompression("snappy"))...)
+
+	resp, err := client.EchoCompression(context.Background(), connect.NewRequest(&pb.EchoCompressionRequest{Message: "hello"}))
+	if err != nil {
+		t.Fatalf("EchoCompression failed: %v", err)
+	}
+	if resp.Msg.ResponseEncoding != "snappy" {
+		t.Errorf("expected response encoding snappy, got %q", resp.Msg.ResponseEncoding)
+	}
+	if !slices.Contains(resp.Msg.AcceptEncodings, "zstd") {
+		t.Errorf("expected zstd in accept encodings %v", resp.Msg.AcceptEncodings)
+	}
+}
+
+func TestEchoCompression_StreamingHeaders(t *testing.T) {
+	server := setupCompressionTestServer(t, Compressors, 0)
+	client := protoconnect.NewEchoClient(server.Client(), server.URL,
+		append(acceptAllCompressors(), connect.WithSendCompression("zstd"))...)
+
+	stream, err := client.ServerStream(context.Background(), connect.NewRequest(&pb.ServerStreamRequest{Message: "hello", Count: 1}))
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	for stream.Receive() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+	if got := stream.ResponseHeader().Get("X-Echo-Request-Compression"); got != "zstd" {
+		t.Errorf("expected request compression zstd, got %q", got)
+	}
+	if got := stream.ResponseHeader().Get("X-Echo-Protocol"); got != "connect" {
+		t.Errorf("expected protocol connect, got %q", got)
+	}
+}
+
+func TestEchoCompression_FailedUnaryHeaders(t *testing.T) {
+	server := setupCompressionTestServer(t, Compressors, 0)
+
+	for _, opt := range []connect.ClientOption{connect.WithProtoJSON(), connect.WithGRPC(), connect.WithGRPCWeb()} {
+		client := protoconnect.NewEchoClient(server.Client(), server.URL, opt)
+
+		_, err := client.EchoError(context.Background(), connect.NewRequest(&pb.EchoErrorRequest{
+			Message: "error test",
+			Code:    int32(connect.CodeNotFound),
+		}))
+		var connectErr *connect.Error
+		if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeNotFound {
+			t.Fatalf("expected NotFound, got %v", err)
+		}
+		if got := connectErr.Meta().Get("X-Echo-Request-Compression"); got != "identity" {
+			t.Errorf("expected request compression identity on the error, got %q", got)
+		}
+	}
+}
+
+func TestEchoCompression_DefaultHandler(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	resp, err := client.EchoCompression(context.Background(), connect.NewRequest(&pb.EchoCompressionRequest{Message: "hello"}))
+	if err != nil {
+		t.Fatalf("EchoCompression failed: %v", err)
+	}
+	if !slices.Equal(resp.Msg.SupportedEncodings, []string{"gzip"}) {
+		t.Errorf("expected only gzip, got %v", resp.Msg.SupportedEncodings)
+	}
+	if resp.Msg.ResponseEncoding != "gzip" {
+		t.Errorf("expected gzip response encoding, got %q", resp.Msg.ResponseEncoding)
+	}
+}
+
+func TestEchoCompression_ResponseCompressorUnsupported(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	_, err := client.EchoCompression(context.Background(), connect.NewRequest(&pb.EchoCompressionRequest{
+		Message:            "hello",
+		ResponseCompressor: "gzip",
+	}))
+	if connect.CodeOf(err) != connect.CodeUnimplemented {
+		t.Errorf("expected Unimplemented, got %v", err)
+	}
+}
+
+func TestCompressionOptions_OnlyConfigured(t *testing.T) {
+	server := setupCompressionTestServer(t, []string{"zstd"}, 0)
+	client := protoconnect.NewEchoClient(server.Client(), server.URL, connect.WithSendGzip())
+
+	_, err := client.Echo(context.Background(), connect.NewRequest(&pb.EchoRequest{Message: "hello"}))
+	if connect.CodeOf(err) != connect.CodeUnimplemented {
+		t.Errorf("expected Unimplemented for unsupported gzip, got %v", err)
+	}
+
+	if _, err := CompressionOptions([]string{"brotli"}, 0); err == nil {
+		t.Error("expected error for unknown compressor")
+	}
+}
+
+func TestCompressionOptions_MinBytes(t *testing.T) {
+	server := setupCompressionTestServer(t, Compressors, 1000)
+
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{name: "below minimum", message: "hello", expected: ""},
+		{name: "above minimum", message: strings.Repeat("x", 2000), expected: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+protoconnect.EchoEchoCompressionProcedure,
+				strings.NewReader(`{"message": "`+tt.message+`"}`))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			// Setting Accept-Encoding stops the transport from decompressing
+			req.Header.Set("Accept-Encoding", "gzip")
+
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if got := resp.Header.Get("Content-Encoding"); got != tt.expected {
+				t.Errorf("expected Content-Encoding %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"connectrpc.com/connect"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 
 	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
@@ -23,10 +25,13 @@ const (
 
 type EchoServer struct {
 	protoconnect.UnimplementedEchoHandler
+	flaky *flakyTracker
 }
 
 func NewEchoServer() *EchoServer {
-	return &EchoServer{}
+	return &EchoServer{
+		flaky: newFlakyTracker(),
+	}
 }
 
 func (s *EchoServer) Echo(ctx context.Context, req *connect.Request[pb.EchoRequest]) (*connect.Response[pb.EchoResponse], error) {
@@ -42,6 +47,10 @@ func (s *EchoServer) Echo(ctx context.Context, req *connect.Request[pb.EchoReque
 		}
 	}
 
+	for key, value := range tlsMetadata(ctx) {
+		resp.Metadata[key] = value
+	}
+
 	response := connect.NewResponse(resp)
 
 	// Set response trailers
@@ -75,6 +84,10 @@ func (s *EchoServer) EchoWithDelay(ctx context.Context, req *connect.Request[pb.
 		}
 	}
 
+	for key, value := range tlsMetadata(ctx) {
+		resp.Metadata[key] = value
+	}
+
 	response := connect.NewResponse(resp)
 
 	// Set response trailers
@@ -87,9 +100,9 @@ func (s *EchoServer) EchoWithDelay(ctx context.Context, req *connect.Request[pb.
 	return response, nil
 }
 
-func (s *EchoServer) EchoError(_ context.Context, req *connect.Request[pb.EchoErrorRequest]) (*connect.Response[pb.EchoResponse], error) {
+func (s *EchoServer) EchoError(ctx context.Context, req *connect.Request[pb.EchoErrorRequest]) (*connect.Response[pb.EchoResponse], error) {
 	code := connect.Code(req.Msg.Code)
-	if code > 16 {
+	if code > 16 && !req.Msg.Wire.GetRawCode() {
 		code = connect.CodeUnknown
 	}
 
@@ -98,6 +111,13 @@ func (s *EchoServer) EchoError(_ context.Context, req *connect.Request[pb.EchoEr
 		details = fmt.Sprintf("error with code %d: %s", req.Msg.Code, req.Msg.Message)
 	}
 
+	// Malformed statuses are written by WireStatusMiddleware
+	if req.Msg.Wire != nil {
+		if err := setWireStatus(ctx, req.Peer().Protocol, req.Msg.Wire); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, connect.NewError(code, fmt.Errorf("%s", details))
 }
 
@@ -138,6 +158,10 @@ func (s *EchoServer) EchoWithTrailers(ctx context.Context, req *connect.Request[
 		}
 	}
 
+	for key, value := range tlsMetadata(ctx) {
+		resp.Metadata[key] = value
+	}
+
 	response := connect.NewResponse(resp)
 
 	// Set specified trailers
@@ -148,6 +172,61 @@ func (s *EchoServer) EchoWithTrailers(ctx context.Context, req *connect.Request[
 	return response, nil
 }
 
+func (s *EchoServer) EchoWithMetadata(ctx context.Context, req *connect.Request[pb.EchoWithMetadataRequest]) (*connect.Response[pb.EchoResponse], error) {
+	header, err := headerFromEntries(req.Msg.Headers)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid headers: %w", err))
+	}
+	trailer, err := headerFromEntries(req.Msg.Trailers)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid trailers: %w", err))
+	}
+
+	// An error status ends the call without a response message. connect-go
+	// sends error metadata where the protocol keeps it: in the trailers for
+	// gRPC and gRPC-Web, in the headers for Connect unary calls
+	if req.Msg.StatusCode != 0 {
+		code := connect.Code(req.Msg.StatusCode)
+		if code > 16 {
+			code = connect.CodeUnknown
+		}
+		connectErr := connect.NewError(code, errors.New(req.Msg.StatusMessage))
+		for key, values := range header {
+			connectErr.Meta()[key] = append(connectErr.Meta()[key], values...)
+		}
+		for key, values := range trailer {
+			connectErr.Meta()[key] = append(connectErr.Meta()[key], values...)
+		}
+		return nil, connectErr
+	}
+
+	resp := &pb.EchoResponse{
+		Message:  req.Msg.Message,
+		Metadata: make(map[string]string),
+	}
+
+	// Echo back request headers in response body
+	for key, values := range req.Header() {
+		if len(values) > 0 {
+			resp.Metadata[key] = values[0]
+		}
+	}
+
+	for key, value := range tlsMetadata(ctx) {
+		resp.Metadata[key] = value
+	}
+
+	response := connect.NewResponse(resp)
+	for key, values := range header {
+		response.Header()[key] = values
+	}
+	for key, values := range trailer {
+		response.Trailer()[key] = values
+	}
+
+	return response, nil
+}
+
 func (s *EchoServer) EchoLargePayload(_ context.Context, req *connect.Request[pb.EchoLargePayloadRequest]) (*connect.Response[pb.EchoLargePayloadResponse], error) {
 	size := int(req.Msg.SizeBytes)
 	if size <= 0 {
@@ -175,6 +254,25 @@ func (s *EchoServer) EchoLargePayload(_ context.Context, req *connect.Request[pb
 	return connect.NewResponse(resp), nil
 }
 
+func (s *EchoServer) EchoCompression(ctx context.Context, req *connect.Request[pb.EchoCompressionRequest]) (*connect.Response[pb.EchoCompressionResponse], error) {
+	if req.Msg.ResponseCompressor != "" {
+		return nil, connect.NewError(connect.CodeUnimplemented, errors.New("connect-go negotiates the response compressor itself"))
+	}
+
+	info, ok := callInfoFromContext(ctx)
+	if !ok {
+		info = newCallInfo(req.Spec(), req.Peer(), req.Header(), req.HTTPMethod(), defaultCompressors)
+	}
+
+	return connect.NewResponse(&pb.EchoCompressionResponse{
+		Message:            req.Msg.Message,
+		RequestEncoding:    info.requestCompression,
+		AcceptEncodings:    info.acceptCompression,
+		ResponseEncoding:   info.responseCompression,
+		SupportedEncodings: info.compressors,
+	}), nil
+}
+
 func (s *EchoServer) EchoDeadline(ctx context.Context, req *connect.Request[pb.EchoDeadlineRequest]) (*connect.Response[pb.EchoDeadlineResponse], error) {
 	resp := &pb.EchoDeadlineResponse{
 		Message:     req.Msg.Message,
@@ -208,50 +306,104 @@ func (s *EchoServer) EchoErrorWithDetails(_ context.Context, req *connect.Reques
 
 	// Add rich error details
 	for _, detail := range req.Msg.Details {
-		switch detail.Type {
-		case "bad_request":
-			br := &errdetails.BadRequest{}
-			for _, fv := range detail.FieldViolations {
-				br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
-					Field:       fv.Field,
-					Description: fv.Description,
-				})
-			}
-			if d, detailErr := connect.NewErrorDetail(br); detailErr == nil {
-				err.AddDetail(d)
-			}
-		case "retry_info":
-			ri := &errdetails.RetryInfo{
-				RetryDelay: durationpb.New(time.Duration(detail.RetryDelayMs) * time.Millisecond),
-			}
-			if d, detailErr := connect.NewErrorDetail(ri); detailErr == nil {
-				err.AddDetail(d)
-			}
-		case "debug_info":
-			di := &errdetails.DebugInfo{
-				StackEntries: detail.StackEntries,
-				Detail:       detail.DebugDetail,
-			}
-			if d, detailErr := connect.NewErrorDetail(di); detailErr == nil {
-				err.AddDetail(d)
-			}
-		case "quota_failure":
-			qf := &errdetails.QuotaFailure{}
-			for _, qv := range detail.QuotaViolations {
-				qf.Violations = append(qf.Violations, &errdetails.QuotaFailure_Violation{
-					Subject:     qv.Subject,
-					Description: qv.Description,
-				})
-			}
-			if d, detailErr := connect.NewErrorDetail(qf); detailErr == nil {
-				err.AddDetail(d)
-			}
+		msg, detailErr := errorDetail(detail)
+		if detailErr != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, detailErr)
 		}
+		// Passthrough details are already an Any, which is sent as is
+		d, detailErr := connect.NewErrorDetail(msg)
+		if detailErr != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to attach error details: %w", detailErr))
+		}
+		err.AddDetail(d)
 	}
 
 	return nil, err
 }
 
+// errorDetail builds the google.rpc error detail message of detail's type, or
+// returns its Any as is for the "any" type.
+func errorDetail(detail *pb.ErrorDetail) (proto.Message, error) {
+	switch detail.Type {
+	case "bad_request":
+		br := &errdetails.BadRequest{}
+		for _, fv := range detail.FieldViolations {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fv.Field,
+				Description: fv.Description,
+			})
+		}
+		return br, nil
+	case "retry_info":
+		return &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(time.Duration(detail.RetryDelayMs) * time.Millisecond),
+		}, nil
+	case "debug_info":
+		return &errdetails.DebugInfo{
+			StackEntries: detail.StackEntries,
+			Detail:       detail.DebugDetail,
+		}, nil
+	case "quota_failure":
+		qf := &errdetails.QuotaFailure{}
+		for _, qv := range detail.QuotaViolations {
+			qf.Violations = append(qf.Violations, &errdetails.QuotaFailure_Violation{
+				Subject:     qv.Subject,
+				Description: qv.Description,
+			})
+		}
+		return qf, nil
+	case "error_info":
+		return &errdetails.ErrorInfo{
+			Reason:   detail.Reason,
+			Domain:   detail.Domain,
+			Metadata: detail.Metadata,
+		}, nil
+	case "precondition_failure":
+		pf := &errdetails.PreconditionFailure{}
+		for _, pv := range detail.PreconditionViolations {
+			pf.Violations = append(pf.Violations, &errdetails.PreconditionFailure_Violation{
+				Type:        pv.Type,
+				Subject:     pv.Subject,
+				Description: pv.Description,
+			})
+		}
+		return pf, nil
+	case "resource_info":
+		return &errdetails.ResourceInfo{
+			ResourceType: detail.ResourceType,
+			ResourceName: detail.ResourceName,
+			Owner:        detail.Owner,
+			Description:  detail.Description,
+		}, nil
+	case "request_info":
+		return &errdetails.RequestInfo{
+			RequestId:   detail.RequestId,
+			ServingData: detail.ServingData,
+		}, nil
+	case "help":
+		help := &errdetails.Help{}
+		for _, link := range detail.Links {
+			help.Links = append(help.Links, &errdetails.Help_Link{
+				Description: link.Description,
+				Url:         link.Url,
+			})
+		}
+		return help, nil
+	case "localized_message":
+		return &errdetails.LocalizedMessage{
+			Locale:  detail.Locale,
+			Message: detail.LocalizedMessage,
+		}, nil
+	case "any":
+		if detail.Any == nil {
+			return nil, fmt.Errorf("error detail type %q requires any", detail.Type)
+		}
+		return detail.Any, nil
+	default:
+		return nil, fmt.Errorf("unknown error detail type %q", detail.Type)
+	}
+}
+
 func (s *EchoServer) ServerStream(ctx context.Context, req *connect.Request[pb.ServerStreamRequest], stream *connect.ServerStream[pb.EchoResponse]) error {
 	md := make(map[string]string)
 
@@ -269,7 +421,16 @@ func (s *EchoServer) ServerStream(ctx context.Context, req *connect.Request[pb.S
 
 	interval := time.Duration(req.Msg.IntervalMs) * time.Millisecond
 
-	for i := int32(0); i < count; i++ {
+	failure := req.Msg.Failure
+	if err := checkStreamFailure(failure); err != nil {
+		return err
+	}
+	sent := count
+	if failure != nil && failure.After < count {
+		sent = failure.After
+	}
+
+	for i := int32(0); i < sent; i++ {
 		select {
 		case <-ctx.Done():
 			return connect.NewError(connect.CodeCanceled, fmt.Errorf("stream canceled"))
@@ -285,7 +446,7 @@ func (s *EchoServer) ServerStream(ctx context.Context, req *connect.Request[pb.S
 			return err
 		}
 
-		if i < count-1 && interval > 0 {
+		if i < sent-1 && interval > 0 {
 			select {
 			case <-time.After(interval):
 			case <-ctx.Done():
@@ -294,6 +455,10 @@ func (s *EchoServer) ServerStream(ctx context.Context, req *connect.Request[pb.S
 		}
 	}
 
+	if failure != nil {
+		return failStream(stream.ResponseTrailer(), failure)
+	}
+
 	return nil
 }
 
@@ -307,11 +472,25 @@ func (s *EchoServer) ClientStream(ctx context.Context, stream *connect.ClientStr
 		}
 	}
 
-	var messages []string
+	var (
+		messages []string
+		failure  *pb.StreamFailure
+	)
 
 	for stream.Receive() {
 		req := stream.Msg()
 		messages = append(messages, req.Message)
+
+		if len(messages) == 1 && req.Failure != nil {
+			if err := checkStreamFailure(req.Failure); err != nil {
+				return nil, err
+			}
+			failure = req.Failure
+		}
+		// Stop reading once the failure is due
+		if failure != nil && int32(len(messages)) >= failure.After {
+			break
+		}
 	}
 
 	if err := stream.Err(); err != nil {
@@ -322,8 +501,22 @@ func (s *EchoServer) ClientStream(ctx context.Context, stream *connect.ClientStr
 		Message:  strings.Join(messages, ", "),
 		Metadata: md,
 	}
+	response := connect.NewResponse(resp)
 
-	return connect.NewResponse(resp), nil
+	// A failure with code 0 only adds trailers to the normal response
+	if failure != nil {
+		if err := failStream(response.Trailer(), failure); err != nil {
+			var connectErr *connect.Error
+			if errors.As(err, &connectErr) {
+				for key, values := range response.Trailer() {
+					connectErr.Meta()[key] = values
+				}
+			}
+			return nil, err
+		}
+	}
+
+	return response, nil
 }
 
 func (s *EchoServer) BidirectionalStream(ctx context.Context, stream *connect.BidiStream[pb.EchoRequest, pb.EchoResponse]) error {
@@ -336,7 +529,17 @@ func (s *EchoServer) BidirectionalStream(ctx context.Context, stream *connect.Bi
 		}
 	}
 
-	for {
+	mode, err := parseBidiMode(stream.RequestHeader().Get)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	var (
+		failure *pb.StreamFailure
+		sent    int32
+	)
+
+	for received := 0; ; received++ {
 		select {
 		case <-ctx.Done():
 			return connect.NewError(connect.CodeCanceled, fmt.Errorf("stream canceled"))
@@ -344,20 +547,54 @@ func (s *EchoServer) BidirectionalStream(ctx context.Context, stream *connect.Bi
 		}
 
 		req, err := stream.Receive()
-		if err == io.EOF {
-			return nil
-		}
-		if err != nil {
+		closed := errors.Is(err, io.EOF)
+		if err != nil && !closed {
 			return err
 		}
 
-		resp := &pb.EchoResponse{
-			Message:  req.Message,
-			Metadata: md,
+		var outputs []string
+		if closed {
+			// Keep sending after the client's half-close
+			outputs = mode.flush()
+		} else {
+			if received == 0 && req.Failure != nil {
+				if err := checkStreamFailure(req.Failure); err != nil {
+					return err
+				}
+				failure = req.Failure
+			}
+			outputs = mode.push(req.Message)
 		}
 
-		if err := stream.Send(resp); err != nil {
-			return err
+		for _, message := range outputs {
+			if failure != nil && sent >= failure.After {
+				return failStream(stream.ResponseTrailer(), failure)
+			}
+
+			if mode.delay > 0 {
+				select {
+				case <-time.After(mode.delay):
+				case <-ctx.Done():
+					return connect.NewError(connect.CodeCanceled, fmt.Errorf("stream canceled"))
+				}
+			}
+
+			resp := &pb.EchoResponse{
+				Message:  message,
+				Metadata: md,
+			}
+
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			sent++
+		}
+
+		if closed {
+			if failure != nil {
+				return failStream(stream.ResponseTrailer(), failure)
+			}
+			return nil
 		}
 	}
 }
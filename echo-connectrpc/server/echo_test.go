@@ -1,7 +1,9 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,6 +11,8 @@ import (
 
 	"connectrpc.com/connect"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 
 	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
 	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
@@ -279,6 +283,89 @@ func TestEchoWithTrailers_NoTrailers(t *testing.T) {
 	}
 }
 
+func TestEchoWithMetadata_SetsHeadersAndTrailers(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	resp, err := client.EchoWithMetadata(context.Background(), connect.NewRequest(&pb.EchoWithMetadataRequest{
+		Message: "hello",
+		Headers: []*pb.MetadataEntry{
+			{Key: "x-dup", Value: "one"},
+			{Key: "x-dup", Value: "two"},
+			{Key: "x-data-bin", BinaryValue: []byte{0x00, 0xff}},
+		},
+		Trailers: []*pb.MetadataEntry{
+			{Key: "X-Trailer", Value: "trailer-value"},
+		},
+	}))
+
+	if err != nil {
+		t.Fatalf("EchoWithMetadata failed: %v", err)
+	}
+	if resp.Msg.Message != "hello" {
+		t.Errorf("expected message %q, got %q", "hello", resp.Msg.Message)
+	}
+	if vals := resp.Header().Values("x-dup"); len(vals) != 2 || vals[0] != "one" || vals[1] != "two" {
+		t.Errorf("expected header x-dup=[one two], got %v", vals)
+	}
+	data, err := connect.DecodeBinaryHeader(resp.Header().Get("x-data-bin"))
+	if err != nil || !bytes.Equal(data, []byte{0x00, 0xff}) {
+		t.Errorf("expected binary header x-data-bin, got %q (%v)", data, err)
+	}
+	if val := resp.Trailer().Get("x-trailer"); val != "trailer-value" {
+		t.Errorf("expected trailer x-trailer=trailer-value, got %q", val)
+	}
+}
+
+func TestEchoWithMetadata_ErrorStatus(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	_, err := client.EchoWithMetadata(context.Background(), connect.NewRequest(&pb.EchoWithMetadataRequest{
+		Headers:       []*pb.MetadataEntry{{Key: "x-header", Value: "header-value"}},
+		Trailers:      []*pb.MetadataEntry{{Key: "x-trailer", Value: "trailer-value"}},
+		StatusCode:    int32(connect.CodePermissionDenied),
+		StatusMessage: "denied",
+	}))
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodePermissionDenied || connectErr.Message() != "denied" {
+		t.Fatalf("expected PermissionDenied \"denied\", got %v", err)
+	}
+	if val := connectErr.Meta().Get("x-header"); val != "header-value" {
+		t.Errorf("expected error metadata x-header=header-value, got %q", val)
+	}
+	if val := connectErr.Meta().Get("x-trailer"); val != "trailer-value" {
+		t.Errorf("expected error metadata x-trailer=trailer-value, got %q", val)
+	}
+}
+
+func TestEchoWithMetadata_InvalidEntries(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		entry *pb.MetadataEntry
+	}{
+		{name: "empty key", entry: &pb.MetadataEntry{Value: "v"}},
+		{name: "reserved key", entry: &pb.MetadataEntry{Key: "grpc-status", Value: "0"}},
+		{name: "invalid character", entry: &pb.MetadataEntry{Key: "x:key", Value: "v"}},
+		{name: "binary value without -bin", entry: &pb.MetadataEntry{Key: "x-data", BinaryValue: []byte{0x01}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.EchoWithMetadata(context.Background(), connect.NewRequest(&pb.EchoWithMetadataRequest{
+				Headers: []*pb.MetadataEntry{tt.entry},
+			}))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
 func TestEchoRequestMetadata_ReturnsAllMetadata(t *testing.T) {
 	client, server := setupTestServer(t)
 	defer server.Close()
@@ -623,3 +710,136 @@ func TestEchoErrorWithDetails_QuotaFailure(t *testing.T) {
 		t.Errorf("expected subject %q, got %q", "user:123", quotaFailure.Violations[0].Subject)
 	}
 }
+
+func TestEchoErrorWithDetails_ExtendedTypes(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		detail   *pb.ErrorDetail
+		expected proto.Message
+	}{
+		{
+			name:   "error_info",
+			detail: &pb.ErrorDetail{Type: "error_info", Reason: "API_DISABLED", Domain: "example.com", Metadata: map[string]string{"service": "echo"}},
+			expected: &errdetails.ErrorInfo{
+				Reason:   "API_DISABLED",
+				Domain:   "example.com",
+				Metadata: map[string]string{"service": "echo"},
+			},
+		},
+		{
+			name: "precondition_failure",
+			detail: &pb.ErrorDetail{Type: "precondition_failure", PreconditionViolations: []*pb.PreconditionViolation{
+				{Type: "TOS", Subject: "user:123", Description: "terms not accepted"},
+			}},
+			expected: &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+				{Type: "TOS", Subject: "user:123", Description: "terms not accepted"},
+			}},
+		},
+		{
+			name:   "resource_info",
+			detail: &pb.ErrorDetail{Type: "resource_info", ResourceType: "book", ResourceName: "books/1", Owner: "user:123", Description: "not found"},
+			expected: &errdetails.ResourceInfo{
+				ResourceType: "book",
+				ResourceName: "books/1",
+				Owner:        "user:123",
+				Description:  "not found",
+			},
+		},
+		{
+			name:     "request_info",
+			detail:   &pb.ErrorDetail{Type: "request_info", RequestId: "req-1", ServingData: "stack"},
+			expected: &errdetails.RequestInfo{RequestId: "req-1", ServingData: "stack"},
+		},
+		{
+			name:     "help",
+			detail:   &pb.ErrorDetail{Type: "help", Links: []*pb.HelpLink{{Description: "docs", Url: "https://example.com"}}},
+			expected: &errdetails.Help{Links: []*errdetails.Help_Link{{Description: "docs", Url: "https://example.com"}}},
+		},
+		{
+			name:     "localized_message",
+			detail:   &pb.ErrorDetail{Type: "localized_message", Locale: "en-US", LocalizedMessage: "Not found"},
+			expected: &errdetails.LocalizedMessage{Locale: "en-US", Message: "Not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.EchoErrorWithDetails(context.Background(), connect.NewRequest(&pb.EchoErrorWithDetailsRequest{
+				Code:    int32(connect.CodeFailedPrecondition),
+				Details: []*pb.ErrorDetail{tt.detail},
+			}))
+
+			var connectErr *connect.Error
+			if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeFailedPrecondition {
+				t.Fatalf("expected FailedPrecondition, got %v", err)
+			}
+			details := connectErr.Details()
+			if len(details) != 1 {
+				t.Fatalf("expected 1 detail, got %d", len(details))
+			}
+			detail, err := details[0].Value()
+			if err != nil {
+				t.Fatalf("failed to get detail value: %v", err)
+			}
+			if !proto.Equal(detail, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, detail)
+			}
+		})
+	}
+}
+
+func TestEchoErrorWithDetails_AnyPassthrough(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	custom := &pb.EchoRequest{Message: "custom detail"}
+	detailAny, err := anypb.New(custom)
+	if err != nil {
+		t.Fatalf("failed to create Any: %v", err)
+	}
+
+	_, err = client.EchoErrorWithDetails(context.Background(), connect.NewRequest(&pb.EchoErrorWithDetailsRequest{
+		Code:    int32(connect.CodeAborted),
+		Details: []*pb.ErrorDetail{{Type: "any", Any: detailAny}},
+	}))
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeAborted {
+		t.Fatalf("expected Aborted, got %v", err)
+	}
+	details := connectErr.Details()
+	if len(details) != 1 {
+		t.Fatalf("expected 1 detail, got %d", len(details))
+	}
+	// The Any is sent as is, not wrapped in another Any
+	if details[0].Type() != "echo.v1.EchoRequest" {
+		t.Errorf("expected echo.v1.EchoRequest detail, got %q", details[0].Type())
+	}
+	detail, err := details[0].Value()
+	if err != nil {
+		t.Fatalf("failed to get detail value: %v", err)
+	}
+	if !proto.Equal(detail, custom) {
+		t.Errorf("expected %v, got %v", custom, detail)
+	}
+}
+
+func TestEchoErrorWithDetails_InvalidDetail(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	for _, detail := range []*pb.ErrorDetail{{Type: "unknown"}, {Type: "any"}} {
+		t.Run(detail.Type, func(t *testing.T) {
+			_, err := client.EchoErrorWithDetails(context.Background(), connect.NewRequest(&pb.EchoErrorWithDetailsRequest{
+				Code:    int32(connect.CodeNotFound),
+				Details: []*pb.ErrorDetail{detail},
+			}))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
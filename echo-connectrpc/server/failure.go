@@ -0,0 +1,50 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+)
+
+// checkStreamFailure validates f before the stream sends anything.
+func checkStreamFailure(f *pb.StreamFailure) error {
+	if f == nil {
+		return nil
+	}
+	if f.After < 0 {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("failure.after must not be negative"))
+	}
+	if _, err := headerFromEntries(f.Trailers); err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid failure trailers: %w", err))
+	}
+	return nil
+}
+
+// failStream ends the stream as f describes. With rst_stream it panics with
+// http.ErrAbortHandler, which makes net/http reset the HTTP/2 stream with
+// RST_STREAM (or close an HTTP/1.1 connection) without logging. Otherwise it
+// adds f's trailers to trailer and returns f's status, nil for code 0.
+func failStream(trailer http.Header, f *pb.StreamFailure) error {
+	if f.RstStream {
+		panic(http.ErrAbortHandler)
+	}
+
+	// Already validated by checkStreamFailure
+	entries, _ := headerFromEntries(f.Trailers)
+	for key, values := range entries {
+		trailer[key] = append(trailer[key], values...)
+	}
+
+	code := connect.Code(f.Code)
+	if code == 0 {
+		return nil
+	}
+	if code > 16 {
+		code = connect.CodeUnknown
+	}
+	return connect.NewError(code, errors.New(f.Message))
+}
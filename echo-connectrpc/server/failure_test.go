@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+)
+
+// isRSTStream reports whether err comes from the server resetting the stream.
+func isRSTStream(err error) bool {
+	return connect.CodeOf(err) == connect.CodeInternal && strings.Contains(err.Error(), "INTERNAL_ERROR")
+}
+
+func TestServerStream_FailsAfterMessages(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	stream, err := client.ServerStream(context.Background(), connect.NewRequest(&pb.ServerStreamRequest{
+		Message: "ping",
+		Count:   5,
+		Failure: &pb.StreamFailure{
+			After:    2,
+			Code:     int32(connect.CodeUnavailable),
+			Message:  "backend went away",
+			Trailers: []*pb.MetadataEntry{{Key: "x-failure", Value: "injected"}},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+
+	var received int
+	for stream.Receive() {
+		received++
+	}
+
+	if received != 2 {
+		t.Errorf("expected 2 messages before the failure, got %d", received)
+	}
+	var connectErr *connect.Error
+	if !errors.As(stream.Err(), &connectErr) || connectErr.Code() != connect.CodeUnavailable || connectErr.Message() != "backend went away" {
+		t.Errorf("expected Unavailable \"backend went away\", got %v", stream.Err())
+	}
+	if val := stream.ResponseTrailer().Get("x-failure"); val != "injected" {
+		t.Errorf("expected trailer x-failure=injected, got %q", val)
+	}
+}
+
+func TestServerStream_ResetsStream(t *testing.T) {
+	client := setupHTTP2TestServer(t)
+
+	stream, err := client.ServerStream(context.Background(), connect.NewRequest(&pb.ServerStreamRequest{
+		Message: "ping",
+		Count:   5,
+		Failure: &pb.StreamFailure{After: 3, RstStream: true},
+	}))
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+
+	var received int
+	for stream.Receive() {
+		received++
+	}
+
+	if received != 3 {
+		t.Errorf("expected 3 messages before the reset, got %d", received)
+	}
+	if !isRSTStream(stream.Err()) {
+		t.Errorf("expected RST_STREAM error, got %v", stream.Err())
+	}
+
+	// The connection stays usable after the reset
+	if _, err := client.Echo(context.Background(), connect.NewRequest(&pb.EchoRequest{Message: "hello"})); err != nil {
+		t.Errorf("Echo after reset failed: %v", err)
+	}
+}
+
+func TestClientStream_FailsAfterMessages(t *testing.T) {
+	client := setupHTTP2TestServer(t)
+
+	tests := []struct {
+		name    string
+		failure *pb.StreamFailure
+		check   func(t *testing.T, resp *connect.Response[pb.EchoResponse], err error)
+	}{
+		{
+			name: "error status",
+			failure: &pb.StreamFailure{
+				After:    2,
+				Code:     int32(connect.CodeAborted),
+				Message:  "aborted",
+				Trailers: []*pb.MetadataEntry{{Key: "x-failure", Value: "injected"}},
+			},
+			check: func(t *testing.T, _ *connect.Response[pb.EchoResponse], err error) {
+				var connectErr *connect.Error
+				if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeAborted {
+					t.Fatalf("expected Aborted, got %v", err)
+				}
+				if val := connectErr.Meta().Get("x-failure"); val != "injected" {
+					t.Errorf("expected error metadata x-failure=injected, got %q", val)
+				}
+			},
+		},
+		{
+			name:    "early response",
+			failure: &pb.StreamFailure{After: 2, Trailers: []*pb.MetadataEntry{{Key: "x-early", Value: "true"}}},
+			check: func(t *testing.T, resp *connect.Response[pb.EchoResponse], err error) {
+				if err != nil {
+					t.Fatalf("expected early response, got %v", err)
+				}
+				if resp.Msg.Message != "one, two" {
+					t.Errorf("expected %q, got %q", "one, two", resp.Msg.Message)
+				}
+				if val := resp.Trailer().Get("x-early"); val != "true" {
+					t.Errorf("expected trailer x-early=true, got %q", val)
+				}
+			},
+		},
+		{
+			name:    "reset",
+			failure: &pb.StreamFailure{After: 1, RstStream: true},
+			check: func(t *testing.T, _ *connect.Response[pb.EchoResponse], err error) {
+				if !isRSTStream(err) {
+					t.Errorf("expected RST_STREAM error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := client.ClientStream(context.Background())
+
+			requests := []*pb.EchoRequest{
+				{Message: "one", Failure: tt.failure},
+				{Message: "two"},
+				{Message: "three"},
+			}
+			for _, req := range requests {
+				// Sends fail once the server has ended the stream
+				if err := stream.Send(req); err != nil {
+					break
+				}
+			}
+
+			resp, err := stream.CloseAndReceive()
+			tt.check(t, resp, err)
+		})
+	}
+}
+
+func TestBidirectionalStream_FailsAfterMessages(t *testing.T) {
+	client := setupHTTP2TestServer(t)
+
+	tests := []struct {
+		name    string
+		failure *pb.StreamFailure
+		matches func(error) bool
+	}{
+		{
+			name:    "error status",
+			failure: &pb.StreamFailure{After: 1, Code: int32(connect.CodeDataLoss)},
+			matches: func(err error) bool { return connect.CodeOf(err) == connect.CodeDataLoss },
+		},
+		{
+			name:    "reset",
+			failure: &pb.StreamFailure{After: 1, RstStream: true},
+			matches: isRSTStream,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := client.BidirectionalStream(context.Background())
+			defer func() { _ = stream.CloseResponse() }()
+
+			if err := stream.Send(&pb.EchoRequest{Message: "one", Failure: tt.failure}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+			resp, err := stream.Receive()
+			if err != nil || resp.Message != "one" {
+				t.Fatalf("expected echo of first message, got %v, %v", resp, err)
+			}
+
+			if err := stream.Send(&pb.EchoRequest{Message: "two"}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+			if _, err := stream.Receive(); !tt.matches(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStreamFailure_InvalidArgument(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	stream, err := client.ServerStream(context.Background(), connect.NewRequest(&pb.ServerStreamRequest{
+		Count:   1,
+		Failure: &pb.StreamFailure{Trailers: []*pb.MetadataEntry{{Key: "grpc-status", Value: "0"}}},
+	}))
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	for stream.Receive() {
+	}
+	if connect.CodeOf(stream.Err()) != connect.CodeInvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", stream.Err())
+	}
+}
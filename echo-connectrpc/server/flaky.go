@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+)
+
+const (
+	defaultFlakyTTL = time.Minute
+	// maxFlakySequences caps the sequences tracked at once, since clients
+	// choose both the sequence IDs and their TTL
+	maxFlakySequences = 10000
+)
+
+type flakySequence struct {
+	attempts  int32
+	expiresAt time.Time
+}
+
+// flakyTracker counts attempts per sequence ID. Sequences that see no attempt
+// within their TTL are evicted lazily on the next call, and the sequence
+// closest to expiry makes room for a new one once limit is reached.
+type flakyTracker struct {
+	mu        sync.Mutex
+	sequences map[string]*flakySequence
+	limit     int
+}
+
+func newFlakyTracker() *flakyTracker {
+	return &flakyTracker{
+		sequences: make(map[string]*flakySequence),
+		limit:     maxFlakySequences,
+	}
+}
+
+// next records an attempt for id and returns its 1-based attempt number.
+func (t *flakyTracker) next(id string, ttl time.Duration) int32 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for key, seq := range t.sequences {
+		if now.After(seq.expiresAt) {
+			delete(t.sequences, key)
+		}
+	}
+
+	seq, ok := t.sequences[id]
+	if !ok {
+		if len(t.sequences) >= t.limit {
+			t.evictNextToExpire()
+		}
+		seq = &flakySequence{}
+		t.sequences[id] = seq
+	}
+	seq.attempts++
+	seq.expiresAt = now.Add(ttl)
+
+	return seq.attempts
+}
+
+func (t *flakyTracker) evictNextToExpire() {
+	var oldest string
+	var oldestExpiry time.Time
+	for key, seq := range t.sequences {
+		if oldest == "" || seq.expiresAt.Before(oldestExpiry) {
+			oldest, oldestExpiry = key, seq.expiresAt
+		}
+	}
+	delete(t.sequences, oldest)
+}
+
+func (s *EchoServer) EchoFlaky(_ context.Context, req *connect.Request[pb.EchoFlakyRequest]) (*connect.Response[pb.EchoFlakyResponse], error) {
+	if req.Msg.SequenceId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("sequence_id is required"))
+	}
+
+	ttl := defaultFlakyTTL
+	if req.Msg.TtlMs > 0 {
+		ttl = time.Duration(req.Msg.TtlMs) * time.Millisecond
+	}
+
+	attempt := s.flaky.next(req.Msg.SequenceId, ttl)
+
+	var previousAttempts int32
+	if v := req.Header().Get("Grpc-Previous-Rpc-Attempts"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			previousAttempts = int32(n)
+		}
+	}
+
+	// Attempts beyond the configured list repeat the last code
+	var code connect.Code
+	if n := int32(len(req.Msg.Codes)); n > 0 {
+		code = connect.Code(req.Msg.Codes[min(attempt, n)-1])
+		if code > 16 {
+			code = connect.CodeUnknown
+		}
+	}
+
+	if code != 0 {
+		connectErr := connect.NewError(code, fmt.Errorf("flaky failure on attempt %d of sequence %q", attempt, req.Msg.SequenceId))
+		connectErr.Meta().Set("X-Flaky-Attempt", strconv.Itoa(int(attempt)))
+		connectErr.Meta().Set("X-Flaky-Previous-Rpc-Attempts", strconv.Itoa(int(previousAttempts)))
+		return nil, connectErr
+	}
+
+	response := connect.NewResponse(&pb.EchoFlakyResponse{
+		Message:             req.Msg.Message,
+		SequenceId:          req.Msg.SequenceId,
+		Attempt:             attempt,
+		PreviousRpcAttempts: previousAttempts,
+	})
+	response.Trailer().Set("X-Flaky-Attempt", strconv.Itoa(int(attempt)))
+	response.Trailer().Set("X-Flaky-Previous-Rpc-Attempts", strconv.Itoa(int(previousAttempts)))
+
+	return response, nil
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+)
+
+func TestEchoFlaky_ReturnsCodesInOrder(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	msg := &pb.EchoFlakyRequest{
+		SequenceId: "order",
+		Codes:      []int32{int32(connect.CodeUnavailable), int32(connect.CodeUnavailable), 0},
+		Message:    "hello",
+	}
+
+	for _, attempt := range []string{"1", "2"} {
+		_, err := client.EchoFlaky(context.Background(), connect.NewRequest(msg))
+		var connectErr *connect.Error
+		if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeUnavailable {
+			t.Fatalf("attempt %s: expected Unavailable, got %v", attempt, err)
+		}
+		if got := connectErr.Meta().Get("X-Flaky-Attempt"); got != attempt {
+			t.Errorf("expected X-Flaky-Attempt=%s, got %q", attempt, got)
+		}
+	}
+
+	resp, err := client.EchoFlaky(context.Background(), connect.NewRequest(msg))
+	if err != nil {
+		t.Fatalf("attempt 3: expected success, got %v", err)
+	}
+	if resp.Msg.Attempt != 3 {
+		t.Errorf("expected attempt 3, got %d", resp.Msg.Attempt)
+	}
+	if resp.Msg.Message != "hello" {
+		t.Errorf("expected message %q, got %q", "hello", resp.Msg.Message)
+	}
+}
+
+func TestEchoFlaky_ReportsPreviousRPCAttempts(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	req := connect.NewRequest(&pb.EchoFlakyRequest{SequenceId: "header"})
+	req.Header().Set("Grpc-Previous-Rpc-Attempts", "2")
+
+	resp, err := client.EchoFlaky(context.Background(), req)
+	if err != nil {
+		t.Fatalf("EchoFlaky failed: %v", err)
+	}
+	if resp.Msg.PreviousRpcAttempts != 2 {
+		t.Errorf("expected previous_rpc_attempts 2, got %d", resp.Msg.PreviousRpcAttempts)
+	}
+}
+
+func TestEchoFlaky_ExpiresAfterTTL(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	msg := &pb.EchoFlakyRequest{
+		SequenceId: "ttl",
+		Codes:      []int32{int32(connect.CodeUnavailable), 0},
+		TtlMs:      50,
+	}
+
+	if _, err := client.EchoFlaky(context.Background(), connect.NewRequest(msg)); connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if _, err := client.EchoFlaky(context.Background(), connect.NewRequest(msg)); connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Fatalf("expected sequence to restart after TTL, got %v", err)
+	}
+}
+
+func TestEchoFlaky_RequiresSequenceID(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	_, err := client.EchoFlaky(context.Background(), connect.NewRequest(&pb.EchoFlakyRequest{}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestFlakyTracker_EvictsAtLimit(t *testing.T) {
+	tracker := newFlakyTracker()
+	tracker.limit = 2
+
+	tracker.next("short", time.Second)
+	tracker.next("long", time.Hour)
+	tracker.next("new", time.Hour)
+
+	if len(tracker.sequences) != 2 {
+		t.Fatalf("expected 2 tracked sequences, got %d", len(tracker.sequences))
+	}
+	if _, ok := tracker.sequences["short"]; ok {
+		t.Error("expected the sequence closest to expiry to be evicted")
+	}
+	if attempt := tracker.next("long", time.Hour); attempt != 2 {
+		t.Errorf("expected attempt 2 for a kept sequence, got %d", attempt)
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+)
+
+const (
+	defaultFloodCount = 1000
+	maxFloodCount     = 100000
+	maxFloodPayload   = 64 * 1024
+	maxSlowReadPause  = time.Minute
+)
+
+func (s *EchoServer) FloodStream(ctx context.Context, req *connect.Request[pb.FloodRequest], stream *connect.ServerStream[pb.FloodResponse]) error {
+	count, err := floodCount(req.Msg)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	payload := make([]byte, req.Msg.PayloadBytes)
+	var oversize []byte
+	if req.Msg.OversizeAt > 0 {
+		oversize = make([]byte, req.Msg.OversizeBytes)
+	}
+
+	// No pacing: Send only blocks once the client's flow-control window is
+	// full, which sent_at makes visible
+	for i := int32(1); i <= count; i++ {
+		if ctx.Err() != nil {
+			return connect.NewError(connect.CodeCanceled, fmt.Errorf("stream canceled"))
+		}
+
+		resp := &pb.FloodResponse{
+			Sequence: i,
+			SentAt:   timestamppb.Now(),
+			Payload:  payload,
+		}
+		if i == req.Msg.OversizeAt {
+			resp.Payload = oversize
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *EchoServer) SlowReadStream(ctx context.Context, stream *connect.BidiStream[pb.SlowReadRequest, pb.SlowReadResponse]) error {
+	var (
+		pauseAfter int32
+		pause      time.Duration
+	)
+
+	for sequence := int32(1); ; sequence++ {
+		req, err := stream.Receive()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		receivedAt := timestamppb.Now()
+
+		if sequence == 1 {
+			if pauseAfter, pause, err = slowReadOptions(req); err != nil {
+				return connect.NewError(connect.CodeInvalidArgument, err)
+			}
+		}
+
+		if err := stream.Send(&pb.SlowReadResponse{
+			Message:    req.Message,
+			Sequence:   sequence,
+			ReceivedAt: receivedAt,
+		}); err != nil {
+			return err
+		}
+
+		// Not reading lets the client's messages pile up until the
+		// flow-control window is exhausted and its sends block
+		if sequence == pauseAfter && pause > 0 {
+			select {
+			case <-time.After(pause):
+			case <-ctx.Done():
+				return connect.NewError(connect.CodeCanceled, fmt.Errorf("stream canceled"))
+			}
+		}
+	}
+}
+
+// floodCount validates a FloodStream request and returns the number of
+// messages to send.
+func floodCount(req *pb.FloodRequest) (int32, error) {
+	count := req.Count
+	if count == 0 {
+		count = defaultFloodCount
+	}
+	if count < 0 || count > maxFloodCount {
+		return 0, fmt.Errorf("count must be between 1 and %d", maxFloodCount)
+	}
+	if req.PayloadBytes < 0 || req.PayloadBytes > maxFloodPayload {
+		return 0, fmt.Errorf("payload_bytes must be between 0 and %d", maxFloodPayload)
+	}
+	if req.OversizeAt < 0 || req.OversizeAt > count {
+		return 0, errors.New("oversize_at must be between 0 and count")
+	}
+	if req.OversizeBytes < 0 || req.OversizeBytes > MaxPayloadSize {
+		return 0, fmt.Errorf("oversize_bytes must be between 0 and %d", MaxPayloadSize)
+	}
+	return count, nil
+}
+
+// slowReadOptions validates the options carried by the first SlowReadStream
+// message.
+func slowReadOptions(req *pb.SlowReadRequest) (int32, time.Duration, error) {
+	pauseAfter := req.PauseAfter
+	if pauseAfter == 0 {
+		pauseAfter = 1
+	}
+	if pauseAfter < 0 {
+		return 0, 0, errors.New("pause_after must not be negative")
+	}
+	pause := time.Duration(req.PauseMs) * time.Millisecond
+	if pause < 0 || pause > maxSlowReadPause {
+		return 0, 0, fmt.Errorf("pause_ms must be between 0 and %d", maxSlowReadPause.Milliseconds())
+	}
+	return pauseAfter, pause, nil
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+)
+
+// setupHTTP2TestServer serves the Echo handler over TLS with HTTP/2 so that
+// bidirectional streams work.
+func setupHTTP2TestServer(t *testing.T) protoconnect.EchoClient {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewEchoHandler(NewEchoServer()))
+
+	server := httptest.NewUnstartedServer(mux)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return protoconnect.NewEchoClient(server.Client(), server.URL)
+}
+
+func TestFloodStream_SendsAllMessages(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	stream, err := client.FloodStream(context.Background(), connect.NewRequest(&pb.FloodRequest{
+		Count:        5000,
+		PayloadBytes: 16,
+	}))
+	if err != nil {
+		t.Fatalf("FloodStream failed: %v", err)
+	}
+
+	var received int32
+	for stream.Receive() {
+		received++
+		resp := stream.Msg()
+		if resp.Sequence != received {
+			t.Fatalf("expected sequence %d, got %d", received, resp.Sequence)
+		}
+		if len(resp.Payload) != 16 {
+			t.Fatalf("expected 16-byte payload, got %d", len(resp.Payload))
+		}
+		if resp.SentAt == nil {
+			t.Fatal("expected sent_at to be set")
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+
+	if received != 5000 {
+		t.Errorf("expected 5000 messages, got %d", received)
+	}
+}
+
+func TestFloodStream_OversizeMessage(t *testing.T) {
+	_, server := setupTestServer(t)
+	defer server.Close()
+
+	client := protoconnect.NewEchoClient(http.DefaultClient, server.URL, connect.WithReadMaxBytes(1024))
+	stream, err := client.FloodStream(context.Background(), connect.NewRequest(&pb.FloodRequest{
+		Count:         5,
+		PayloadBytes:  16,
+		OversizeAt:    3,
+		OversizeBytes: 4096,
+	}))
+	if err != nil {
+		t.Fatalf("FloodStream failed: %v", err)
+	}
+
+	var received int
+	for stream.Receive() {
+		received++
+	}
+	if connect.CodeOf(stream.Err()) != connect.CodeResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", stream.Err())
+	}
+
+	if received != 2 {
+		t.Errorf("expected 2 messages before the oversize one, got %d", received)
+	}
+}
+
+func TestFloodStream_InvalidArgument(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		req  *pb.FloodRequest
+	}{
+		{name: "count over max", req: &pb.FloodRequest{Count: maxFloodCount + 1}},
+		{name: "payload over max", req: &pb.FloodRequest{PayloadBytes: maxFloodPayload + 1}},
+		{name: "oversize_at past count", req: &pb.FloodRequest{Count: 2, OversizeAt: 3}},
+		{name: "oversize_bytes over max", req: &pb.FloodRequest{OversizeAt: 1, OversizeBytes: MaxPayloadSize + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.FloodStream(context.Background(), connect.NewRequest(tt.req))
+			if err != nil {
+				t.Fatalf("FloodStream failed: %v", err)
+			}
+			for stream.Receive() {
+			}
+			if connect.CodeOf(stream.Err()) != connect.CodeInvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", stream.Err())
+			}
+		})
+	}
+}
+
+func TestSlowReadStream_PausesReading(t *testing.T) {
+	client := setupHTTP2TestServer(t)
+
+	stream := client.SlowReadStream(context.Background())
+
+	requests := []*pb.SlowReadRequest{
+		{Message: "one", PauseAfter: 1, PauseMs: 200},
+		{Message: "two"},
+		{Message: "three"},
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if err := stream.CloseRequest(); err != nil {
+		t.Fatalf("CloseRequest failed: %v", err)
+	}
+
+	var responses []*pb.SlowReadResponse
+	for {
+		resp, err := stream.Receive()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Receive failed: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	if err := stream.CloseResponse(); err != nil {
+		t.Fatalf("CloseResponse failed: %v", err)
+	}
+
+	if len(responses) != 3 {
+		t.Fatalf("expected 3 responses, got %d", len(responses))
+	}
+	for i, resp := range responses {
+		if resp.Sequence != int32(i+1) || resp.Message != requests[i].Message {
+			t.Errorf("expected %d %q, got %d %q", i+1, requests[i].Message, resp.Sequence, resp.Message)
+		}
+	}
+
+	gap := responses[1].ReceivedAt.AsTime().Sub(responses[0].ReceivedAt.AsTime())
+	if gap < 200*time.Millisecond {
+		t.Errorf("expected reading to pause for 200ms, second message read after %s", gap)
+	}
+}
+
+func TestSlowReadStream_InvalidArgument(t *testing.T) {
+	client := setupHTTP2TestServer(t)
+
+	stream := client.SlowReadStream(context.Background())
+	if err := stream.Send(&pb.SlowReadRequest{PauseMs: -1}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if _, err := stream.Receive(); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+	_ = stream.CloseResponse()
+}
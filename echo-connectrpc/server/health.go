@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// HealthServiceName is the fully-qualified name of the gRPC health service.
+	HealthServiceName = "grpc.health.v1.Health"
+
+	healthCheckProcedure = "/grpc.health.v1.Health/Check"
+	healthWatchProcedure = "/grpc.health.v1.Health/Watch"
+)
+
+// ErrHealthShutdown is returned by Schedule once Shutdown has been called.
+var ErrHealthShutdown = errors.New("health server is shutting down")
+
+// HealthStep is one entry of a health schedule: Status is reported for
+// Duration before moving on to the next step.
+type HealthStep struct {
+	Status   healthpb.HealthCheckResponse_ServingStatus
+	Duration time.Duration
+}
+
+// healthSchedule is closed to stop a running schedule.
+type healthSchedule struct {
+	stop chan struct{}
+}
+
+// HealthServer implements the gRPC health checking protocol, including Watch,
+// with statuses that can be changed at runtime.
+type HealthServer struct {
+	mu        sync.RWMutex
+	services  map[string]healthpb.HealthCheckResponse_ServingStatus
+	watchers  map[string]map[chan healthpb.HealthCheckResponse_ServingStatus]struct{}
+	schedules map[string]*healthSchedule
+	shutdown  bool
+}
+
+// NewHealthServer creates a new health server with default services.
+func NewHealthServer() *HealthServer {
+	h := &HealthServer{
+		services:  make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+		watchers:  make(map[string]map[chan healthpb.HealthCheckResponse_ServingStatus]struct{}),
+		schedules: make(map[string]*healthSchedule),
+	}
+
+	// Set overall server status (empty service name = overall status)
+	h.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	// Set Echo service status
+	h.SetServingStatus("echo.v1.Echo", healthpb.HealthCheckResponse_SERVING)
+
+	return h
+}
+
+// NewHealthHandler builds an HTTP handler serving grpc.health.v1.Health from h.
+func NewHealthHandler(h *HealthServer, opts ...connect.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle(healthCheckProcedure, connect.NewUnaryHandler(healthCheckProcedure, h.Check, opts...))
+	mux.Handle(healthWatchProcedure, connect.NewServerStreamHandler(healthWatchProcedure, h.Watch, opts...))
+	return "/" + HealthServiceName + "/", mux
+}
+
+func (h *HealthServer) Check(_ context.Context, req *connect.Request[healthpb.HealthCheckRequest]) (*connect.Response[healthpb.HealthCheckResponse], error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	status, ok := h.services[req.Msg.Service]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("unknown service %q", req.Msg.Service))
+	}
+	return connect.NewResponse(&healthpb.HealthCheckResponse{Status: status}), nil
+}
+
+// Watch sends the current status of the service and then every change until
+// the client goes away. Unknown services are reported as SERVICE_UNKNOWN.
+func (h *HealthServer) Watch(ctx context.Context, req *connect.Request[healthpb.HealthCheckRequest], stream *connect.ServerStream[healthpb.HealthCheckResponse]) error {
+	service := req.Msg.Service
+
+	// A buffer of one keeps only the latest status for slow clients
+	update := make(chan healthpb.HealthCheckResponse_ServingStatus, 1)
+
+	h.mu.Lock()
+	status, ok := h.services[service]
+	if !ok {
+		status = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	update <- status
+	if h.watchers[service] == nil {
+		h.watchers[service] = make(map[chan healthpb.HealthCheckResponse_ServingStatus]struct{})
+	}
+	h.watchers[service][update] = struct{}{}
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.watchers[service], update)
+		if len(h.watchers[service]) == 0 {
+			delete(h.watchers, service)
+		}
+		h.mu.Unlock()
+	}()
+
+	var last healthpb.HealthCheckResponse_ServingStatus = -1
+	for {
+		select {
+		case <-ctx.Done():
+			return connect.NewError(connect.CodeCanceled, ctx.Err())
+		case status := <-update:
+			if status == last {
+				continue
+			}
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: status}); err != nil {
+				return err
+			}
+			last = status
+		}
+	}
+}
+
+// SetServingStatus updates the serving status for a service, stopping any
+// schedule running for it.
+func (h *HealthServer) SetServingStatus(service string, status healthpb.HealthCheckResponse_ServingStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopScheduleLocked(service)
+	h.setLocked(service, status)
+}
+
+// GetServingStatus returns the current serving status for a service.
+func (h *HealthServer) GetServingStatus(service string) healthpb.HealthCheckResponse_ServingStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if status, ok := h.services[service]; ok {
+		return status
+	}
+	return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+}
+
+// Schedule replaces any schedule running for service. The first step takes
+// effect immediately and each following step after the previous one's
+// Duration; with repeat the schedule loops until replaced. It returns the
+// status the service had before the call.
+func (h *HealthServer) Schedule(service string, steps []HealthStep, repeat bool) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.shutdown {
+		return 0, ErrHealthShutdown
+	}
+
+	previous, ok := h.services[service]
+	if !ok {
+		previous = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+
+	h.stopScheduleLocked(service)
+	if len(steps) == 0 {
+		return previous, nil
+	}
+	h.setLocked(service, steps[0].Status)
+
+	if len(steps) > 1 || repeat {
+		schedule := &healthSchedule{stop: make(chan struct{})}
+		h.schedules[service] = schedule
+		go h.runSchedule(service, schedule, steps, repeat)
+	}
+
+	return previous, nil
+}
+
+func (h *HealthServer) runSchedule(service string, schedule *healthSchedule, steps []HealthStep, repeat bool) {
+	defer func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.schedules[service] == schedule {
+			delete(h.schedules, service)
+		}
+	}()
+
+	for i := 0; ; {
+		timer := time.NewTimer(steps[i].Duration)
+		select {
+		case <-schedule.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		i++
+		if i == len(steps) {
+			if !repeat {
+				return
+			}
+			i = 0
+		}
+
+		// Re-check under the lock so a replaced schedule never overwrites
+		// the status set by its successor
+		h.mu.Lock()
+		select {
+		case <-schedule.stop:
+			h.mu.Unlock()
+			return
+		default:
+		}
+		h.setLocked(service, steps[i].Status)
+		h.mu.Unlock()
+	}
+}
+
+// Shutdown stops all schedules and sets all services to NOT_SERVING status.
+func (h *HealthServer) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.shutdown = true
+	for service := range h.schedules {
+		h.stopScheduleLocked(service)
+	}
+	for service := range h.services {
+		h.setLocked(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+func (h *HealthServer) setLocked(service string, status healthpb.HealthCheckResponse_ServingStatus) {
+	h.services[service] = status
+	for update := range h.watchers[service] {
+		// Replace a status the watcher has not picked up yet
+		select {
+		case <-update:
+		default:
+		}
+		update <- status
+	}
+}
+
+func (h *HealthServer) stopScheduleLocked(service string) {
+	if schedule, ok := h.schedules[service]; ok {
+		close(schedule.stop)
+		delete(h.schedules, service)
+	}
+}
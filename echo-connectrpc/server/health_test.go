@@ -0,0 +1,135 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestNewHealthServer_SetsInitialServingStatus(t *testing.T) {
+	h := NewHealthServer()
+
+	// Overall server status should be SERVING
+	if status := h.GetServingStatus(""); status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected overall status SERVING, got %v", status)
+	}
+
+	// Echo service status should be SERVING
+	if status := h.GetServingStatus("echo.v1.Echo"); status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected echo.v1.Echo status SERVING, got %v", status)
+	}
+}
+
+func TestHealthServer_SetServingStatus(t *testing.T) {
+	h := NewHealthServer()
+
+	h.SetServingStatus("test.service", healthpb.HealthCheckResponse_SERVING)
+	if status := h.GetServingStatus("test.service"); status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING, got %v", status)
+	}
+
+	h.SetServingStatus("test.service", healthpb.HealthCheckResponse_NOT_SERVING)
+	if status := h.GetServingStatus("test.service"); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING, got %v", status)
+	}
+}
+
+func TestHealthServer_GetServingStatus_UnknownService(t *testing.T) {
+	h := NewHealthServer()
+
+	status := h.GetServingStatus("unknown.service")
+	if status != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Errorf("expected SERVICE_UNKNOWN for unregistered service, got %v", status)
+	}
+}
+
+func TestHealthServer_Shutdown(t *testing.T) {
+	h := NewHealthServer()
+
+	// Add additional service
+	h.SetServingStatus("test.service", healthpb.HealthCheckResponse_SERVING)
+
+	// Shutdown
+	h.Shutdown()
+
+	// All services should be NOT_SERVING after shutdown
+	if status := h.GetServingStatus(""); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected overall status NOT_SERVING after shutdown, got %v", status)
+	}
+	if status := h.GetServingStatus("echo.v1.Echo"); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected echo.v1.Echo status NOT_SERVING after shutdown, got %v", status)
+	}
+	if status := h.GetServingStatus("test.service"); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected test.service status NOT_SERVING after shutdown, got %v", status)
+	}
+}
+
+func TestHealthServer_Schedule(t *testing.T) {
+	h := NewHealthServer()
+
+	previous, err := h.Schedule("test.service", []HealthStep{
+		{Status: healthpb.HealthCheckResponse_NOT_SERVING, Duration: 20 * time.Millisecond},
+		{Status: healthpb.HealthCheckResponse_SERVING},
+	}, false)
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	if previous != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Errorf("expected previous status SERVICE_UNKNOWN, got %v", previous)
+	}
+
+	// The first step applies immediately
+	if status := h.GetServingStatus("test.service"); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING, got %v", status)
+	}
+
+	waitForStatus(t, h, "test.service", healthpb.HealthCheckResponse_SERVING)
+}
+
+func TestHealthServer_SetServingStatus_StopsSchedule(t *testing.T) {
+	h := NewHealthServer()
+
+	_, _ = h.Schedule("test.service", []HealthStep{
+		{Status: healthpb.HealthCheckResponse_NOT_SERVING, Duration: 10 * time.Millisecond},
+		{Status: healthpb.HealthCheckResponse_SERVING, Duration: 10 * time.Millisecond},
+	}, true)
+	h.SetServingStatus("test.service", healthpb.HealthCheckResponse_UNKNOWN)
+
+	time.Sleep(50 * time.Millisecond)
+	if status := h.GetServingStatus("test.service"); status != healthpb.HealthCheckResponse_UNKNOWN {
+		t.Errorf("expected the schedule to stop at UNKNOWN, got %v", status)
+	}
+}
+
+func TestHealthServer_Shutdown_StopsSchedules(t *testing.T) {
+	h := NewHealthServer()
+
+	_, _ = h.Schedule("", []HealthStep{
+		{Status: healthpb.HealthCheckResponse_SERVING, Duration: 10 * time.Millisecond},
+		{Status: healthpb.HealthCheckResponse_SERVING, Duration: 10 * time.Millisecond},
+	}, true)
+	h.Shutdown()
+
+	time.Sleep(50 * time.Millisecond)
+	if status := h.GetServingStatus(""); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING after shutdown, got %v", status)
+	}
+
+	if _, err := h.Schedule("", []HealthStep{{Status: healthpb.HealthCheckResponse_SERVING}}, false); err != ErrHealthShutdown {
+		t.Errorf("expected ErrHealthShutdown, got %v", err)
+	}
+}
+
+func waitForStatus(t *testing.T, h *HealthServer, service string, want healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if h.GetServingStatus(service) == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("expected %s to become %v, got %v", service, want, h.GetServingStatus(service))
+}
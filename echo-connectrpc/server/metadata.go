@@ -0,0 +1,55 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+)
+
+// headerFromEntries builds headers from entries, keeping repeated keys as
+// multiple values in order. Values of "-bin" keys are base64-encoded as the
+// Connect and gRPC protocols require.
+func headerFromEntries(entries []*pb.MetadataEntry) (http.Header, error) {
+	header := make(http.Header)
+	for _, entry := range entries {
+		key := strings.ToLower(entry.Key)
+		if err := validateMetadataKey(key); err != nil {
+			return nil, err
+		}
+
+		value := entry.Value
+		if strings.HasSuffix(key, "-bin") {
+			raw := []byte(entry.Value)
+			if len(entry.BinaryValue) > 0 {
+				raw = entry.BinaryValue
+			}
+			value = connect.EncodeBinaryHeader(raw)
+		} else if len(entry.BinaryValue) > 0 {
+			return nil, fmt.Errorf("binary_value requires a key ending in -bin, got %q", key)
+		}
+		header.Add(key, value)
+	}
+	return header, nil
+}
+
+// validateMetadataKey rejects keys that are not valid gRPC metadata keys or
+// that are reserved for the protocol.
+func validateMetadataKey(key string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	if strings.HasPrefix(key, "grpc-") || strings.HasPrefix(key, "connect-") {
+		return fmt.Errorf("reserved key %q", key)
+	}
+	for _, c := range key {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' && c != '.' {
+			return fmt.Errorf("invalid character %q in key %q", c, key)
+		}
+	}
+	return nil
+}
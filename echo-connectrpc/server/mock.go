@@ -0,0 +1,637 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"gopkg.in/yaml.v3"
+)
+
+// MockRules answers calls matching a rule with a canned response instead of
+// the real handler. Rules are tried in file order and the first match wins.
+type MockRules struct {
+	methods map[string]*mockMethod
+}
+
+type mockMethod struct {
+	desc  protoreflect.MethodDescriptor
+	input protoreflect.MessageType
+	rules []*mockRule
+}
+
+type mockRule struct {
+	request   []mockFieldMatcher
+	metadata  map[string]*mockMatcher
+	delay     time.Duration
+	headers   http.Header
+	trailers  http.Header
+	responses []*dynamicpb.Message
+	status    *mockStatus
+}
+
+// mockStatus builds a fresh error for each call, since connect errors carry
+// mutable metadata.
+type mockStatus struct {
+	code    connect.Code
+	message string
+	details []*connect.ErrorDetail
+}
+
+func (s *mockStatus) err() *connect.Error {
+	err := connect.NewError(s.code, errors.New(s.message))
+	for _, detail := range s.details {
+		err.AddDetail(detail)
+	}
+	return err
+}
+
+// mockRulesFile is the rules file, in YAML or JSON.
+type mockRulesFile struct {
+	Rules []mockRuleSpec `json:"rules"`
+}
+
+type mockRuleSpec struct {
+	Method    string                  `json:"method"`
+	Request   map[string]*mockMatcher `json:"request"`
+	Metadata  map[string]*mockMatcher `json:"metadata"`
+	Delay     string                  `json:"delay"`
+	Headers   map[string]string       `json:"headers"`
+	Trailers  map[string]string       `json:"trailers"`
+	Response  json.RawMessage         `json:"response"`
+	Responses []json.RawMessage       `json:"responses"`
+	Status    *mockStatusSpec         `json:"status"`
+}
+
+type mockStatusSpec struct {
+	Code    mockCode          `json:"code"`
+	Message string            `json:"message"`
+	Details []json.RawMessage `json:"details"`
+}
+
+// mockCode is a status code given as a number or a gRPC name, such as 5 or
+// NOT_FOUND. Zero is OK.
+type mockCode connect.Code
+
+func (c *mockCode) UnmarshalJSON(data []byte) error {
+	var number uint32
+	if err := json.Unmarshal(data, &number); err == nil {
+		if number > uint32(connect.CodeUnauthenticated) {
+			return fmt.Errorf("invalid code %d", number)
+		}
+		*c = mockCode(number)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	if strings.EqualFold(name, "OK") {
+		*c = 0
+		return nil
+	}
+	var code connect.Code
+	if err := code.UnmarshalText([]byte(strings.ToLower(name))); err != nil {
+		return err
+	}
+	*c = mockCode(code)
+	return nil
+}
+
+// mockMatcher matches a value exactly, given as a scalar or {equals: ...},
+// or by regular expression, given as {regex: ...}.
+type mockMatcher struct {
+	equals *string
+	regex  *regexp.Regexp
+}
+
+func (m *mockMatcher) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var spec struct {
+			Equals json.RawMessage `json:"equals"`
+			Regex  *string         `json:"regex"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&spec); err != nil {
+			return err
+		}
+		if (spec.Equals != nil) == (spec.Regex != nil) {
+			return errors.New("matcher needs exactly one of equals and regex")
+		}
+		if spec.Regex != nil {
+			re, err := regexp.Compile(*spec.Regex)
+			if err != nil {
+				return err
+			}
+			m.regex = re
+			return nil
+		}
+		data = spec.Equals
+	}
+
+	// Scalars compare as text with the formatted field value
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+	switch value.(type) {
+	case map[string]any, []any, nil:
+		return fmt.Errorf("matcher value must be a scalar, got %s", data)
+	}
+	equals := fmt.Sprint(value)
+	m.equals = &equals
+	return nil
+}
+
+func (m *mockMatcher) match(value string) bool {
+	if m.regex != nil {
+		return m.regex.MatchString(value)
+	}
+	return *m.equals == value
+}
+
+// mockFieldMatcher matches the scalar at a dotted field path of the request.
+type mockFieldMatcher struct {
+	path    mockFieldPath
+	matcher *mockMatcher
+}
+
+// mockFieldPath walks fields by proto or JSON name. A list index or map key
+// follows a repeated or map field, e.g. "tags.0" or "labels.env".
+type mockFieldPath []mockPathSegment
+
+type mockPathSegment struct {
+	field protoreflect.FieldDescriptor
+	index int
+	key   protoreflect.MapKey
+}
+
+func compileFieldPath(md protoreflect.MessageDescriptor, path string) (mockFieldPath, error) {
+	names := strings.Split(path, ".")
+	var fp mockFieldPath
+	var elem protoreflect.FieldDescriptor
+	for i := 0; i < len(names); i++ {
+		if md == nil {
+			return nil, fmt.Errorf("%s: %s is not a message", path, elem.FullName())
+		}
+		fd := md.Fields().ByName(protoreflect.Name(names[i]))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(names[i])
+		}
+		if fd == nil {
+			return nil, fmt.Errorf("%s: no field %q in %s", path, names[i], md.FullName())
+		}
+
+		seg := mockPathSegment{field: fd}
+		elem = fd
+		if fd.IsList() || fd.IsMap() {
+			if i+1 == len(names) {
+				return nil, fmt.Errorf("%s: %s needs an index or key", path, fd.Name())
+			}
+			i++
+			if fd.IsList() {
+				index, err := strconv.Atoi(names[i])
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("%s: invalid index %q", path, names[i])
+				}
+				seg.index = index
+			} else {
+				key, err := parseMapKey(fd.MapKey(), names[i])
+				if err != nil {
+					return nil, fmt.Errorf("%s: invalid key %q: %w", path, names[i], err)
+				}
+				seg.key = key
+				elem = fd.MapValue()
+			}
+		}
+		fp = append(fp, seg)
+		md = elem.Message()
+	}
+	if md != nil {
+		return nil, fmt.Errorf("%s: %s is a message, not a scalar", path, elem.FullName())
+	}
+	return fp, nil
+}
+
+func parseMapKey(fd protoreflect.FieldDescriptor, s string) (protoreflect.MapKey, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s).MapKey(), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(b).MapKey(), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)).MapKey(), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)).MapKey(), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(n).MapKey(), err
+	default:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(n).MapKey(), err
+	}
+}
+
+// value formats the scalar at the path like protojson, except that strings
+// are unquoted. Missing list elements and map entries have no value.
+func (p mockFieldPath) value(m protoreflect.Message) (string, bool) {
+	var fd protoreflect.FieldDescriptor
+	var v protoreflect.Value
+	for _, seg := range p {
+		fd = seg.field
+		v = m.Get(fd)
+		switch {
+		case fd.IsList():
+			if seg.index >= v.List().Len() {
+				return "", false
+			}
+			v = v.List().Get(seg.index)
+		case fd.IsMap():
+			if !v.Map().Has(seg.key) {
+				return "", false
+			}
+			v = v.Map().Get(seg.key)
+			fd = fd.MapValue()
+		}
+		if fd.Message() != nil {
+			m = v.Message()
+		}
+	}
+
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name()), true
+		}
+		return strconv.Itoa(int(v.Enum())), true
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes()), true
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), true
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+	}
+	return v.String(), true
+}
+
+// LoadMockRules reads a rules file. Methods, request fields and responses
+// are resolved against the compiled-in services.
+func LoadMockRules(path string) (*MockRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON; converting it to JSON lets responses be
+	// parsed with protojson
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var file mockRulesFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	r := &MockRules{methods: make(map[string]*mockMethod)}
+	for i, spec := range file.Rules {
+		if err := r.add(spec); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", path, i+1, err)
+		}
+	}
+	return r, nil
+}
+
+func (r *MockRules) add(spec mockRuleSpec) error {
+	// Accept both "/pkg.Service/Method" and "pkg.Service/Method"
+	procedure := "/" + strings.TrimPrefix(spec.Method, "/")
+	service, name, ok := strings.Cut(procedure[1:], "/")
+	if !ok {
+		return fmt.Errorf("invalid method %q", spec.Method)
+	}
+
+	method, ok := r.methods[procedure]
+	if !ok {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+		sd, isService := d.(protoreflect.ServiceDescriptor)
+		if err != nil || !isService {
+			return fmt.Errorf("unknown service %q", service)
+		}
+		md := sd.Methods().ByName(protoreflect.Name(name))
+		if md == nil {
+			return fmt.Errorf("unknown method %q", spec.Method)
+		}
+		method = &mockMethod{desc: md, input: dynamicpb.NewMessageType(md.Input())}
+		r.methods[procedure] = method
+	}
+
+	rule := &mockRule{metadata: make(map[string]*mockMatcher)}
+	for path, matcher := range spec.Request {
+		fp, err := compileFieldPath(method.desc.Input(), path)
+		if err != nil {
+			return err
+		}
+		rule.request = append(rule.request, mockFieldMatcher{path: fp, matcher: matcher})
+	}
+	for key, matcher := range spec.Metadata {
+		rule.metadata[key] = matcher
+	}
+
+	if spec.Delay != "" {
+		delay, err := time.ParseDuration(spec.Delay)
+		if err != nil {
+			return fmt.Errorf("invalid delay: %w", err)
+		}
+		rule.delay = delay
+	}
+	rule.headers = make(http.Header)
+	for key, value := range spec.Headers {
+		rule.headers.Set(key, value)
+	}
+	rule.trailers = make(http.Header)
+	for key, value := range spec.Trailers {
+		rule.trailers.Set(key, value)
+	}
+
+	raw := spec.Responses
+	if spec.Response != nil {
+		if raw != nil {
+			return errors.New("use either response or responses")
+		}
+		raw = []json.RawMessage{spec.Response}
+	}
+	if len(raw) > 1 && !method.desc.IsStreamingServer() {
+		return fmt.Errorf("%s returns a single response", spec.Method)
+	}
+	if len(raw) == 0 {
+		raw = []json.RawMessage{[]byte("{}")}
+	}
+	// Responses are dynamic messages, since interceptors cannot name the
+	// handler's response type
+	for _, data := range raw {
+		resp := dynamicpb.NewMessage(method.desc.Output())
+		if err := protojson.Unmarshal(data, resp); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		rule.responses = append(rule.responses, resp)
+	}
+
+	if spec.Status != nil && spec.Status.Code != 0 {
+		rule.status = &mockStatus{code: connect.Code(spec.Status.Code), message: spec.Status.Message}
+		for _, data := range spec.Status.Details {
+			detail := &anypb.Any{}
+			if err := protojson.Unmarshal(data, detail); err != nil {
+				return fmt.Errorf("invalid status detail: %w", err)
+			}
+			msg, err := detail.UnmarshalNew()
+			if err != nil {
+				return fmt.Errorf("invalid status detail: %w", err)
+			}
+			errDetail, err := connect.NewErrorDetail(msg)
+			if err != nil {
+				return fmt.Errorf("invalid status detail: %w", err)
+			}
+			rule.status.details = append(rule.status.details, errDetail)
+		}
+	}
+
+	method.rules = append(method.rules, rule)
+	return nil
+}
+
+func (r *mockRule) matchMetadata(header http.Header) bool {
+	for key, matcher := range r.metadata {
+		matched := false
+		for _, value := range header.Values(key) {
+			if matcher.match(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *mockRule) matchRequest(req proto.Message) bool {
+	m := req.ProtoReflect()
+	for _, fm := range r.request {
+		value, ok := fm.path.value(m)
+		if !ok || !fm.matcher.match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *mockRule) wait(ctx context.Context) error {
+	if r.delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(r.delay):
+		return nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return connect.NewError(connect.CodeDeadlineExceeded, ctx.Err())
+		}
+		return connect.NewError(connect.CodeCanceled, ctx.Err())
+	}
+}
+
+// statusErr returns the rule's error with its trailers, or nil if the
+// status is OK.
+func (r *mockRule) statusErr() *connect.Error {
+	if r.status == nil {
+		return nil
+	}
+	err := r.status.err()
+	mergeHeaders(err.Meta(), r.trailers)
+	return err
+}
+
+func mergeHeaders(dst, src http.Header) {
+	for key, values := range src {
+		dst[key] = append(dst[key], values...)
+	}
+}
+
+// Interceptor answers calls matching a rule with its responses, then its
+// status. Unary calls get the status instead of the response if it is not
+// OK. Request fields of streaming calls are matched against the first
+// request, which is passed on to the handler if no rule matches.
+func (r *MockRules) Interceptor() connect.Interceptor {
+	return &mockInterceptor{rules: r}
+}
+
+type mockInterceptor struct {
+	rules *MockRules
+}
+
+func (i *mockInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		method, ok := i.rules.methods[req.Spec().Procedure]
+		if req.Spec().IsClient || !ok {
+			return next(ctx, req)
+		}
+
+		for _, rule := range method.rules {
+			if !rule.matchMetadata(req.Header()) || !rule.matchRequest(req.Any().(proto.Message)) {
+				continue
+			}
+
+			if err := rule.wait(ctx); err != nil {
+				return nil, err
+			}
+			// Unary errors have no separate headers
+			if err := rule.statusErr(); err != nil {
+				mergeHeaders(err.Meta(), rule.headers)
+				return nil, err
+			}
+			resp := connect.NewResponse(proto.Clone(rule.responses[0]).(*dynamicpb.Message))
+			mergeHeaders(resp.Header(), rule.headers)
+			mergeHeaders(resp.Trailer(), rule.trailers)
+			return resp, nil
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *mockInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *mockInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		method, ok := i.rules.methods[conn.Spec().Procedure]
+		if !ok {
+			return next(ctx, conn)
+		}
+
+		var peeked *peekedConn
+		for _, rule := range method.rules {
+			if !rule.matchMetadata(conn.RequestHeader()) {
+				continue
+			}
+			if len(rule.request) > 0 {
+				if peeked == nil {
+					peeked = &peekedConn{StreamingHandlerConn: conn, msg: method.input.New().Interface()}
+					peeked.err = conn.Receive(peeked.msg)
+				}
+				if peeked.err != nil || !rule.matchRequest(peeked.msg) {
+					continue
+				}
+			}
+			return rule.serveStream(ctx, conn, method)
+		}
+
+		if peeked != nil {
+			return next(ctx, peeked)
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (r *mockRule) serveStream(ctx context.Context, conn connect.StreamingHandlerConn, method *mockMethod) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	mergeHeaders(conn.ResponseHeader(), r.headers)
+
+	// Client streaming methods reply once the client is done, unless the
+	// status replaces the reply
+	if !method.desc.IsStreamingServer() {
+		if err := drainStream(conn, method.input); err != nil {
+			return err
+		}
+		if err := r.statusErr(); err != nil {
+			return err
+		}
+		mergeHeaders(conn.ResponseTrailer(), r.trailers)
+		return conn.Send(r.responses[0])
+	}
+
+	for _, resp := range r.responses {
+		if err := conn.Send(resp); err != nil {
+			return err
+		}
+	}
+	if method.desc.IsStreamingClient() {
+		if err := drainStream(conn, method.input); err != nil {
+			return err
+		}
+	}
+	if err := r.statusErr(); err != nil {
+		return err
+	}
+	mergeHeaders(conn.ResponseTrailer(), r.trailers)
+	return nil
+}
+
+// drainStream reads requests until the client closes its side.
+func drainStream(conn connect.StreamingHandlerConn, input protoreflect.MessageType) error {
+	for {
+		err := conn.Receive(input.New().Interface())
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// peekedConn replays the request an interceptor read before the handler.
+type peekedConn struct {
+	connect.StreamingHandlerConn
+	msg      proto.Message
+	err      error
+	replayed bool
+}
+
+func (c *peekedConn) Receive(m any) error {
+	if c.replayed {
+		return c.StreamingHandlerConn.Receive(m)
+	}
+	c.replayed = true
+	if c.err != nil {
+		return c.err
+	}
+	data, err := proto.Marshal(c.msg)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, m.(proto.Message))
+}
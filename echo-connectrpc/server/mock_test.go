@@ -0,0 +1,272 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+)
+
+const mockRulesYAML = `rules:
+  - method: echo.v1.Echo/Echo
+    request:
+      message: hello
+    metadata:
+      x-user: alice
+    headers:
+      x-mock: "true"
+    trailers:
+      x-mock-rule: greet
+    response:
+      message: mocked hello
+  - method: /echo.v1.Echo/Echo
+    request:
+      message: {regex: "^missing-"}
+    headers:
+      x-mock: "true"
+    status:
+      code: NOT_FOUND
+      message: no such message
+      details:
+        - "@type": type.googleapis.com/google.rpc.ErrorInfo
+          reason: MESSAGE_NOT_FOUND
+          domain: echo.example.com
+  - method: echo.v1.Echo/EchoWithDelay
+    delay: 1s
+  - method: echo.v1.Echo/ServerStream
+    request:
+      count: 2
+      intervalMs: 0
+    responses:
+      - message: first
+      - message: second
+    status:
+      code: 14
+      message: stream interrupted
+  - method: echo.v1.Echo/ClientStream
+    request:
+      message: mock
+      failure.after: 0
+    response:
+      message: mocked stream
+`
+
+// setupMockTestServer serves the Echo handler over HTTP/2 with the mock
+// interceptor for the given rules.
+func setupMockTestServer(t *testing.T, rulesYAML string) *httptest.Server {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(rulesYAML), 0o600); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+	rules, err := LoadMockRules(path)
+	if err != nil {
+		t.Fatalf("LoadMockRules failed: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewEchoHandler(NewEchoServer(), connect.WithInterceptors(rules.Interceptor())))
+
+	server := httptest.NewUnstartedServer(mux)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMock_UnaryResponse(t *testing.T) {
+	server := setupMockTestServer(t, mockRulesYAML)
+
+	protocols := map[string][]connect.ClientOption{
+		"connect":  nil,
+		"grpc":     {connect.WithGRPC()},
+		"grpc-web": {connect.WithGRPCWeb()},
+	}
+	tests := []struct {
+		name     string
+		message  string
+		user     string
+		expected string
+		mocked   bool
+	}{
+		{name: "matching rule", message: "hello", user: "alice", expected: "mocked hello", mocked: true},
+		{name: "metadata mismatch", message: "hello", user: "bob", expected: "hello"},
+		{name: "request mismatch", message: "bye", user: "alice", expected: "bye"},
+	}
+
+	for protocol, opts := range protocols {
+		client := protoconnect.NewEchoClient(server.Client(), server.URL, opts...)
+		for _, tt := range tests {
+			t.Run(protocol+" "+tt.name, func(t *testing.T) {
+				req := connect.NewRequest(&pb.EchoRequest{Message: tt.message})
+				req.Header().Set("X-User", tt.user)
+
+				resp, err := client.Echo(context.Background(), req)
+				if err != nil {
+					t.Fatalf("Echo failed: %v", err)
+				}
+				if resp.Msg.Message != tt.expected {
+					t.Errorf("expected message %q, got %q", tt.expected, resp.Msg.Message)
+				}
+
+				if tt.mocked {
+					if got := resp.Header().Get("X-Mock"); got != "true" {
+						t.Errorf("expected header X-Mock, got %v", resp.Header())
+					}
+					if got := resp.Trailer().Get("X-Mock-Rule"); got != "greet" {
+						t.Errorf("expected trailer X-Mock-Rule, got %v", resp.Trailer())
+					}
+				} else if resp.Header().Get("X-Mock") != "" {
+					t.Errorf("expected no X-Mock header, got %v", resp.Header())
+				}
+			})
+		}
+	}
+}
+
+func TestMock_UnaryStatus(t *testing.T) {
+	server := setupMockTestServer(t, mockRulesYAML)
+	client := protoconnect.NewEchoClient(server.Client(), server.URL)
+
+	_, err := client.Echo(context.Background(), connect.NewRequest(&pb.EchoRequest{Message: "missing-42"}))
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("expected connect error, got %v", err)
+	}
+	if connectErr.Code() != connect.CodeNotFound || connectErr.Message() != "no such message" {
+		t.Fatalf("expected NotFound 'no such message', got %v", err)
+	}
+	if got := connectErr.Meta().Get("X-Mock"); got != "true" {
+		t.Errorf("expected X-Mock in error metadata, got %v", connectErr.Meta())
+	}
+
+	details := connectErr.Details()
+	if len(details) != 1 {
+		t.Fatalf("expected 1 detail, got %d", len(details))
+	}
+	value, err := details[0].Value()
+	if err != nil {
+		t.Fatalf("failed to decode detail: %v", err)
+	}
+	info, ok := value.(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("expected ErrorInfo, got %T", value)
+	}
+	if info.Reason != "MESSAGE_NOT_FOUND" || info.Domain != "echo.example.com" {
+		t.Errorf("unexpected ErrorInfo: %v", info)
+	}
+}
+
+func TestMock_Delay(t *testing.T) {
+	server := setupMockTestServer(t, mockRulesYAML)
+	client := protoconnect.NewEchoClient(server.Client(), server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.EchoWithDelay(ctx, connect.NewRequest(&pb.EchoWithDelayRequest{Message: "hello"}))
+	if connect.CodeOf(err) != connect.CodeDeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestMock_ServerStream(t *testing.T) {
+	server := setupMockTestServer(t, mockRulesYAML)
+	client := protoconnect.NewEchoClient(server.Client(), server.URL, connect.WithGRPC())
+
+	stream, err := client.ServerStream(context.Background(), connect.NewRequest(&pb.ServerStreamRequest{Message: "hello", Count: 2}))
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	var messages []string
+	for stream.Receive() {
+		messages = append(messages, stream.Msg().Message)
+	}
+	if connect.CodeOf(stream.Err()) != connect.CodeUnavailable {
+		t.Errorf("expected Unavailable, got %v", stream.Err())
+	}
+	if strings.Join(messages, ",") != "first,second" {
+		t.Errorf("expected first,second, got %v", messages)
+	}
+}
+
+func TestMock_ClientStream(t *testing.T) {
+	server := setupMockTestServer(t, mockRulesYAML)
+	client := protoconnect.NewEchoClient(server.Client(), server.URL, connect.WithGRPC())
+
+	tests := []struct {
+		name     string
+		messages []string
+		expected string
+	}{
+		{name: "first request matches", messages: []string{"mock", "a"}, expected: "mocked stream"},
+		{name: "first request replayed", messages: []string{"a", "mock"}, expected: "a, mock"},
+		{name: "no requests", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := client.ClientStream(context.Background())
+			for _, message := range tt.messages {
+				if err := stream.Send(&pb.EchoRequest{Message: message}); err != nil {
+					t.Fatalf("Send failed: %v", err)
+				}
+			}
+
+			resp, err := stream.CloseAndReceive()
+			if err != nil {
+				t.Fatalf("CloseAndReceive failed: %v", err)
+			}
+			if resp.Msg.Message != tt.expected {
+				t.Errorf("expected message %q, got %q", tt.expected, resp.Msg.Message)
+			}
+		})
+	}
+}
+
+func TestLoadMockRules_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{name: "invalid yaml", rules: "rules: [\n"},
+		{name: "unknown key", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    responce: {}\n"},
+		{name: "unknown service", rules: "rules:\n  - method: echo.v1.Missing/Echo\n"},
+		{name: "unknown method", rules: "rules:\n  - method: echo.v1.Echo/Missing\n"},
+		{name: "unknown field", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    request: {missing: x}\n"},
+		{name: "message field", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    request: {failure: x}\n"},
+		{name: "object matcher", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    request: {message: {equals: {a: b}}}\n"},
+		{name: "invalid regex", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    request: {message: {regex: \"(\"}}\n"},
+		{name: "invalid response", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    response: {missing: x}\n"},
+		{name: "unary responses", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    responses: [{}, {}]\n"},
+		{name: "invalid delay", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    delay: soon\n"},
+		{name: "unknown code", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    status: {code: BROKEN}\n"},
+		{name: "code out of range", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    status: {code: 17}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.yaml")
+			if err := os.WriteFile(path, []byte(tt.rules), 0o600); err != nil {
+				t.Fatalf("failed to write rules: %v", err)
+			}
+			if _, err := LoadMockRules(path); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
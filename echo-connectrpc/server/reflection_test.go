@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"connectrpc.com/connect"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+)
+
+type reflectionStream = *connect.BidiStreamForClient[reflectionv1.ServerReflectionRequest, reflectionv1.ServerReflectionResponse]
+
+// setupReflectionTestServer serves both reflection versions over HTTP/2 and
+// opens a stream to the one at path.
+func setupReflectionTestServer(t *testing.T, path string, includeDeps bool) reflectionStream {
+	t.Helper()
+
+	reflector := NewReflectionServer([]string{protoconnect.EchoName, HealthServiceName}, includeDeps)
+	mux := http.NewServeMux()
+	mux.Handle(NewReflectionHandlerV1(reflector))
+	mux.Handle(NewReflectionHandlerV1Alpha(reflector))
+
+	server := httptest.NewUnstartedServer(mux)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	client := connect.NewClient[reflectionv1.ServerReflectionRequest, reflectionv1.ServerReflectionResponse](
+		server.Client(), server.URL+path+reflectionMethod, connect.WithGRPC())
+	stream := client.CallBidiStream(context.Background())
+	t.Cleanup(func() {
+		_ = stream.CloseRequest()
+		_ = stream.CloseResponse()
+	})
+	return stream
+}
+
+func reflectionCall(t *testing.T, stream reflectionStream, req *reflectionv1.ServerReflectionRequest) *reflectionv1.ServerReflectionResponse {
+	t.Helper()
+
+	if err := stream.Send(req); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	resp, err := stream.Receive()
+	if err != nil {
+		t.Fatalf("failed to receive: %v", err)
+	}
+	return resp
+}
+
+// fileNames decodes the file descriptors of a reflection response.
+func fileNames(t *testing.T, resp *reflectionv1.ServerReflectionResponse) []string {
+	t.Helper()
+
+	var names []string
+	for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		var fd descriptorpb.FileDescriptorProto
+		if err := proto.Unmarshal(b, &fd); err != nil {
+			t.Fatalf("failed to unmarshal descriptor: %v", err)
+		}
+		names = append(names, fd.GetName())
+	}
+	return names
+}
+
+func TestReflection_OmitsDependencies(t *testing.T) {
+	for _, path := range []string{reflectionV1Path, reflectionV1AlphaPath} {
+		t.Run(path, func(t *testing.T) {
+			stream := setupReflectionTestServer(t, path, false)
+
+			resp := reflectionCall(t, stream, &reflectionv1.ServerReflectionRequest{
+				MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: protoconnect.EchoName},
+			})
+			if names := fileNames(t, resp); len(names) != 1 || names[0] != "echo.proto" {
+				t.Errorf("expected only echo.proto, got %v", names)
+			}
+
+			// Imports must be requested one by one
+			resp = reflectionCall(t, stream, &reflectionv1.ServerReflectionRequest{
+				MessageRequest: &reflectionv1.ServerReflectionRequest_FileByFilename{FileByFilename: "echo_unary.proto"},
+			})
+			if names := fileNames(t, resp); len(names) != 1 || names[0] != "echo_unary.proto" {
+				t.Errorf("expected only echo_unary.proto, got %v", names)
+			}
+
+			// Files already sent on the stream are not sent again
+			resp = reflectionCall(t, stream, &reflectionv1.ServerReflectionRequest{
+				MessageRequest: &reflectionv1.ServerReflectionRequest_FileByFilename{FileByFilename: "echo.proto"},
+			})
+			if names := fileNames(t, resp); len(names) != 0 {
+				t.Errorf("expected no files, got %v", names)
+			}
+		})
+	}
+}
+
+func TestReflection_IncludesDependencies(t *testing.T) {
+	stream := setupReflectionTestServer(t, reflectionV1Path, true)
+
+	resp := reflectionCall(t, stream, &reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: protoconnect.EchoName},
+	})
+	names := fileNames(t, resp)
+	if len(names) < 2 || names[0] != "echo.proto" || !slices.Contains(names, "echo_unary.proto") {
+		t.Errorf("expected echo.proto followed by its imports, got %v", names)
+	}
+}
+
+func TestReflection_ListServices(t *testing.T) {
+	stream := setupReflectionTestServer(t, reflectionV1Path, false)
+
+	resp := reflectionCall(t, stream, &reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	})
+
+	var names []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		names = append(names, service.Name)
+	}
+	if len(names) != 2 || names[0] != protoconnect.EchoName || names[1] != HealthServiceName {
+		t.Errorf("expected sorted services, got %v", names)
+	}
+}
+
+func TestReflection_UnknownSymbol(t *testing.T) {
+	stream := setupReflectionTestServer(t, reflectionV1Path, false)
+
+	resp := reflectionCall(t, stream, &reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "echo.v1.Missing"},
+	})
+	if code := resp.GetErrorResponse().GetErrorCode(); code != int32(connect.CodeNotFound) {
+		t.Errorf("expected NotFound error response, got %v", resp.MessageResponse)
+	}
+}
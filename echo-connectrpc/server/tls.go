@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+)
+
+type tlsStateKey struct{}
+
+// TLSStateMiddleware stores the TLS connection state of each request in its
+// context so Connect handlers, which do not see the *http.Request, can report it.
+func TLSStateMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			r = r.WithContext(context.WithValue(r.Context(), tlsStateKey{}, r.TLS))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tlsMetadata describes the TLS connection of the calling peer as response
+// metadata entries. It returns nil for plaintext connections.
+func tlsMetadata(ctx context.Context) map[string]string {
+	state, ok := ctx.Value(tlsStateKey{}).(*tls.ConnectionState)
+	if !ok {
+		return nil
+	}
+
+	md := map[string]string{
+		"X-Tls-Version":             tls.VersionName(state.Version),
+		"X-Tls-Cipher-Suite":        tls.CipherSuiteName(state.CipherSuite),
+		"X-Tls-Server-Name":         state.ServerName,
+		"X-Tls-Negotiated-Protocol": state.NegotiatedProtocol,
+		"X-Tls-Peer-Verified":       strconv.FormatBool(len(state.VerifiedChains) > 0),
+	}
+
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		fingerprint := sha256.Sum256(cert.Raw)
+		md["X-Tls-Peer-Subject"] = cert.Subject.String()
+		md["X-Tls-Peer-Issuer"] = cert.Issuer.String()
+		md["X-Tls-Peer-Serial"] = cert.SerialNumber.String()
+		md["X-Tls-Peer-Fingerprint-Sha256"] = hex.EncodeToString(fingerprint[:])
+	}
+
+	return md
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+)
+
+func TestEcho_IncludesTLSMetadata(t *testing.T) {
+	mux := http.NewServeMux()
+	path, handler := protoconnect.NewEchoHandler(NewEchoServer())
+	mux.Handle(path, handler)
+
+	server := httptest.NewUnstartedServer(TLSStateMiddleware(mux))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	client := protoconnect.NewEchoClient(server.Client(), server.URL)
+
+	resp, err := client.Echo(context.Background(), connect.NewRequest(&pb.EchoRequest{
+		Message: "hello",
+	}))
+	if err != nil {
+		t.Fatalf("Echo failed: %v", err)
+	}
+
+	if resp.Msg.Metadata["X-Tls-Version"] != "TLS 1.3" {
+		t.Errorf("expected X-Tls-Version=%q, got %q", "TLS 1.3", resp.Msg.Metadata["X-Tls-Version"])
+	}
+	if resp.Msg.Metadata["X-Tls-Negotiated-Protocol"] != "h2" {
+		t.Errorf("expected X-Tls-Negotiated-Protocol=%q, got %q", "h2", resp.Msg.Metadata["X-Tls-Negotiated-Protocol"])
+	}
+	if resp.Msg.Metadata["X-Tls-Peer-Verified"] != "false" {
+		t.Errorf("expected X-Tls-Peer-Verified=%q, got %q", "false", resp.Msg.Metadata["X-Tls-Peer-Verified"])
+	}
+}
+
+func TestEcho_PlaintextOmitsTLSMetadata(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	resp, err := client.Echo(context.Background(), connect.NewRequest(&pb.EchoRequest{
+		Message: "hello",
+	}))
+	if err != nil {
+		t.Fatalf("Echo failed: %v", err)
+	}
+
+	if _, ok := resp.Msg.Metadata["X-Tls-Version"]; ok {
+		t.Error("expected no TLS metadata on plaintext connection")
+	}
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+)
+
+// gRPC-Web marks the envelope carrying trailers with this flag
+const grpcWebTrailerFlag = 0x80
+
+type wireStatusKey struct{}
+
+// wireStatusHolder carries the WireStatus of EchoError from the handler to
+// the response writer of WireStatusMiddleware.
+type wireStatusHolder struct {
+	wire *pb.WireStatus
+}
+
+// WireStatusMiddleware lets EchoError send malformed statuses, which
+// connect-go always writes well-formed, by rewriting the response of calls
+// that asked for it.
+func WireStatusMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		holder := &wireStatusHolder{}
+		ww := &wireStatusWriter{
+			ResponseWriter: w,
+			holder:         holder,
+			web:            strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc-web"),
+		}
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), wireStatusKey{}, holder)))
+		ww.finish()
+	})
+}
+
+// setWireStatus validates wire and asks WireStatusMiddleware to rewrite the
+// status of the call as wire describes. raw_code needs no rewriting.
+func setWireStatus(ctx context.Context, protocol string, wire *pb.WireStatus) error {
+	if wire.HttpStatus != 0 && (wire.HttpStatus < 200 || wire.HttpStatus > 599) {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("wire.http_status must be between 200 and 599"))
+	}
+	if strings.ContainsFunc(wire.GrpcStatus, func(r rune) bool { return r < ' ' || r == 0x7f }) {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("wire.grpc_status must not contain control characters"))
+	}
+	grpcOnly := wire.GrpcStatus != "" || wire.OmitGrpcStatus || wire.RawMessage
+	if grpcOnly && protocol == connect.ProtocolConnect {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("wire.grpc_status, wire.omit_grpc_status and wire.raw_message need the gRPC or gRPC-Web protocol"))
+	}
+	if !grpcOnly && wire.HttpStatus == 0 {
+		return nil
+	}
+
+	holder, ok := ctx.Value(wireStatusKey{}).(*wireStatusHolder)
+	if !ok {
+		return connect.NewError(connect.CodeUnimplemented, errors.New("wire status is not enabled on this server"))
+	}
+	holder.wire = wire
+	return nil
+}
+
+// wireStatusWriter applies the WireStatus of the call, if any, before the
+// response headers go out. gRPC statuses are found in the headers of
+// trailers-only responses, in the declared trailers, or for gRPC-Web in the
+// trailer envelope of the body, which is held back until the handler ends.
+type wireStatusWriter struct {
+	http.ResponseWriter
+	holder *wireStatusHolder
+	web    bool
+
+	wroteHeader     bool
+	rewroteTrailers bool
+	discard         bool // the response was replaced by a plain HTTP status
+	body            bytes.Buffer
+}
+
+func (w *wireStatusWriter) WriteHeader(code int) {
+	if w.prepare() {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *wireStatusWriter) Write(p []byte) (int, error) {
+	if !w.prepare() {
+		return len(p), nil
+	}
+	if w.holder.wire != nil && w.web {
+		return w.body.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush is required by Connect for streaming RPCs.
+func (w *wireStatusWriter) Flush() {
+	if w.prepare() {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+func (w *wireStatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// prepare applies the WireStatus to the headers before they are sent and
+// reports whether the handler's output should still be written.
+func (w *wireStatusWriter) prepare() bool {
+	if w.discard {
+		return false
+	}
+	if w.wroteHeader {
+		return true
+	}
+	w.wroteHeader = true
+
+	wire := w.holder.wire
+	if wire == nil {
+		return true
+	}
+	if wire.HttpStatus != 0 {
+		w.discard = true
+		header := w.ResponseWriter.Header()
+		for key := range header {
+			delete(header, key)
+		}
+		code := int(wire.HttpStatus)
+		http.Error(w.ResponseWriter, http.StatusText(code), code)
+		return false
+	}
+	header := w.ResponseWriter.Header()
+	rewriteGRPCStatus(header, "", wire)
+	w.rewroteTrailers = rewriteGRPCStatus(header, http.TrailerPrefix, wire)
+	return true
+}
+
+// finish writes what prepare and Write held back once the handler is done.
+func (w *wireStatusWriter) finish() {
+	wire := w.holder.wire
+	if wire == nil {
+		return
+	}
+	if !w.prepare() {
+		return
+	}
+	// Trailers declared after the headers were sent
+	if !w.rewroteTrailers {
+		rewriteGRPCStatus(w.ResponseWriter.Header(), http.TrailerPrefix, wire)
+	}
+	if w.web {
+		_, _ = w.ResponseWriter.Write(rewriteWebTrailers(w.body.Bytes(), wire))
+	}
+}
+
+// rewriteGRPCStatus applies wire to the grpc-status and grpc-message keys of
+// h, which are prefixed by prefix. It reports whether h carries a status.
+func rewriteGRPCStatus(h http.Header, prefix string, wire *pb.WireStatus) bool {
+	statusKey, messageKey := prefix+"Grpc-Status", prefix+"Grpc-Message"
+	if _, ok := h[statusKey]; !ok {
+		return false
+	}
+	if wire.OmitGrpcStatus {
+		delete(h, statusKey)
+	} else if wire.GrpcStatus != "" {
+		h[statusKey] = []string{wire.GrpcStatus}
+	}
+	if wire.RawMessage && len(h[messageKey]) > 0 {
+		h[messageKey] = []string{percentDecode(h[messageKey][0])}
+	}
+	return true
+}
+
+// rewriteWebTrailers applies wire to the trailer envelope of a gRPC-Web
+// response body. Message envelopes are kept as is.
+func rewriteWebTrailers(body []byte, wire *pb.WireStatus) []byte {
+	var out []byte
+	for len(body) >= 5 {
+		n := 5 + int(binary.BigEndian.Uint32(body[1:5]))
+		if len(body) < n {
+			break
+		}
+		if body[0]&grpcWebTrailerFlag == 0 {
+			out = append(out, body[:n]...)
+			body = body[n:]
+			continue
+		}
+
+		mime, err := textproto.NewReader(bufio.NewReader(bytes.NewReader(append(body[5:n:n], "\r\n"...)))).ReadMIMEHeader()
+		if err != nil {
+			out = append(out, body[:n]...)
+			body = body[n:]
+			continue
+		}
+		trailer := http.Header(mime)
+		rewriteGRPCStatus(trailer, "", wire)
+
+		var data bytes.Buffer
+		for key, values := range trailer {
+			for _, value := range values {
+				data.WriteString(strings.ToLower(key) + ": " + value + "\r\n")
+			}
+		}
+		out = append(out, body[0])
+		out = binary.BigEndian.AppendUint32(out, uint32(data.Len()))
+		out = append(out, data.Bytes()...)
+		body = body[n:]
+	}
+	return append(out, body...)
+}
+
+// percentDecode undoes the percent-encoding of grpc-message values.
+func percentDecode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
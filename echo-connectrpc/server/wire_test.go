@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-connectrpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-connectrpc/proto/protoconnect"
+)
+
+// setupWireTestServer serves the Echo handler over HTTP/2 behind
+// WireStatusMiddleware, as main does.
+func setupWireTestServer(t *testing.T, opts ...connect.HandlerOption) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewEchoHandler(NewEchoServer(), opts...))
+
+	server := httptest.NewUnstartedServer(WireStatusMiddleware(mux))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEchoError_WireStatus(t *testing.T) {
+	// With response headers, gRPC-Web sends trailers in the body rather than
+	// as a trailers-only response
+	servers := map[string]*httptest.Server{
+		"":           setupWireTestServer(t),
+		" call info": setupWireTestServer(t, connect.WithInterceptors(NewCallInfoInterceptor(nil))),
+	}
+	protocols := map[string][]connect.ClientOption{
+		"connect":  nil,
+		"grpc":     {connect.WithGRPC()},
+		"grpc-web": {connect.WithGRPCWeb()},
+	}
+
+	tests := []struct {
+		name        string
+		code        int32
+		details     string
+		wire        *pb.WireStatus
+		grpcOnly    bool
+		wantCode    connect.Code
+		wantMessage string
+	}{
+		{
+			name:        "clamped code",
+			code:        42,
+			details:     "unknown code",
+			wantCode:    connect.CodeUnknown,
+			wantMessage: "unknown code",
+		},
+		{
+			name:        "raw code",
+			code:        42,
+			details:     "unknown code",
+			wire:        &pb.WireStatus{RawCode: true},
+			wantCode:    connect.Code(42),
+			wantMessage: "unknown code",
+		},
+		{
+			name:        "non-numeric grpc-status",
+			code:        int32(connect.CodeNotFound),
+			wire:        &pb.WireStatus{GrpcStatus: "NOT_FOUND"},
+			grpcOnly:    true,
+			wantCode:    connect.CodeUnknown,
+			wantMessage: "NOT_FOUND",
+		},
+		{
+			name:     "missing grpc-status",
+			code:     int32(connect.CodeNotFound),
+			wire:     &pb.WireStatus{OmitGrpcStatus: true},
+			grpcOnly: true,
+			wantCode: connect.CodeUnknown,
+		},
+		{
+			name:        "percent-encoded message",
+			code:        int32(connect.CodeNotFound),
+			details:     "50%25 off",
+			wantCode:    connect.CodeNotFound,
+			wantMessage: "50%25 off",
+		},
+		{
+			name:        "raw message",
+			code:        int32(connect.CodeNotFound),
+			details:     "50%25 off",
+			wire:        &pb.WireStatus{RawMessage: true},
+			grpcOnly:    true,
+			wantCode:    connect.CodeNotFound,
+			wantMessage: "50% off",
+		},
+		{
+			name:        "raw message with invalid percent-encoding",
+			code:        int32(connect.CodeNotFound),
+			details:     "100%",
+			wire:        &pb.WireStatus{RawMessage: true},
+			grpcOnly:    true,
+			wantCode:    connect.CodeInternal,
+			wantMessage: "invalid error message",
+		},
+		{
+			name:        "http status",
+			code:        int32(connect.CodeNotFound),
+			wire:        &pb.WireStatus{HttpStatus: 503},
+			wantCode:    connect.CodeUnavailable,
+			wantMessage: "",
+		},
+	}
+
+	for serverName, server := range servers {
+		for protocol, opts := range protocols {
+			client := protoconnect.NewEchoClient(server.Client(), server.URL, opts...)
+			for _, tt := range tests {
+				t.Run(protocol+serverName+" "+tt.name, func(t *testing.T) {
+					_, err := client.EchoError(context.Background(), connect.NewRequest(&pb.EchoErrorRequest{
+						Code:    tt.code,
+						Details: tt.details,
+						Wire:    tt.wire,
+					}))
+
+					wantCode, wantMessage := tt.wantCode, tt.wantMessage
+					if protocol == "connect" {
+						if tt.grpcOnly {
+							wantCode, wantMessage = connect.CodeInvalidArgument, "gRPC"
+						} else if wantCode > 16 {
+							// The Connect protocol names codes, and clients do not know code_42
+							wantCode = connect.CodeUnknown
+						}
+					}
+					if connect.CodeOf(err) != wantCode {
+						t.Fatalf("expected code %v, got %v", wantCode, err)
+					}
+					if !strings.Contains(err.Error(), wantMessage) {
+						t.Errorf("expected error containing %q, got %q", wantMessage, err.Error())
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestEchoError_WireStatusInvalid(t *testing.T) {
+	server := setupWireTestServer(t)
+	client := protoconnect.NewEchoClient(server.Client(), server.URL, connect.WithGRPC())
+
+	for _, wire := range []*pb.WireStatus{{HttpStatus: 99}, {HttpStatus: 600}, {GrpcStatus: "5\n"}} {
+		_, err := client.EchoError(context.Background(), connect.NewRequest(&pb.EchoErrorRequest{Code: 5, Wire: wire}))
+		if connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Errorf("expected InvalidArgument for %v, got %v", wire, err)
+		}
+	}
+}
+
+func TestEchoError_WireStatusWithoutMiddleware(t *testing.T) {
+	client, server := setupTestServer(t)
+	defer server.Close()
+
+	_, err := client.EchoError(context.Background(), connect.NewRequest(&pb.EchoErrorRequest{
+		Code: 5,
+		Wire: &pb.WireStatus{HttpStatus: 503},
+	}))
+	if connect.CodeOf(err) != connect.CodeUnimplemented {
+		t.Errorf("expected Unimplemented, got %v", err)
+	}
+}
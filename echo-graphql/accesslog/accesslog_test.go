@@ -0,0 +1,91 @@
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestID   string
+		traceparent string
+		wantID      string
+		wantTraceID string
+	}{
+		{name: "request id", requestID: "abc-123", wantID: "abc-123"},
+		{name: "request id and traceparent", requestID: "abc-123", traceparent: traceparent, wantID: "abc-123", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "traceparent", traceparent: traceparent, wantID: "4bf92f3577b34da6a3ce929d0e0e4736", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "invalid request id", requestID: "bad id", traceparent: traceparent, wantID: "4bf92f3577b34da6a3ce929d0e0e4736", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "zero trace id", traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "uppercase trace id", traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "extra fields in version 00", traceparent: traceparent + "-extra"},
+		{name: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, traceID := RequestID(tt.requestID, tt.traceparent)
+			if traceID != tt.wantTraceID {
+				t.Errorf("expected trace ID %q, got %q", tt.wantTraceID, traceID)
+			}
+			if tt.wantID != "" {
+				if id != tt.wantID {
+					t.Errorf("expected ID %q, got %q", tt.wantID, id)
+				}
+			} else if len(id) != 32 {
+				t.Errorf("expected a generated ID, got %q", id)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write(body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader("hello"))
+	req.Header.Set("Traceparent", traceparent)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the trace ID echoed back, got %q", got)
+	}
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("invalid log line %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"msg":        "access",
+		"request_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+		"protocol":   "HTTP/1.1",
+		"method":     "POST",
+		"route":      "/graphql",
+		"status":     float64(418),
+		"bytes_in":   float64(5),
+		"bytes_out":  float64(5),
+		"peer":       "192.0.2.1:1234",
+	}
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("expected %s %v, got %v", key, value, line[key])
+		}
+	}
+	if _, ok := line["duration_ms"].(float64); !ok {
+		t.Errorf("expected duration_ms, got %v", line)
+	}
+}
@@ -2,13 +2,25 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Host string
-	Port string
+	Host                string
+	Port                string
+	Listen              string
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSClientCAFile     string
+	TLSClientAuth       string
+	TLSSelfSigned       bool
+	TLSSelfSignedDir    string
+	TLSSelfSignedHosts  string
+	ShutdownDrainPeriod time.Duration
+	ShutdownTimeout     time.Duration
+	AccessLog           bool
 }
 
 func LoadConfig() *Config {
@@ -16,8 +28,19 @@ func LoadConfig() *Config {
 	_ = godotenv.Load()
 
 	return &Config{
-		Host: getEnv("HOST", "0.0.0.0"),
-		Port: getEnv("PORT", "8080"),
+		Host:                getEnv("HOST", "0.0.0.0"),
+		Port:                getEnv("PORT", "8080"),
+		Listen:              getEnv("LISTEN", ""),
+		TLSCertFile:         getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:          getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:     getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:       getEnv("TLS_CLIENT_AUTH", ""),
+		TLSSelfSigned:       getEnvBool("TLS_SELF_SIGNED", false),
+		TLSSelfSignedDir:    getEnv("TLS_SELF_SIGNED_DIR", "tls"),
+		TLSSelfSignedHosts:  getEnv("TLS_SELF_SIGNED_HOSTS", ""),
+		ShutdownDrainPeriod: getEnvDuration("SHUTDOWN_DRAIN_PERIOD", 0),
+		ShutdownTimeout:     getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		AccessLog:           getEnvBool("ACCESS_LOG", true),
 	}
 }
 
@@ -31,3 +54,32 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	switch value {
+	case "1", "true", "TRUE", "True", "yes", "YES", "on", "ON":
+		return true
+	case "0", "false", "FALSE", "False", "no", "NO", "off", "OFF":
+		return false
+	default:
+		return defaultValue
+	}
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return defaultValue
+	}
+	return parsed
+}
@@ -89,6 +89,16 @@ type ComplexityRoot struct {
 		Value func(childComplexity int) int
 	}
 
+	PeerCertificate struct {
+		DNSNames          func(childComplexity int) int
+		FingerprintSha256 func(childComplexity int) int
+		Issuer            func(childComplexity int) int
+		NotAfter          func(childComplexity int) int
+		NotBefore         func(childComplexity int) int
+		SerialNumber      func(childComplexity int) int
+		Subject           func(childComplexity int) int
+	}
+
 	Query struct {
 		Echo               func(childComplexity int, message string) int
 		EchoError          func(childComplexity int, message string) int
@@ -98,6 +108,7 @@ type ComplexityRoot struct {
 		EchoNull           func(childComplexity int) int
 		EchoOptional       func(childComplexity int, message string, returnNull bool) int
 		EchoPartialError   func(childComplexity int, messages []string) int
+		EchoTLS            func(childComplexity int) int
 		EchoWithDelay      func(childComplexity int, message string, delayMs int) int
 		EchoWithExtensions func(childComplexity int, message string) int
 	}
@@ -108,6 +119,15 @@ type ComplexityRoot struct {
 		MessageCreated         func(childComplexity int) int
 		MessageCreatedFiltered func(childComplexity int, textContains *string) int
 	}
+
+	TLSInfo struct {
+		CipherSuite        func(childComplexity int) int
+		NegotiatedProtocol func(childComplexity int) int
+		PeerCertificates   func(childComplexity int) int
+		ServerName         func(childComplexity int) int
+		Verified           func(childComplexity int) int
+		Version            func(childComplexity int) int
+	}
 }
 
 type HeadersResolver interface {
@@ -133,6 +153,7 @@ type QueryResolver interface {
 	EchoList(ctx context.Context, message string, count int) ([]*model.EchoListItem, error)
 	EchoNull(ctx context.Context) (*string, error)
 	EchoOptional(ctx context.Context, message string, returnNull bool) (*string, error)
+	EchoTLS(ctx context.Context) (*model.TLSInfo, error)
 }
 type SubscriptionResolver interface {
 	MessageCreated(ctx context.Context) (<-chan *model.Message, error)
@@ -306,6 +327,49 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.NestedEcho.Value(childComplexity), true
 
+	case "PeerCertificate.dnsNames":
+		if e.complexity.PeerCertificate.DNSNames == nil {
+			break
+		}
+
+		return e.complexity.PeerCertificate.DNSNames(childComplexity), true
+	case "PeerCertificate.fingerprintSha256":
+		if e.complexity.PeerCertificate.FingerprintSha256 == nil {
+			break
+		}
+
+		return e.complexity.PeerCertificate.FingerprintSha256(childComplexity), true
+	case "PeerCertificate.issuer":
+		if e.complexity.PeerCertificate.Issuer == nil {
+			break
+		}
+
+		return e.complexity.PeerCertificate.Issuer(childComplexity), true
+	case "PeerCertificate.notAfter":
+		if e.complexity.PeerCertificate.NotAfter == nil {
+			break
+		}
+
+		return e.complexity.PeerCertificate.NotAfter(childComplexity), true
+	case "PeerCertificate.notBefore":
+		if e.complexity.PeerCertificate.NotBefore == nil {
+			break
+		}
+
+		return e.complexity.PeerCertificate.NotBefore(childComplexity), true
+	case "PeerCertificate.serialNumber":
+		if e.complexity.PeerCertificate.SerialNumber == nil {
+			break
+		}
+
+		return e.complexity.PeerCertificate.SerialNumber(childComplexity), true
+	case "PeerCertificate.subject":
+		if e.complexity.PeerCertificate.Subject == nil {
+			break
+		}
+
+		return e.complexity.PeerCertificate.Subject(childComplexity), true
+
 	case "Query.echo":
 		if e.complexity.Query.Echo == nil {
 			break
@@ -384,6 +448,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.Query.EchoPartialError(childComplexity, args["messages"].([]string)), true
+	case "Query.echoTLS":
+		if e.complexity.Query.EchoTLS == nil {
+			break
+		}
+
+		return e.complexity.Query.EchoTLS(childComplexity), true
 	case "Query.echoWithDelay":
 		if e.complexity.Query.EchoWithDelay == nil {
 			break
@@ -447,6 +517,43 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Subscription.MessageCreatedFiltered(childComplexity, args["textContains"].(*string)), true
 
+	case "TLSInfo.cipherSuite":
+		if e.complexity.TLSInfo.CipherSuite == nil {
+			break
+		}
+
+		return e.complexity.TLSInfo.CipherSuite(childComplexity), true
+	case "TLSInfo.negotiatedProtocol":
+		if e.complexity.TLSInfo.NegotiatedProtocol == nil {
+			break
+		}
+
+		return e.complexity.TLSInfo.NegotiatedProtocol(childComplexity), true
+	case "TLSInfo.peerCertificates":
+		if e.complexity.TLSInfo.PeerCertificates == nil {
+			break
+		}
+
+		return e.complexity.TLSInfo.PeerCertificates(childComplexity), true
+	case "TLSInfo.serverName":
+		if e.complexity.TLSInfo.ServerName == nil {
+			break
+		}
+
+		return e.complexity.TLSInfo.ServerName(childComplexity), true
+	case "TLSInfo.verified":
+		if e.complexity.TLSInfo.Verified == nil {
+			break
+		}
+
+		return e.complexity.TLSInfo.Verified(childComplexity), true
+	case "TLSInfo.version":
+		if e.complexity.TLSInfo.Version == nil {
+			break
+		}
+
+		return e.complexity.TLSInfo.Version(childComplexity), true
+
 	}
 	return 0, false
 }
@@ -1498,6 +1605,209 @@ func (ec *executionContext) fieldContext_NestedEcho_child(_ context.Context, fie
 	return fc, nil
 }
 
+func (ec *executionContext) _PeerCertificate_subject(ctx context.Context, field graphql.CollectedField, obj *model.PeerCertificate) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PeerCertificate_subject,
+		func(ctx context.Context) (any, error) {
+			return obj.Subject, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PeerCertificate_subject(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PeerCertificate",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PeerCertificate_issuer(ctx context.Context, field graphql.CollectedField, obj *model.PeerCertificate) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PeerCertificate_issuer,
+		func(ctx context.Context) (any, error) {
+			return obj.Issuer, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PeerCertificate_issuer(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PeerCertificate",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PeerCertificate_serialNumber(ctx context.Context, field graphql.CollectedField, obj *model.PeerCertificate) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PeerCertificate_serialNumber,
+		func(ctx context.Context) (any, error) {
+			return obj.SerialNumber, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PeerCertificate_serialNumber(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PeerCertificate",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PeerCertificate_notBefore(ctx context.Context, field graphql.CollectedField, obj *model.PeerCertificate) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PeerCertificate_notBefore,
+		func(ctx context.Context) (any, error) {
+			return obj.NotBefore, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PeerCertificate_notBefore(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PeerCertificate",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PeerCertificate_notAfter(ctx context.Context, field graphql.CollectedField, obj *model.PeerCertificate) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PeerCertificate_notAfter,
+		func(ctx context.Context) (any, error) {
+			return obj.NotAfter, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PeerCertificate_notAfter(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PeerCertificate",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PeerCertificate_dnsNames(ctx context.Context, field graphql.CollectedField, obj *model.PeerCertificate) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PeerCertificate_dnsNames,
+		func(ctx context.Context) (any, error) {
+			return obj.DNSNames, nil
+		},
+		nil,
+		ec.marshalNString2ᚕstringᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PeerCertificate_dnsNames(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PeerCertificate",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PeerCertificate_fingerprintSha256(ctx context.Context, field graphql.CollectedField, obj *model.PeerCertificate) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PeerCertificate_fingerprintSha256,
+		func(ctx context.Context) (any, error) {
+			return obj.FingerprintSha256, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PeerCertificate_fingerprintSha256(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PeerCertificate",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Query_echo(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -1912,6 +2222,49 @@ func (ec *executionContext) fieldContext_Query_echoOptional(ctx context.Context,
 	return fc, nil
 }
 
+func (ec *executionContext) _Query_echoTLS(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_echoTLS,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Query().EchoTLS(ctx)
+		},
+		nil,
+		ec.marshalOTLSInfo2ᚖgithubᚗcomᚋjsrᚑprobitasᚋechoᚑserversᚋechoᚑgraphqlᚋgraphᚋmodelᚐTLSInfo,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_echoTLS(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "version":
+				return ec.fieldContext_TLSInfo_version(ctx, field)
+			case "cipherSuite":
+				return ec.fieldContext_TLSInfo_cipherSuite(ctx, field)
+			case "serverName":
+				return ec.fieldContext_TLSInfo_serverName(ctx, field)
+			case "negotiatedProtocol":
+				return ec.fieldContext_TLSInfo_negotiatedProtocol(ctx, field)
+			case "verified":
+				return ec.fieldContext_TLSInfo_verified(ctx, field)
+			case "peerCertificates":
+				return ec.fieldContext_TLSInfo_peerCertificates(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type TLSInfo", field.Name)
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -2057,134 +2410,324 @@ func (ec *executionContext) fieldContext_Subscription_messageCreated(_ context.C
 	return fc, nil
 }
 
-func (ec *executionContext) _Subscription_countdown(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
-	return graphql.ResolveFieldStream(
+func (ec *executionContext) _Subscription_countdown(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
+	return graphql.ResolveFieldStream(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Subscription_countdown,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Subscription().Countdown(ctx, fc.Args["from"].(int))
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Subscription_countdown(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Subscription",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Subscription_countdown_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Subscription_messageCreatedFiltered(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
+	return graphql.ResolveFieldStream(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Subscription_messageCreatedFiltered,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Subscription().MessageCreatedFiltered(ctx, fc.Args["textContains"].(*string))
+		},
+		nil,
+		ec.marshalNMessage2ᚖgithubᚗcomᚋjsrᚑprobitasᚋechoᚑserversᚋechoᚑgraphqlᚋgraphᚋmodelᚐMessage,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Subscription_messageCreatedFiltered(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Subscription",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Message_id(ctx, field)
+			case "text":
+				return ec.fieldContext_Message_text(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Message_createdAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Message", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Subscription_messageCreatedFiltered_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Subscription_heartbeat(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
+	return graphql.ResolveFieldStream(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Subscription_heartbeat,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Subscription().Heartbeat(ctx, fc.Args["intervalMs"].(int))
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Subscription_heartbeat(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Subscription",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Subscription_heartbeat_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _TLSInfo_version(ctx context.Context, field graphql.CollectedField, obj *model.TLSInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_TLSInfo_version,
+		func(ctx context.Context) (any, error) {
+			return obj.Version, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_TLSInfo_version(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "TLSInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _TLSInfo_cipherSuite(ctx context.Context, field graphql.CollectedField, obj *model.TLSInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_TLSInfo_cipherSuite,
+		func(ctx context.Context) (any, error) {
+			return obj.CipherSuite, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_TLSInfo_cipherSuite(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "TLSInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _TLSInfo_serverName(ctx context.Context, field graphql.CollectedField, obj *model.TLSInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_TLSInfo_serverName,
+		func(ctx context.Context) (any, error) {
+			return obj.ServerName, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_TLSInfo_serverName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "TLSInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _TLSInfo_negotiatedProtocol(ctx context.Context, field graphql.CollectedField, obj *model.TLSInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Subscription_countdown,
+		ec.fieldContext_TLSInfo_negotiatedProtocol,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Subscription().Countdown(ctx, fc.Args["from"].(int))
+			return obj.NegotiatedProtocol, nil
 		},
 		nil,
-		ec.marshalNInt2int,
-		true,
+		ec.marshalOString2ᚖstring,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Subscription_countdown(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_TLSInfo_negotiatedProtocol(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Subscription",
+		Object:     "TLSInfo",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Subscription_countdown_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Subscription_messageCreatedFiltered(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
-	return graphql.ResolveFieldStream(
+func (ec *executionContext) _TLSInfo_verified(ctx context.Context, field graphql.CollectedField, obj *model.TLSInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Subscription_messageCreatedFiltered,
+		ec.fieldContext_TLSInfo_verified,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Subscription().MessageCreatedFiltered(ctx, fc.Args["textContains"].(*string))
+			return obj.Verified, nil
 		},
 		nil,
-		ec.marshalNMessage2ᚖgithubᚗcomᚋjsrᚑprobitasᚋechoᚑserversᚋechoᚑgraphqlᚋgraphᚋmodelᚐMessage,
+		ec.marshalNBoolean2bool,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Subscription_messageCreatedFiltered(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_TLSInfo_verified(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Subscription",
+		Object:     "TLSInfo",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Message_id(ctx, field)
-			case "text":
-				return ec.fieldContext_Message_text(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_Message_createdAt(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Message", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Subscription_messageCreatedFiltered_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Subscription_heartbeat(ctx context.Context, field graphql.CollectedField) (ret func(ctx context.Context) graphql.Marshaler) {
-	return graphql.ResolveFieldStream(
+func (ec *executionContext) _TLSInfo_peerCertificates(ctx context.Context, field graphql.CollectedField, obj *model.TLSInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Subscription_heartbeat,
+		ec.fieldContext_TLSInfo_peerCertificates,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Subscription().Heartbeat(ctx, fc.Args["intervalMs"].(int))
+			return obj.PeerCertificates, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNPeerCertificate2ᚕᚖgithubᚗcomᚋjsrᚑprobitasᚋechoᚑserversᚋechoᚑgraphqlᚋgraphᚋmodelᚐPeerCertificateᚄ,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Subscription_heartbeat(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_TLSInfo_peerCertificates(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Subscription",
+		Object:     "TLSInfo",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "subject":
+				return ec.fieldContext_PeerCertificate_subject(ctx, field)
+			case "issuer":
+				return ec.fieldContext_PeerCertificate_issuer(ctx, field)
+			case "serialNumber":
+				return ec.fieldContext_PeerCertificate_serialNumber(ctx, field)
+			case "notBefore":
+				return ec.fieldContext_PeerCertificate_notBefore(ctx, field)
+			case "notAfter":
+				return ec.fieldContext_PeerCertificate_notAfter(ctx, field)
+			case "dnsNames":
+				return ec.fieldContext_PeerCertificate_dnsNames(ctx, field)
+			case "fingerprintSha256":
+				return ec.fieldContext_PeerCertificate_fingerprintSha256(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PeerCertificate", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Subscription_heartbeat_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
@@ -4097,6 +4640,75 @@ func (ec *executionContext) _NestedEcho(ctx context.Context, sel ast.SelectionSe
 	return out
 }
 
+var peerCertificateImplementors = []string{"PeerCertificate"}
+
+func (ec *executionContext) _PeerCertificate(ctx context.Context, sel ast.SelectionSet, obj *model.PeerCertificate) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, peerCertificateImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("PeerCertificate")
+		case "subject":
+			out.Values[i] = ec._PeerCertificate_subject(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "issuer":
+			out.Values[i] = ec._PeerCertificate_issuer(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "serialNumber":
+			out.Values[i] = ec._PeerCertificate_serialNumber(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "notBefore":
+			out.Values[i] = ec._PeerCertificate_notBefore(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "notAfter":
+			out.Values[i] = ec._PeerCertificate_notAfter(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "dnsNames":
+			out.Values[i] = ec._PeerCertificate_dnsNames(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "fingerprintSha256":
+			out.Values[i] = ec._PeerCertificate_fingerprintSha256(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var queryImplementors = []string{"Query"}
 
 func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
@@ -4329,6 +4941,25 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "echoTLS":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_echoTLS(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "__type":
 			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
@@ -4387,6 +5018,64 @@ func (ec *executionContext) _Subscription(ctx context.Context, sel ast.Selection
 	}
 }
 
+var tLSInfoImplementors = []string{"TLSInfo"}
+
+func (ec *executionContext) _TLSInfo(ctx context.Context, sel ast.SelectionSet, obj *model.TLSInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, tLSInfoImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("TLSInfo")
+		case "version":
+			out.Values[i] = ec._TLSInfo_version(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "cipherSuite":
+			out.Values[i] = ec._TLSInfo_cipherSuite(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "serverName":
+			out.Values[i] = ec._TLSInfo_serverName(ctx, field, obj)
+		case "negotiatedProtocol":
+			out.Values[i] = ec._TLSInfo_negotiatedProtocol(ctx, field, obj)
+		case "verified":
+			out.Values[i] = ec._TLSInfo_verified(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "peerCertificates":
+			out.Values[i] = ec._TLSInfo_peerCertificates(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var __DirectiveImplementors = []string{"__Directive"}
 
 func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionSet, obj *introspection.Directive) graphql.Marshaler {
@@ -5018,6 +5707,60 @@ func (ec *executionContext) marshalNNestedEcho2ᚖgithubᚗcomᚋjsrᚑprobitas
 	return ec._NestedEcho(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNPeerCertificate2ᚕᚖgithubᚗcomᚋjsrᚑprobitasᚋechoᚑserversᚋechoᚑgraphqlᚋgraphᚋmodelᚐPeerCertificateᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.PeerCertificate) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNPeerCertificate2ᚖgithubᚗcomᚋjsrᚑprobitasᚋechoᚑserversᚋechoᚑgraphqlᚋgraphᚋmodelᚐPeerCertificate(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNPeerCertificate2ᚖgithubᚗcomᚋjsrᚑprobitasᚋechoᚑserversᚋechoᚑgraphqlᚋgraphᚋmodelᚐPeerCertificate(ctx context.Context, sel ast.SelectionSet, v *model.PeerCertificate) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._PeerCertificate(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNString2string(ctx context.Context, v any) (string, error) {
 	res, err := graphql.UnmarshalString(v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -5372,6 +6115,13 @@ func (ec *executionContext) marshalOString2ᚖstring(ctx context.Context, sel as
 	return res
 }
 
+func (ec *executionContext) marshalOTLSInfo2ᚖgithubᚗcomᚋjsrᚑprobitasᚋechoᚑserversᚋechoᚑgraphqlᚋgraphᚋmodelᚐTLSInfo(ctx context.Context, sel ast.SelectionSet, v *model.TLSInfo) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._TLSInfo(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐEnumValueᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.EnumValue) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -2,7 +2,11 @@ package model
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"net/http"
+	"time"
 )
 
 type Message struct {
@@ -39,6 +43,66 @@ type EchoListItem struct {
 	Message string `json:"message"`
 }
 
+// TLSInfo describes the TLS connection a request arrived on
+type TLSInfo struct {
+	Version            string             `json:"version"`
+	CipherSuite        string             `json:"cipherSuite"`
+	ServerName         *string            `json:"serverName,omitempty"`
+	NegotiatedProtocol *string            `json:"negotiatedProtocol,omitempty"`
+	Verified           bool               `json:"verified"`
+	PeerCertificates   []*PeerCertificate `json:"peerCertificates"`
+}
+
+// PeerCertificate summarizes a certificate presented by the client
+type PeerCertificate struct {
+	Subject           string   `json:"subject"`
+	Issuer            string   `json:"issuer"`
+	SerialNumber      string   `json:"serialNumber"`
+	NotBefore         string   `json:"notBefore"`
+	NotAfter          string   `json:"notAfter"`
+	DNSNames          []string `json:"dnsNames"`
+	FingerprintSha256 string   `json:"fingerprintSha256"`
+}
+
+// NewTLSInfo builds TLSInfo from a connection state (nil for plaintext connections)
+func NewTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := &TLSInfo{
+		Version:          tls.VersionName(state.Version),
+		CipherSuite:      tls.CipherSuiteName(state.CipherSuite),
+		Verified:         len(state.VerifiedChains) > 0,
+		PeerCertificates: []*PeerCertificate{},
+	}
+	if state.ServerName != "" {
+		info.ServerName = &state.ServerName
+	}
+	if state.NegotiatedProtocol != "" {
+		info.NegotiatedProtocol = &state.NegotiatedProtocol
+	}
+
+	for _, cert := range state.PeerCertificates {
+		fingerprint := sha256.Sum256(cert.Raw)
+		dnsNames := cert.DNSNames
+		if dnsNames == nil {
+			dnsNames = []string{}
+		}
+		info.PeerCertificates = append(info.PeerCertificates, &PeerCertificate{
+			Subject:           cert.Subject.String(),
+			Issuer:            cert.Issuer.String(),
+			SerialNumber:      cert.SerialNumber.String(),
+			NotBefore:         cert.NotBefore.UTC().Format(time.RFC3339),
+			NotAfter:          cert.NotAfter.UTC().Format(time.RFC3339),
+			DNSNames:          dnsNames,
+			FingerprintSha256: hex.EncodeToString(fingerprint[:]),
+		})
+	}
+
+	return info
+}
+
 // Key for storing http.Request in context
 type contextKey string
 
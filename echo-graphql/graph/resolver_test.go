@@ -2,6 +2,11 @@ package graph_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
 	"testing"
 	"time"
 
@@ -10,6 +15,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 
 	"github.com/jsr-probitas/echo-servers/echo-graphql/graph"
+	"github.com/jsr-probitas/echo-servers/echo-graphql/graph/model"
 )
 
 func setupTestClient(t *testing.T) *client.Client {
@@ -354,6 +360,86 @@ func TestEchoHeaders_ReturnsEmptyWhenNoRequest(t *testing.T) {
 	}
 }
 
+func TestEchoTLS_ReturnsNullForPlaintext(t *testing.T) {
+	c := setupTestClient(t)
+
+	var resp struct {
+		EchoTLS *struct {
+			Version string
+		}
+	}
+	c.MustPost(`query { echoTLS { version } }`, &resp)
+
+	if resp.EchoTLS != nil {
+		t.Errorf("expected null for plaintext request, got %+v", resp.EchoTLS)
+	}
+}
+
+func TestEchoTLS_ReturnsConnectionDetails(t *testing.T) {
+	resolver := graph.NewResolver()
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Resolvers: resolver,
+	}))
+	srv.AddTransport(transport.POST{})
+	withRequest := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), model.RequestKey, r)
+		srv.ServeHTTP(w, r.WithContext(ctx))
+	})
+	c := client.New(withRequest)
+
+	peer := &x509.Certificate{
+		Raw:          []byte("fake-der"),
+		SerialNumber: big.NewInt(7),
+		Subject:      pkix.Name{CommonName: "echo-client"},
+	}
+	withTLS := func(bd *client.Request) {
+		bd.HTTP.TLS = &tls.ConnectionState{
+			Version:            tls.VersionTLS13,
+			CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+			ServerName:         "echo.local",
+			NegotiatedProtocol: "h2",
+			PeerCertificates:   []*x509.Certificate{peer},
+			VerifiedChains:     [][]*x509.Certificate{{peer}},
+		}
+	}
+
+	var resp struct {
+		EchoTLS struct {
+			Version            string
+			ServerName         *string
+			NegotiatedProtocol *string
+			Verified           bool
+			PeerCertificates   []struct {
+				Subject      string
+				SerialNumber string
+			}
+		}
+	}
+	c.MustPost(`query { echoTLS { version serverName negotiatedProtocol verified peerCertificates { subject serialNumber } } }`, &resp, withTLS)
+
+	if resp.EchoTLS.Version != "TLS 1.3" {
+		t.Errorf("expected version 'TLS 1.3', got %q", resp.EchoTLS.Version)
+	}
+	if resp.EchoTLS.ServerName == nil || *resp.EchoTLS.ServerName != "echo.local" {
+		t.Errorf("expected serverName 'echo.local', got %v", resp.EchoTLS.ServerName)
+	}
+	if resp.EchoTLS.NegotiatedProtocol == nil || *resp.EchoTLS.NegotiatedProtocol != "h2" {
+		t.Errorf("expected negotiatedProtocol 'h2', got %v", resp.EchoTLS.NegotiatedProtocol)
+	}
+	if !resp.EchoTLS.Verified {
+		t.Error("expected verified to be true")
+	}
+	if len(resp.EchoTLS.PeerCertificates) != 1 {
+		t.Fatalf("expected 1 peer certificate, got %d", len(resp.EchoTLS.PeerCertificates))
+	}
+	if resp.EchoTLS.PeerCertificates[0].Subject != "CN=echo-client" {
+		t.Errorf("expected subject 'CN=echo-client', got %q", resp.EchoTLS.PeerCertificates[0].Subject)
+	}
+	if resp.EchoTLS.PeerCertificates[0].SerialNumber != "7" {
+		t.Errorf("expected serialNumber '7', got %q", resp.EchoTLS.PeerCertificates[0].SerialNumber)
+	}
+}
+
 // Mutation Tests
 
 func TestCreateMessage_CreatesAndReturnsMessage(t *testing.T) {
@@ -250,6 +250,15 @@ func (r *queryResolver) EchoOptional(ctx context.Context, message string, return
 	return &message, nil
 }
 
+// EchoTLS returns TLS connection details for TLS/mTLS client testing
+func (r *queryResolver) EchoTLS(ctx context.Context) (*model.TLSInfo, error) {
+	req := model.GetRequestFromContext(ctx)
+	if req == nil {
+		return nil, nil
+	}
+	return model.NewTLSInfo(req.TLS), nil
+}
+
 // MessageCreated subscribes to message creation events
 func (r *subscriptionResolver) MessageCreated(ctx context.Context) (<-chan *model.Message, error) {
 	ch := r.Subscribe()
@@ -4,6 +4,10 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -12,8 +16,10 @@ import (
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gorilla/websocket"
 
+	"github.com/jsr-probitas/echo-servers/echo-graphql/accesslog"
 	"github.com/jsr-probitas/echo-servers/echo-graphql/graph"
 	"github.com/jsr-probitas/echo-servers/echo-graphql/graph/model"
+	"github.com/jsr-probitas/echo-servers/echo-graphql/metrics"
 )
 
 // requestContextMiddleware injects the http.Request into context for header access
@@ -27,6 +33,8 @@ func requestContextMiddleware(next http.Handler) http.Handler {
 func main() {
 	cfg := LoadConfig()
 
+	m := metrics.New()
+
 	resolver := graph.NewResolver()
 	srv := handler.New(graph.NewExecutableSchema(graph.Config{
 		Resolvers: resolver,
@@ -52,20 +60,92 @@ func main() {
 	// Enable introspection
 	srv.Use(extension.Introspection{})
 
-	// Health check endpoint
+	// Record operation and subscription metrics
+	srv.Use(m.Extension())
+
+	// Health check endpoint (fails once shutdown has started)
+	var shuttingDown atomic.Bool
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
+		if shuttingDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":"shutting_down"}`))
+			return
+		}
 		_, _ = w.Write([]byte(`{"status":"ok"}`))
 	})
 
+	// Prometheus metrics endpoint
+	http.Handle("/metrics", m.Handler())
+
 	// GraphQL playground
 	http.Handle("/", playground.Handler("GraphQL Playground", "/graphql"))
 
-	// GraphQL endpoint (with request context middleware for header access)
-	http.Handle("/graphql", requestContextMiddleware(srv))
+	// GraphQL endpoint (with request context middleware for header access),
+	// tracking subscriptions so that shutdown can close them
+	subs := newSubscriptions()
+	http.Handle("/graphql", subs.Middleware(requestContextMiddleware(srv)))
+
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		log.Fatalf("Failed to configure TLS: %v", err)
+	}
+
+	// Serve the same handlers on every listener, each with its own TLS and
+	// HTTP version settings
+	listeners, err := cfg.Listeners()
+	if err != nil {
+		log.Fatalf("Failed to configure listeners: %v", err)
+	}
+	root := m.Middleware(http.DefaultServeMux)
+	if cfg.AccessLog {
+		// Log each request as a JSON line correlated by X-Request-ID
+		root = accesslog.New(os.Stdout).Middleware(root)
+	}
+	servers, err := newHTTPServers(listeners, func(l Listener) (*http.Server, error) {
+		srv := &http.Server{Handler: root, Protocols: l.Protocols()}
+		if l.TLS {
+			srv.TLSConfig = tlsConfig.Clone()
+		}
+		return srv, nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+
+	// Graceful shutdown: fail health checks, drain, wait for in-flight
+	// requests (HTTP/2 clients receive GOAWAY), then close subscriptions
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
 
-	log.Printf("Starting server on %s", cfg.Addr())
-	if err := http.ListenAndServe(cfg.Addr(), nil); err != nil {
+		log.Println("Shutting down server...")
+		shuttingDown.Store(true)
+		if cfg.ShutdownDrainPeriod > 0 {
+			log.Printf("Draining for %s", cfg.ShutdownDrainPeriod)
+			time.Sleep(cfg.ShutdownDrainPeriod)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		if err := servers.Shutdown(ctx); err != nil {
+			log.Printf("Server shutdown error: %v", err)
+			servers.Close()
+		}
+		if err := subs.Close(ctx); err != nil {
+			log.Printf("Subscription shutdown error: %v", err)
+		}
+	}()
+
+	if err := servers.Serve(); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
+
+	<-stopped
+	log.Println("Server stopped")
 }
@@ -0,0 +1,313 @@
+// Package metrics exposes Prometheus metrics for the echo GraphQL server.
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Metrics records GraphQL operation metrics through Extension and HTTP
+// transport metrics through Middleware.
+type Metrics struct {
+	registry            *prometheus.Registry
+	operations          *prometheus.CounterVec
+	duration            *prometheus.HistogramVec
+	operationsInFlight  *prometheus.GaugeVec
+	activeSubscriptions *prometheus.GaugeVec
+	subscriptionEvents  *prometheus.CounterVec
+	requests            *prometheus.CounterVec
+	inFlight            *prometheus.GaugeVec
+	requestBytes        *prometheus.CounterVec
+	responseBytes       *prometheus.CounterVec
+}
+
+// New creates a Metrics instance backed by its own registry, which also
+// includes the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "graphql_operations_total",
+			Help: "Total number of GraphQL operations by type, name and result.",
+		}, []string{"operation_type", "operation_name", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "graphql_operation_duration_seconds",
+			Help:    "Latency of GraphQL operations; for subscriptions, the time the subscription was open.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation_type", "operation_name"}),
+		operationsInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "graphql_operations_in_flight",
+			Help: "Number of queries and mutations currently being executed.",
+		}, []string{"operation_type"}),
+		activeSubscriptions: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "graphql_active_subscriptions",
+			Help: "Number of subscriptions currently open.",
+		}, []string{"operation_name"}),
+		subscriptionEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "graphql_subscription_events_total",
+			Help: "Total number of subscription events sent to clients.",
+		}, []string{"operation_name"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by path, method and status code.",
+		}, []string{"path", "method", "code"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests (including open WebSocket connections) by path.",
+		}, []string{"path"}),
+		requestBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_request_bytes_total",
+			Help: "Total bytes received by path, including WebSocket frames.",
+		}, []string{"path"}),
+		responseBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_response_bytes_total",
+			Help: "Total bytes sent by path, including WebSocket frames.",
+		}, []string{"path"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.operations,
+		m.duration,
+		m.operationsInFlight,
+		m.activeSubscriptions,
+		m.subscriptionEvents,
+		m.requests,
+		m.inFlight,
+		m.requestBytes,
+		m.responseBytes,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Extension returns a gqlgen handler extension recording operation metrics.
+func (m *Metrics) Extension() graphql.HandlerExtension {
+	return &extension{metrics: m}
+}
+
+type extension struct {
+	metrics *Metrics
+}
+
+var (
+	_ graphql.HandlerExtension     = (*extension)(nil)
+	_ graphql.OperationInterceptor = (*extension)(nil)
+)
+
+func (e *extension) ExtensionName() string {
+	return "PrometheusMetrics"
+}
+
+func (e *extension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e *extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc == nil || oc.Operation == nil {
+		return next(ctx)
+	}
+
+	m := e.metrics
+	opType := string(oc.Operation.Operation)
+	opName := oc.OperationName
+	if opName == "" {
+		opName = oc.Operation.Name
+	}
+	if opName == "" {
+		opName = "anonymous"
+	}
+	start := time.Now()
+
+	if oc.Operation.Operation != ast.Subscription {
+		inFlight := m.operationsInFlight.WithLabelValues(opType)
+		inFlight.Inc()
+
+		responses := next(ctx)
+		var once sync.Once
+		return func(ctx context.Context) *graphql.Response {
+			resp := responses(ctx)
+			once.Do(func() {
+				inFlight.Dec()
+				m.operations.WithLabelValues(opType, opName, result(resp)).Inc()
+				m.duration.WithLabelValues(opType, opName).Observe(time.Since(start).Seconds())
+			})
+			return resp
+		}
+	}
+
+	active := m.activeSubscriptions.WithLabelValues(opName)
+	active.Inc()
+
+	responses := next(ctx)
+	var once sync.Once
+	failed := false
+	return func(ctx context.Context) *graphql.Response {
+		resp := responses(ctx)
+		if resp != nil {
+			if len(resp.Errors) > 0 {
+				failed = true
+			}
+			m.subscriptionEvents.WithLabelValues(opName).Inc()
+			return resp
+		}
+
+		once.Do(func() {
+			active.Dec()
+			outcome := "success"
+			if failed {
+				outcome = "error"
+			}
+			m.operations.WithLabelValues(opType, opName, outcome).Inc()
+			m.duration.WithLabelValues(opType, opName).Observe(time.Since(start).Seconds())
+		})
+		return nil
+	}
+}
+
+func result(resp *graphql.Response) string {
+	if resp == nil || len(resp.Errors) > 0 {
+		return "error"
+	}
+	return "success"
+}
+
+// Middleware records HTTP requests, in-flight requests and bytes per path.
+// Bytes sent over hijacked (WebSocket) connections are included.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		inFlight := m.inFlight.WithLabelValues(path)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		received := m.requestBytes.WithLabelValues(path)
+		sent := m.responseBytes.WithLabelValues(path)
+
+		body := &countingReader{ReadCloser: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+		rw := &countingWriter{ResponseWriter: w, received: received, sent: sent}
+
+		defer func() {
+			received.Add(float64(body.n.Load()))
+			sent.Add(float64(rw.n))
+
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			m.requests.WithLabelValues(path, r.Method, strconv.Itoa(status)).Inc()
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	received prometheus.Counter
+	sent     prometheus.Counter
+	status   int
+	n        int64
+}
+
+func (c *countingWriter) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	n, err := c.ResponseWriter.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingWriter) Flush() {
+	_ = http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+// Hijack hands out a connection that keeps counting bytes in both directions,
+// so WebSocket traffic shows up in the byte counters.
+func (c *countingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(c.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.status == 0 {
+		c.status = http.StatusSwitchingProtocols
+	}
+
+	// Carry over anything already buffered before switching to the counted conn
+	if err := brw.Flush(); err != nil {
+		return nil, nil, err
+	}
+	buffered, _ := brw.Peek(brw.Reader.Buffered())
+
+	counted := &countingConn{Conn: conn, received: c.received, sent: c.sent}
+	brw = bufio.NewReadWriter(
+		bufio.NewReader(io.MultiReader(bytes.NewReader(buffered), counted)),
+		bufio.NewWriter(counted),
+	)
+	return counted, brw, nil
+}
+
+func (c *countingWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+type countingConn struct {
+	net.Conn
+	received prometheus.Counter
+	sent     prometheus.Counter
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.received.Add(float64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.sent.Add(float64(n))
+	return n, err
+}
@@ -0,0 +1,130 @@
+package metrics_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+
+	"github.com/jsr-probitas/echo-servers/echo-graphql/graph"
+	"github.com/jsr-probitas/echo-servers/echo-graphql/metrics"
+)
+
+func setupTestClient(t *testing.T, m *metrics.Metrics) *client.Client {
+	t.Helper()
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Resolvers: graph.NewResolver(),
+	}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(m.Extension())
+	return client.New(m.Middleware(srv))
+}
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestMetrics_RecordsOperations(t *testing.T) {
+	m := metrics.New()
+	c := setupTestClient(t, m)
+
+	var resp struct {
+		Echo string
+	}
+	c.MustPost(`query Greet { echo(message: "hello") }`, &resp)
+
+	var errResp struct {
+		EchoError string
+	}
+	_ = c.Post(`{ echoError(message: "boom") }`, &errResp)
+
+	body := scrape(t, m)
+
+	expected := []string{
+		`graphql_operations_total{operation_name="Greet",operation_type="query",result="success"} 1`,
+		`graphql_operations_total{operation_name="anonymous",operation_type="query",result="error"} 1`,
+		`graphql_operation_duration_seconds_count{operation_name="Greet",operation_type="query"} 1`,
+		`graphql_operations_in_flight{operation_type="query"} 0`,
+		`http_requests_total{code="200",method="POST",path="/"} 2`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected metrics to contain %q", line)
+		}
+	}
+}
+
+func TestMetrics_TracksSubscriptions(t *testing.T) {
+	m := metrics.New()
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Resolvers: graph.NewResolver(),
+	}))
+	srv.AddTransport(transport.SSE{})
+	srv.Use(m.Extension())
+
+	server := httptest.NewServer(m.Middleware(srv))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	body := `{"query": "subscription Countdown { countdown(from: 5) }"}`
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Wait for the first event
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "data:") {
+			break
+		}
+	}
+
+	active := `graphql_active_subscriptions{operation_name="Countdown"}`
+	waitForMetric(t, m, active+" 1")
+	waitForMetric(t, m, `graphql_subscription_events_total{operation_name="Countdown"} 1`)
+
+	cancel()
+
+	waitForMetric(t, m, active+" 0")
+	waitForMetric(t, m, `graphql_operations_total{operation_name="Countdown",operation_type="subscription",result="success"} 1`)
+}
+
+// waitForMetric polls the metrics until line appears, since the server side of
+// a subscription finishes asynchronously from the client.
+func waitForMetric(t *testing.T, m *metrics.Metrics, line string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(scrape(t, m), line) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("expected metrics to contain %q", line)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// subscriptions tracks the WebSocket connections of GraphQL subscriptions,
+// which http.Server.Shutdown neither waits for nor closes once they are
+// hijacked.
+type subscriptions struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newSubscriptions() *subscriptions {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &subscriptions{ctx: ctx, cancel: cancel}
+}
+
+// Middleware tracks WebSocket upgrades. gqlgen serves the connection until
+// the request context is done, and then closes it with a close frame.
+func (s *subscriptions) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s.wg.Add(1)
+		defer s.wg.Done()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		stop := context.AfterFunc(s.ctx, cancel)
+		defer stop()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Close ends every subscription and waits for its connection to be closed,
+// or for ctx to be done.
+func (s *subscriptions) Close(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSelfSigned(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "certs")
+
+	bundle, err := generateSelfSigned(dir, " echo.test, 10.0.0.1 ,")
+	if err != nil {
+		t.Fatalf("generateSelfSigned failed: %v", err)
+	}
+
+	for name, perm := range map[string]os.FileMode{"ca.pem": 0o644, "client.pem": 0o644, "client-key.pem": 0o600} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", name, err)
+		}
+		if info.Mode().Perm() != perm {
+			t.Errorf("expected %s to have mode %v, got %v", name, perm, info.Mode().Perm())
+		}
+	}
+
+	server, err := x509.ParseCertificate(bundle.server.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse server certificate: %v", err)
+	}
+	for _, name := range []string{"localhost", "echo.test"} {
+		if !slices.Contains(server.DNSNames, name) {
+			t.Errorf("expected DNS SAN %q, got %v", name, server.DNSNames)
+		}
+	}
+	for _, ip := range []string{"127.0.0.1", "::1", "10.0.0.1"} {
+		if !slices.ContainsFunc(server.IPAddresses, net.ParseIP(ip).Equal) {
+			t.Errorf("expected IP SAN %s, got %v", ip, server.IPAddresses)
+		}
+	}
+	if _, err := server.Verify(x509.VerifyOptions{Roots: bundle.caPool, DNSName: "echo.test"}); err != nil {
+		t.Errorf("expected server certificate to verify against the CA: %v", err)
+	}
+
+	// Clients trust the server and authenticate with the written files
+	caPEM, err := os.ReadFile(filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		t.Fatalf("failed to read ca.pem: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		t.Fatal("expected a certificate in ca.pem")
+	}
+	clientPair, err := tls.LoadX509KeyPair(filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem"))
+	if err != nil {
+		t.Fatalf("failed to load client key pair: %v", err)
+	}
+	client, err := x509.ParseCertificate(clientPair.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse client certificate: %v", err)
+	}
+	if _, err := client.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Errorf("expected client certificate to verify against ca.pem: %v", err)
+	}
+	if _, err := server.Verify(x509.VerifyOptions{Roots: roots, DNSName: "localhost"}); err != nil {
+		t.Errorf("expected server certificate to verify against ca.pem: %v", err)
+	}
+}
+
+func TestParseClientAuth(t *testing.T) {
+	tests := []struct {
+		value       string
+		hasClientCA bool
+		want        tls.ClientAuthType
+		wantErr     string
+	}{
+		{value: "", want: tls.NoClientCert},
+		{value: "", hasClientCA: true, want: tls.RequireAndVerifyClientCert},
+		{value: "none", hasClientCA: true, want: tls.NoClientCert},
+		{value: "request", want: tls.RequestClientCert},
+		{value: "require", want: tls.RequireAnyClientCert},
+		{value: "verify-if-given", want: tls.VerifyClientCertIfGiven},
+		{value: "require-and-verify", want: tls.RequireAndVerifyClientCert},
+		{value: "REQUIRE", wantErr: `invalid TLS_CLIENT_AUTH "REQUIRE"`},
+		{value: "optional", wantErr: `invalid TLS_CLIENT_AUTH "optional"`},
+	}
+
+	for _, tt := range tests {
+		got, err := parseClientAuth(tt.value, tt.hasClientCA)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%q: expected error %q, got %v", tt.value, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q (client CA %v): expected %v, got %v", tt.value, tt.hasClientCA, tt.want, got)
+		}
+	}
+}
+
+func TestConfig_TLSConfig(t *testing.T) {
+	// A key pair and CA on disk, as TLS_CERT_FILE and TLS_CLIENT_CA_FILE take them
+	dir := t.TempDir()
+	if _, err := generateSelfSigned(dir, ""); err != nil {
+		t.Fatalf("generateSelfSigned failed: %v", err)
+	}
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	caFile := filepath.Join(dir, "ca.pem")
+
+	t.Run("disabled", func(t *testing.T) {
+		tlsConfig, err := (&Config{}).TLSConfig()
+		if err != nil || tlsConfig != nil {
+			t.Errorf("expected no TLS config, got %v, %v", tlsConfig, err)
+		}
+	})
+
+	t.Run("self-signed verifies client certificates when asked", func(t *testing.T) {
+		cfg := &Config{TLSSelfSigned: true, TLSSelfSignedDir: t.TempDir(), TLSClientAuth: "verify-if-given"}
+		tlsConfig, err := cfg.TLSConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tlsConfig.ClientAuth != tls.VerifyClientCertIfGiven || tlsConfig.ClientCAs == nil {
+			t.Errorf("expected verify-if-given with the generated CA, got %v", tlsConfig.ClientAuth)
+		}
+	})
+
+	t.Run("client CA requires client certificates", func(t *testing.T) {
+		cfg := &Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: caFile}
+		tlsConfig, err := cfg.TLSConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert || tlsConfig.ClientCAs == nil {
+			t.Errorf("expected require-and-verify with the client CA, got %v", tlsConfig.ClientAuth)
+		}
+	})
+
+	errorTests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name:    "missing key",
+			cfg:     Config{TLSCertFile: certFile},
+			wantErr: "TLS_KEY_FILE is required when TLS_CERT_FILE is set",
+		},
+		{
+			name:    "verification without client CA",
+			cfg:     Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientAuth: "require-and-verify"},
+			wantErr: "TLS_CLIENT_CA_FILE is required to verify client certificates",
+		},
+		{
+			name:    "verify-if-given without client CA",
+			cfg:     Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientAuth: "verify-if-given"},
+			wantErr: "TLS_CLIENT_CA_FILE is required to verify client certificates",
+		},
+		{
+			name:    "missing client CA file",
+			cfg:     Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: filepath.Join(dir, "missing.pem")},
+			wantErr: "read client CA",
+		},
+		{
+			name:    "client CA without certificates",
+			cfg:     Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: keyFile},
+			wantErr: "no certificates found in " + keyFile,
+		},
+		{
+			name:    "invalid client auth",
+			cfg:     Config{TLSSelfSigned: true, TLSSelfSignedDir: t.TempDir(), TLSClientAuth: "always"},
+			wantErr: `invalid TLS_CLIENT_AUTH "always"`,
+		},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cfg.TLSConfig(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Package accesslog writes a structured JSON access log line per request,
+// correlated by a request ID taken from X-Request-ID or traceparent and echoed
+// back in X-Request-ID.
+package accesslog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader carries the request ID in requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds the X-Request-ID values that are kept; longer ones
+// are replaced by a generated ID.
+const maxRequestIDLen = 128
+
+// Logger writes access log records as JSON lines.
+type Logger struct {
+	logger *slog.Logger
+}
+
+// New creates a Logger writing to w.
+func New(w io.Writer) *Logger {
+	return &Logger{logger: slog.New(slog.NewJSONHandler(w, nil))}
+}
+
+// RequestID returns the ID correlating a request: its X-Request-ID if valid,
+// else the trace ID of its traceparent, else a random ID. traceID is the
+// trace ID of a valid traceparent, whichever ID is used.
+func RequestID(requestID, traceparent string) (id, traceID string) {
+	traceID = parseTraceparent(traceparent)
+	switch {
+	case validRequestID(requestID):
+		return requestID, traceID
+	case traceID != "":
+		return traceID, traceID
+	default:
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		return hex.EncodeToString(b), ""
+	}
+}
+
+// validRequestID accepts IDs of printable ASCII, which are safe to log and to
+// send back in a header.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTraceparent returns the trace ID of a W3C traceparent header
+// (version-traceid-parentid-flags), or "" if it is malformed.
+func parseTraceparent(value string) string {
+	parts := strings.Split(value, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return ""
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return ""
+	}
+	for _, part := range parts[:4] {
+		if strings.Trim(part, "0123456789abcdef") != "" {
+			return ""
+		}
+	}
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return ""
+	}
+	return parts[1]
+}
+
+// record is one access log line. Empty fields are left out.
+type record struct {
+	requestID   string
+	traceID     string
+	protocol    string // HTTP version
+	rpcProtocol string
+	method      string
+	route       string
+	path        string
+	status      int
+	code        string
+	duration    time.Duration
+	bytesIn     int64
+	bytesOut    int64
+	peer        string
+}
+
+func (l *Logger) write(ctx context.Context, r *record) {
+	attrs := []slog.Attr{slog.String("request_id", r.requestID)}
+	if r.traceID != "" {
+		attrs = append(attrs, slog.String("trace_id", r.traceID))
+	}
+	attrs = append(attrs, slog.String("protocol", r.protocol))
+	if r.rpcProtocol != "" {
+		attrs = append(attrs, slog.String("rpc_protocol", r.rpcProtocol))
+	}
+	attrs = append(attrs, slog.String("method", r.method), slog.String("route", r.route))
+	if r.path != "" {
+		attrs = append(attrs, slog.String("path", r.path))
+	}
+	if r.status != 0 {
+		attrs = append(attrs, slog.Int("status", r.status))
+	}
+	if r.code != "" {
+		attrs = append(attrs, slog.String("code", r.code))
+	}
+	attrs = append(attrs,
+		slog.Float64("duration_ms", float64(r.duration.Microseconds())/1000),
+		slog.Int64("bytes_in", r.bytesIn),
+		slog.Int64("bytes_out", r.bytesOut),
+		slog.String("peer", r.peer),
+	)
+	l.logger.LogAttrs(ctx, slog.LevelInfo, "access", attrs...)
+}
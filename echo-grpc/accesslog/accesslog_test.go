@@ -0,0 +1,246 @@
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-grpc/server"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestID   string
+		traceparent string
+		wantID      string
+		wantTraceID string
+	}{
+		{name: "request id", requestID: "abc-123", wantID: "abc-123"},
+		{name: "request id and traceparent", requestID: "abc-123", traceparent: traceparent, wantID: "abc-123", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "traceparent", traceparent: traceparent, wantID: "4bf92f3577b34da6a3ce929d0e0e4736", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "invalid request id", requestID: "bad id", traceparent: traceparent, wantID: "4bf92f3577b34da6a3ce929d0e0e4736", wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "zero trace id", traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "uppercase trace id", traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "extra fields in version 00", traceparent: traceparent + "-extra"},
+		{name: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, traceID := RequestID(tt.requestID, tt.traceparent)
+			if traceID != tt.wantTraceID {
+				t.Errorf("expected trace ID %q, got %q", tt.wantTraceID, traceID)
+			}
+			if tt.wantID != "" {
+				if id != tt.wantID {
+					t.Errorf("expected ID %q, got %q", tt.wantID, id)
+				}
+			} else if len(id) != 32 {
+				t.Errorf("expected a generated ID, got %q", id)
+			}
+		})
+	}
+}
+
+// lineWriter hands each log line to the test, which may read it after the
+// status has reached the client.
+type lineWriter chan []byte
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- append([]byte(nil), p...)
+	return len(p), nil
+}
+
+func (w lineWriter) next(t *testing.T) map[string]any {
+	t.Helper()
+
+	select {
+	case data := <-w:
+		var line map[string]any
+		if err := json.Unmarshal(data, &line); err != nil {
+			t.Fatalf("invalid log line %q: %v", data, err)
+		}
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("no log line written")
+		return nil
+	}
+}
+
+func setupTestServer(t *testing.T, opts ...grpc.DialOption) (pb.EchoClient, lineWriter) {
+	t.Helper()
+
+	lines := make(lineWriter, 10)
+	l := New(lines)
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(
+		grpc.StatsHandler(l),
+		grpc.ChainUnaryInterceptor(l.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(l.StreamInterceptor()),
+	)
+	pb.RegisterEchoServer(s, server.NewEchoServer())
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough://bufnet", append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		s.Stop()
+	})
+
+	return pb.NewEchoClient(conn), lines
+}
+
+func checkLine(t *testing.T, line, want map[string]any) {
+	t.Helper()
+
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("expected %s %v, got %v", key, value, line[key])
+		}
+	}
+	if _, ok := line["duration_ms"].(float64); !ok {
+		t.Errorf("expected duration_ms, got %v", line)
+	}
+}
+
+func TestLogger_Unary(t *testing.T) {
+	client, lines := setupTestServer(t)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "abc-123")
+	var header, trailer metadata.MD
+	_, err := client.EchoError(ctx, &pb.EchoErrorRequest{Code: 5}, grpc.Header(&header), grpc.Trailer(&trailer))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	// Errors keep their Trailers-Only response
+	if got := trailer.Get("x-request-id"); len(got) != 1 || got[0] != "abc-123" {
+		t.Errorf("expected the request ID echoed back in the trailer, got %v", got)
+	}
+	if got := header.Get("x-request-id"); len(got) != 0 {
+		t.Errorf("expected no request ID header on an error, got %v", got)
+	}
+
+	line := lines.next(t)
+	checkLine(t, line, map[string]any{
+		"msg":          "access",
+		"request_id":   "abc-123",
+		"protocol":     "HTTP/2.0",
+		"rpc_protocol": "grpc",
+		"method":       "POST",
+		"route":        "/echo.v1.Echo/EchoError",
+		"code":         "NotFound",
+		"bytes_out":    float64(0),
+		"peer":         "bufconn",
+	})
+	if line["bytes_in"].(float64) == 0 {
+		t.Errorf("expected request bytes counted, got %v", line)
+	}
+	if _, ok := line["status"]; ok {
+		t.Errorf("expected no HTTP status, got %v", line)
+	}
+}
+
+func TestLogger_UnaryHeader(t *testing.T) {
+	client, lines := setupTestServer(t)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "abc-123")
+	var header metadata.MD
+	if _, err := client.Echo(ctx, &pb.EchoRequest{Message: "hello"}, grpc.Header(&header)); err != nil {
+		t.Fatalf("Echo failed: %v", err)
+	}
+	if got := header.Get("x-request-id"); len(got) != 1 || got[0] != "abc-123" {
+		t.Errorf("expected the request ID echoed back in the header, got %v", got)
+	}
+	lines.next(t)
+}
+
+func TestLogger_Retry(t *testing.T) {
+	// grpc-go clients only retry Trailers-Only responses
+	client, lines := setupTestServer(t, grpc.WithDefaultServiceConfig(`{
+		"methodConfig": [{
+			"name": [{"service": "echo.v1.Echo", "method": "EchoFlaky"}],
+			"retryPolicy": {
+				"maxAttempts": 4,
+				"initialBackoff": "0.01s",
+				"maxBackoff": "0.01s",
+				"backoffMultiplier": 1,
+				"retryableStatusCodes": ["UNAVAILABLE"]
+			}
+		}]
+	}`))
+
+	resp, err := client.EchoFlaky(context.Background(), &pb.EchoFlakyRequest{
+		SequenceId: "retry",
+		Codes:      []int32{int32(codes.Unavailable), int32(codes.Unavailable), int32(codes.OK)},
+	})
+	if err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+	if resp.Attempt != 3 {
+		t.Errorf("expected attempt 3, got %d", resp.Attempt)
+	}
+
+	for _, code := range []string{"Unavailable", "Unavailable", "OK"} {
+		checkLine(t, lines.next(t), map[string]any{"route": "/echo.v1.Echo/EchoFlaky", "code": code})
+	}
+}
+
+func TestLogger_Stream(t *testing.T) {
+	client, lines := setupTestServer(t)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", traceparent)
+	stream, err := client.ServerStream(ctx, &pb.ServerStreamRequest{Message: "hello", Count: 3})
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+	}
+	header, err := stream.Header()
+	if err != nil {
+		t.Fatalf("Header failed: %v", err)
+	}
+	if got := header.Get("x-request-id"); len(got) != 1 || got[0] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the trace ID echoed back, got %v", got)
+	}
+
+	line := lines.next(t)
+	checkLine(t, line, map[string]any{
+		"request_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+		"route":      "/echo.v1.Echo/ServerStream",
+		"code":       "OK",
+	})
+	if line["bytes_out"].(float64) == 0 {
+		t.Errorf("expected response bytes counted, got %v", line)
+	}
+}
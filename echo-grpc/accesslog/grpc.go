@@ -0,0 +1,145 @@
+package accesslog
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+type recordKey struct{}
+
+// rpcRecord collects the record of an RPC, whose payload events may come
+// from the goroutines of a bidirectional stream at once.
+type rpcRecord struct {
+	record
+	in  atomic.Int64 // payload bytes received
+	out atomic.Int64 // payload bytes sent
+}
+
+// TagRPC starts the record of an RPC. Logger is a grpc stats.Handler; pair
+// it with UnaryInterceptor and StreamInterceptor to echo request IDs back.
+func (l *Logger) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, recordKey{}, &rpcRecord{record: record{
+		protocol:    "HTTP/2.0",
+		rpcProtocol: "grpc",
+		method:      http.MethodPost,
+		route:       info.FullMethodName,
+	}})
+}
+
+// HandleRPC fills in the record from the RPC events and writes it when the
+// RPC ends.
+func (l *Logger) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	rec, ok := ctx.Value(recordKey{}).(*rpcRecord)
+	if !ok || s.IsClient() {
+		return
+	}
+
+	switch s := s.(type) {
+	case *stats.InHeader:
+		rec.requestID, rec.traceID = RequestID(first(s.Header, RequestIDHeader), first(s.Header, "traceparent"))
+		if s.RemoteAddr != nil {
+			rec.peer = s.RemoteAddr.String()
+		}
+	case *stats.InPayload:
+		rec.in.Add(int64(s.WireLength))
+	case *stats.OutPayload:
+		rec.out.Add(int64(s.WireLength))
+	case *stats.End:
+		rec.code = status.Code(s.Error).String()
+		rec.duration = s.EndTime.Sub(s.BeginTime)
+		rec.bytesIn = rec.in.Load()
+		rec.bytesOut = rec.out.Load()
+		l.write(ctx, &rec.record)
+	}
+}
+
+// TagConn is a no-op; records are only written per RPC.
+func (l *Logger) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn is a no-op; records are only written per RPC.
+func (l *Logger) HandleConn(context.Context, stats.ConnStats) {}
+
+// UnaryInterceptor sends the request ID back in the x-request-id header of
+// successful RPCs, and in the trailer of failed ones: headers would turn a
+// Trailers-Only error response into one that grpc-go clients do not retry.
+func (l *Logger) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if md := requestIDHeader(ctx); md != nil {
+			// SetHeader fails if the handler already sent the headers
+			if err != nil || grpc.SetHeader(ctx, md) != nil {
+				_ = grpc.SetTrailer(ctx, md)
+			}
+		}
+		return resp, err
+	}
+}
+
+// StreamInterceptor sends the request ID back in the x-request-id header if
+// the handler sends headers or messages, else in the trailer, leaving
+// Trailers-Only error responses as they are.
+func (l *Logger) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		md := requestIDHeader(ss.Context())
+		if md == nil {
+			return handler(srv, ss)
+		}
+
+		stream := &requestIDStream{ServerStream: ss, md: md}
+		err := handler(srv, stream)
+		// Wait for a header being set by another goroutine of the handler
+		stream.once.Do(func() {})
+		if !stream.headerSet {
+			ss.SetTrailer(md)
+		}
+		return err
+	}
+}
+
+// requestIDStream adds the request ID to the headers before they are sent.
+type requestIDStream struct {
+	grpc.ServerStream
+	md        metadata.MD
+	once      sync.Once
+	headerSet bool
+}
+
+func (s *requestIDStream) setHeader() {
+	s.once.Do(func() {
+		s.headerSet = s.ServerStream.SetHeader(s.md) == nil
+	})
+}
+
+func (s *requestIDStream) SendHeader(md metadata.MD) error {
+	s.setHeader()
+	return s.ServerStream.SendHeader(md)
+}
+
+func (s *requestIDStream) SendMsg(m any) error {
+	s.setHeader()
+	return s.ServerStream.SendMsg(m)
+}
+
+func requestIDHeader(ctx context.Context) metadata.MD {
+	rec, ok := ctx.Value(recordKey{}).(*rpcRecord)
+	if !ok {
+		return nil
+	}
+	return metadata.Pairs(RequestIDHeader, rec.requestID)
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
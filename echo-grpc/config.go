@@ -1,17 +1,53 @@
 package main
 
 import (
+	"log"
+	"math"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 type Config struct {
 	Host                     string
 	Port                     string
+	Listen                   string
 	ReflectionIncludeDeps    bool
 	DisableReflectionV1      bool
 	DisableReflectionV1Alpha bool
+	TLSCertFile              string
+	TLSKeyFile               string
+	TLSClientCAFile          string
+	TLSClientAuth            string
+	TLSSelfSigned            bool
+	TLSSelfSignedDir         string
+	TLSSelfSignedHosts       string
+	MetricsPort              string
+	ShutdownDrainPeriod      time.Duration
+	ShutdownTimeout          time.Duration
+	DescriptorSetFiles       []string
+	ProtoFiles               []string
+	ProtoImportPaths         []string
+	MockRulesFile            string
+	KeepaliveTime            time.Duration
+	KeepaliveTimeout         time.Duration
+	KeepaliveMinTime         time.Duration
+	KeepalivePermitNoStream  bool
+	MaxConnectionIdle        time.Duration
+	MaxConnectionAge         time.Duration
+	MaxConnectionAgeGrace    time.Duration
+	MaxRecvMsgSize           int
+	MaxSendMsgSize           int
+	MaxConcurrentStreams     int
+	MaxHeaderListSize        int
+	AccessLog                bool
 }
 
 func LoadConfig() *Config {
@@ -21,9 +57,36 @@ func LoadConfig() *Config {
 	return &Config{
 		Host:                     getEnv("HOST", "0.0.0.0"),
 		Port:                     getEnv("PORT", "50051"),
+		Listen:                   getEnv("LISTEN", ""),
 		ReflectionIncludeDeps:    getEnvBool("REFLECTION_INCLUDE_DEPENDENCIES", false),
 		DisableReflectionV1:      getEnvBool("DISABLE_REFLECTION_V1", false),
 		DisableReflectionV1Alpha: getEnvBool("DISABLE_REFLECTION_V1ALPHA", false),
+		TLSCertFile:              getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:               getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:          getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:            getEnv("TLS_CLIENT_AUTH", ""),
+		TLSSelfSigned:            getEnvBool("TLS_SELF_SIGNED", false),
+		TLSSelfSignedDir:         getEnv("TLS_SELF_SIGNED_DIR", "tls"),
+		TLSSelfSignedHosts:       getEnv("TLS_SELF_SIGNED_HOSTS", ""),
+		MetricsPort:              getEnv("METRICS_PORT", "9090"),
+		ShutdownDrainPeriod:      getEnvDuration("SHUTDOWN_DRAIN_PERIOD", 0),
+		ShutdownTimeout:          getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		DescriptorSetFiles:       getEnvList("DESCRIPTOR_SET_FILES"),
+		ProtoFiles:               getEnvList("PROTO_FILES"),
+		ProtoImportPaths:         getEnvList("PROTO_IMPORT_PATHS"),
+		MockRulesFile:            getEnv("MOCK_RULES_FILE", ""),
+		KeepaliveTime:            getEnvDuration("KEEPALIVE_TIME", 0),
+		KeepaliveTimeout:         getEnvDuration("KEEPALIVE_TIMEOUT", 0),
+		KeepaliveMinTime:         getEnvDuration("KEEPALIVE_MIN_TIME", 0),
+		KeepalivePermitNoStream:  getEnvBool("KEEPALIVE_PERMIT_WITHOUT_STREAM", false),
+		MaxConnectionIdle:        getEnvDuration("MAX_CONNECTION_IDLE", 0),
+		MaxConnectionAge:         getEnvDuration("MAX_CONNECTION_AGE", 0),
+		MaxConnectionAgeGrace:    getEnvDuration("MAX_CONNECTION_AGE_GRACE", 0),
+		MaxRecvMsgSize:           getEnvInt("MAX_RECV_MSG_SIZE", 0),
+		MaxSendMsgSize:           getEnvInt("MAX_SEND_MSG_SIZE", 0),
+		MaxConcurrentStreams:     getEnvInt("MAX_CONCURRENT_STREAMS", 0),
+		MaxHeaderListSize:        getEnvInt("MAX_HEADER_LIST_SIZE", 0),
+		AccessLog:                getEnvBool("ACCESS_LOG", true),
 	}
 }
 
@@ -31,6 +94,11 @@ func (c *Config) Addr() string {
 	return c.Host + ":" + c.Port
 }
 
+// MetricsAddr is the address of the HTTP server exposing /metrics.
+func (c *Config) MetricsAddr() string {
+	return c.Host + ":" + c.MetricsPort
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -53,3 +121,95 @@ func getEnvBool(key string, defaultValue bool) bool {
 		return defaultValue
 	}
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		log.Fatalf("Invalid %s %q: want a non-negative integer", key, value)
+	}
+	return parsed
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvList splits a comma-separated value, dropping empty entries.
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// KeepaliveOptions configures server pings, the client ping policy and
+// connection lifetimes. Zero durations keep the grpc-go defaults.
+func (c *Config) KeepaliveOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     c.MaxConnectionIdle,
+			MaxConnectionAge:      c.MaxConnectionAge,
+			MaxConnectionAgeGrace: c.MaxConnectionAgeGrace,
+			Time:                  c.KeepaliveTime,
+			Timeout:               c.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             c.KeepaliveMinTime,
+			PermitWithoutStream: c.KeepalivePermitNoStream,
+		}),
+	}
+}
+
+// LimitOptions configures message size, concurrent stream and header list
+// limits. Zero values keep the grpc-go defaults.
+func (c *Config) LimitOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if c.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(c.MaxRecvMsgSize))
+	}
+	if c.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(c.MaxSendMsgSize))
+	}
+	if c.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(uint32(min(c.MaxConcurrentStreams, math.MaxUint32))))
+	}
+	if c.MaxHeaderListSize > 0 {
+		opts = append(opts, grpc.MaxHeaderListSize(uint32(min(c.MaxHeaderListSize, math.MaxUint32))))
+	}
+	return opts
+}
+
+// ConfigureLimits applies the concurrent stream and header list limits to
+// the servers of listeners with transport=http, which LimitOptions does not
+// reach. Zero values keep the net/http defaults.
+func (c *Config) ConfigureLimits(srv *http.Server, h2s *http2.Server) {
+	if c.MaxConcurrentStreams > 0 {
+		h2s.MaxConcurrentStreams = uint32(min(c.MaxConcurrentStreams, math.MaxUint32))
+	}
+	if c.MaxHeaderListSize > 0 {
+		srv.MaxHeaderBytes = c.MaxHeaderListSize
+	}
+}
+
+// HasDynamicServices reports whether services should be loaded from
+// descriptor sets or .proto files.
+func (c *Config) HasDynamicServices() bool {
+	return len(c.DescriptorSetFiles) > 0 || len(c.ProtoFiles) > 0
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Listener is one address the server listens on.
+type Listener struct {
+	Name    string // the address as configured, for logs
+	Network string
+	Address string
+	TLS     bool
+	HTTP    bool // served with net/http instead of the gRPC transport
+}
+
+// Listeners parses LISTEN, a comma-separated list of addresses with optional
+// tls and transport options, or returns HOST and PORT when it is unset.
+// Listeners serve TLS when it is configured unless tls=false is given.
+func (c *Config) Listeners() ([]Listener, error) {
+	if c.Listen == "" {
+		return []Listener{{Name: c.Addr(), Network: "tcp", Address: c.Addr(), TLS: c.TLSEnabled()}}, nil
+	}
+
+	var listeners []Listener
+	for _, entry := range strings.Split(c.Listen, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		l, err := c.parseListener(entry)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	if len(listeners) == 0 {
+		return nil, errors.New("LISTEN has no addresses")
+	}
+	return listeners, nil
+}
+
+// parseListener parses ADDRESS[?tls=BOOL&transport=grpc|http].
+func (c *Config) parseListener(entry string) (Listener, error) {
+	value, query, _ := strings.Cut(entry, "?")
+	network, address, err := parseListen(value)
+	if err != nil {
+		return Listener{}, err
+	}
+	options, err := url.ParseQuery(query)
+	if err != nil {
+		return Listener{}, fmt.Errorf("invalid LISTEN %q: %w", entry, err)
+	}
+
+	l := Listener{Name: value, Network: network, Address: address, TLS: c.TLSEnabled()}
+	for key, values := range options {
+		option := values[len(values)-1]
+		switch key {
+		case "tls":
+			enabled, err := strconv.ParseBool(option)
+			if err != nil {
+				return Listener{}, fmt.Errorf("invalid LISTEN %q: tls must be true or false", entry)
+			}
+			if enabled && !c.TLSEnabled() {
+				return Listener{}, fmt.Errorf("invalid LISTEN %q: tls=true needs TLS_CERT_FILE or TLS_SELF_SIGNED", entry)
+			}
+			l.TLS = enabled
+		case "transport":
+			if option != "grpc" && option != "http" {
+				return Listener{}, fmt.Errorf("invalid LISTEN %q: transport must be grpc or http", entry)
+			}
+			l.HTTP = option == "http"
+		default:
+			return Listener{}, fmt.Errorf("invalid LISTEN %q: unknown option %q", entry, key)
+		}
+	}
+	return l, nil
+}
+
+// parseListen splits an address of LISTEN into the network and address of
+// net.Listen. It accepts the gRPC naming forms tcp://HOST:PORT,
+// unix:PATH, unix:///ABSOLUTE_PATH and unix-abstract:NAME, the latter being a
+// Linux abstract socket.
+func parseListen(value string) (network, address string, err error) {
+	switch {
+	case strings.HasPrefix(value, "tcp://"):
+		address = strings.TrimPrefix(value, "tcp://")
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return "", "", fmt.Errorf("invalid LISTEN %q: %w", value, err)
+		}
+		return "tcp", address, nil
+	case strings.HasPrefix(value, "unix-abstract:"):
+		name := strings.TrimPrefix(value, "unix-abstract:")
+		if name == "" {
+			return "", "", fmt.Errorf("invalid LISTEN %q: missing socket name", value)
+		}
+		return "unix", "@" + name, nil
+	case strings.HasPrefix(value, "unix://"):
+		path := strings.TrimPrefix(value, "unix://")
+		if !strings.HasPrefix(path, "/") {
+			return "", "", fmt.Errorf("invalid LISTEN %q: unix:// needs an absolute path", value)
+		}
+		return "unix", path, nil
+	case strings.HasPrefix(value, "unix:"):
+		path := strings.TrimPrefix(value, "unix:")
+		if path == "" {
+			return "", "", fmt.Errorf("invalid LISTEN %q: missing socket path", value)
+		}
+		return "unix", path, nil
+	default:
+		return "", "", fmt.Errorf("invalid LISTEN %q: want tcp://HOST:PORT, unix:PATH or unix-abstract:NAME", value)
+	}
+}
+
+// String describes the listener for logs.
+func (l Listener) String() string {
+	var attrs []string
+	if l.TLS {
+		attrs = append(attrs, "TLS")
+	}
+	if l.HTTP {
+		attrs = append(attrs, "net/http")
+	}
+	if len(attrs) == 0 {
+		return l.Name
+	}
+	return l.Name + " (" + strings.Join(attrs, ", ") + ")"
+}
+
+// Listen opens the listener. Connections of TLS listeners served by the gRPC
+// transport are marked for listenerCredentials.
+func (l Listener) Listen() (net.Listener, error) {
+	if l.Network == "unix" && !strings.HasPrefix(l.Address, "@") {
+		// Remove the socket left behind by a previous run that did not exit
+		// cleanly; other files are kept and make Listen fail
+		if info, err := os.Stat(l.Address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(l.Address)
+		}
+	}
+	lis, err := net.Listen(l.Network, l.Address)
+	if err != nil || !l.TLS || l.HTTP {
+		return lis, err
+	}
+	return tlsListener{lis}, nil
+}
+
+// tlsListener marks the connections it accepts as TLS ones.
+type tlsListener struct {
+	net.Listener
+}
+
+func (l tlsListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return tlsConn{conn}, nil
+}
+
+type tlsConn struct {
+	net.Conn
+}
+
+// listenerCredentials performs the TLS handshake on connections accepted by
+// TLS listeners only, so that a single server can serve TLS and plaintext
+// listeners.
+type listenerCredentials struct {
+	credentials.TransportCredentials // TLS
+	plaintext                        credentials.TransportCredentials
+}
+
+func newListenerCredentials(tlsConfig *tls.Config) credentials.TransportCredentials {
+	return &listenerCredentials{
+		TransportCredentials: credentials.NewTLS(tlsConfig),
+		plaintext:            insecure.NewCredentials(),
+	}
+}
+
+func (c *listenerCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if conn, ok := rawConn.(tlsConn); ok {
+		return c.TransportCredentials.ServerHandshake(conn.Conn)
+	}
+	return c.plaintext.ServerHandshake(rawConn)
+}
+
+func (c *listenerCredentials) Clone() credentials.TransportCredentials {
+	return &listenerCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		plaintext:            c.plaintext.Clone(),
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseListen(t *testing.T) {
+	tests := []struct {
+		value       string
+		wantNetwork string
+		wantAddress string
+		wantErr     string
+	}{
+		{value: "tcp://127.0.0.1:50051", wantNetwork: "tcp", wantAddress: "127.0.0.1:50051"},
+		{value: "tcp://[::1]:50051", wantNetwork: "tcp", wantAddress: "[::1]:50051"},
+		{value: "tcp://:50051", wantNetwork: "tcp", wantAddress: ":50051"},
+		{value: "unix:echo.sock", wantNetwork: "unix", wantAddress: "echo.sock"},
+		{value: "unix:/run/echo.sock", wantNetwork: "unix", wantAddress: "/run/echo.sock"},
+		{value: "unix:///run/echo.sock", wantNetwork: "unix", wantAddress: "/run/echo.sock"},
+		{value: "unix-abstract:echo", wantNetwork: "unix", wantAddress: "@echo"},
+		{value: "tcp://127.0.0.1", wantErr: "missing port in address"},
+		{value: "unix:", wantErr: "missing socket path"},
+		{value: "unix://run/echo.sock", wantErr: "unix:// needs an absolute path"},
+		{value: "unix-abstract:", wantErr: "missing socket name"},
+		{value: "127.0.0.1:50051", wantErr: "want tcp://HOST:PORT, unix:PATH or unix-abstract:NAME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			network, address, err := parseListen(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if network != tt.wantNetwork || address != tt.wantAddress {
+				t.Errorf("expected %s %s, got %s %s", tt.wantNetwork, tt.wantAddress, network, address)
+			}
+		})
+	}
+}
+
+func TestConfig_ParseListener(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		tls     bool
+		want    Listener
+		wantErr string
+	}{
+		{
+			name:  "plain",
+			entry: "tcp://127.0.0.1:50051",
+			want:  Listener{Name: "tcp://127.0.0.1:50051", Network: "tcp", Address: "127.0.0.1:50051"},
+		},
+		{
+			name:  "TLS by default when configured",
+			entry: "tcp://127.0.0.1:50443",
+			tls:   true,
+			want:  Listener{Name: "tcp://127.0.0.1:50443", Network: "tcp", Address: "127.0.0.1:50443", TLS: true},
+		},
+		{
+			name:  "tls=false",
+			entry: "unix:echo.sock?tls=false",
+			tls:   true,
+			want:  Listener{Name: "unix:echo.sock", Network: "unix", Address: "echo.sock"},
+		},
+		{
+			name:  "transport=http with TLS",
+			entry: "tcp://127.0.0.1:50443?tls=true&transport=http",
+			tls:   true,
+			want:  Listener{Name: "tcp://127.0.0.1:50443", Network: "tcp", Address: "127.0.0.1:50443", TLS: true, HTTP: true},
+		},
+		{
+			name:  "transport=http",
+			entry: "unix-abstract:echo?transport=http",
+			want:  Listener{Name: "unix-abstract:echo", Network: "unix", Address: "@echo", HTTP: true},
+		},
+		{
+			name:  "transport=grpc",
+			entry: "unix:///run/echo.sock?transport=grpc",
+			want:  Listener{Name: "unix:///run/echo.sock", Network: "unix", Address: "/run/echo.sock"},
+		},
+		{
+			name:  "last value wins",
+			entry: "tcp://127.0.0.1:50051?transport=http&transport=grpc",
+			want:  Listener{Name: "tcp://127.0.0.1:50051", Network: "tcp", Address: "127.0.0.1:50051"},
+		},
+		{
+			name:    "invalid address",
+			entry:   "localhost:50051?tls=false",
+			wantErr: `invalid LISTEN "localhost:50051": want tcp://HOST:PORT, unix:PATH or unix-abstract:NAME`,
+		},
+		{
+			name:    "invalid query",
+			entry:   "tcp://127.0.0.1:50051?tls=%zz",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:50051?tls=%zz": invalid URL escape`,
+		},
+		{
+			name:    "invalid tls",
+			entry:   "tcp://127.0.0.1:50051?tls=yes",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:50051?tls=yes": tls must be true or false`,
+		},
+		{
+			name:    "tls=true without TLS",
+			entry:   "tcp://127.0.0.1:50051?tls=true",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:50051?tls=true": tls=true needs TLS_CERT_FILE or TLS_SELF_SIGNED`,
+		},
+		{
+			name:    "invalid transport",
+			entry:   "tcp://127.0.0.1:50051?transport=h2",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:50051?transport=h2": transport must be grpc or http`,
+		},
+		{
+			name:    "unknown option",
+			entry:   "tcp://127.0.0.1:50051?mode=fast",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:50051?mode=fast": unknown option "mode"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{TLSSelfSigned: tt.tls}
+			l, err := cfg.parseListener(tt.entry)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if l != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, l)
+			}
+		})
+	}
+}
+
+func TestConfig_Listeners(t *testing.T) {
+	t.Run("defaults to HOST and PORT", func(t *testing.T) {
+		cfg := &Config{Host: "127.0.0.1", Port: "50051", TLSSelfSigned: true}
+		listeners, err := cfg.Listeners()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := Listener{Name: "127.0.0.1:50051", Network: "tcp", Address: "127.0.0.1:50051", TLS: true}
+		if len(listeners) != 1 || listeners[0] != want {
+			t.Errorf("expected [%+v], got %+v", want, listeners)
+		}
+	})
+
+	t.Run("parses every address", func(t *testing.T) {
+		cfg := &Config{Host: "127.0.0.1", Port: "50051", Listen: " tcp://:50051 ,, unix:echo.sock?transport=http,"}
+		listeners, err := cfg.Listeners()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []Listener{
+			{Name: "tcp://:50051", Network: "tcp", Address: ":50051"},
+			{Name: "unix:echo.sock", Network: "unix", Address: "echo.sock", HTTP: true},
+		}
+		if len(listeners) != len(want) || listeners[0] != want[0] || listeners[1] != want[1] {
+			t.Errorf("expected %+v, got %+v", want, listeners)
+		}
+	})
+
+	t.Run("fails on the first invalid address", func(t *testing.T) {
+		cfg := &Config{Listen: "tcp://:50051,udp://:50051"}
+		if _, err := cfg.Listeners(); err == nil || !strings.Contains(err.Error(), `invalid LISTEN "udp://:50051"`) {
+			t.Errorf("expected error for udp://:50051, got %v", err)
+		}
+	})
+
+	t.Run("no addresses", func(t *testing.T) {
+		cfg := &Config{Listen: " , "}
+		if _, err := cfg.Listeners(); err == nil || err.Error() != "LISTEN has no addresses" {
+			t.Errorf("expected LISTEN has no addresses, got %v", err)
+		}
+	})
+}
+
+func TestListener_String(t *testing.T) {
+	tests := []struct {
+		listener Listener
+		want     string
+	}{
+		{Listener{Name: "tcp://:50051"}, "tcp://:50051"},
+		{Listener{Name: "tcp://:50443", TLS: true}, "tcp://:50443 (TLS)"},
+		{Listener{Name: "tcp://:50443", TLS: true, HTTP: true}, "tcp://:50443 (TLS, net/http)"},
+		{Listener{Name: "unix:echo.sock", HTTP: true}, "unix:echo.sock (net/http)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.listener.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
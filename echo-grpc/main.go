@@ -1,12 +1,28 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/reflect/protoregistry"
 
+	"github.com/jsr-probitas/echo-servers/echo-grpc/accesslog"
+	"github.com/jsr-probitas/echo-servers/echo-grpc/metrics"
 	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
 	"github.com/jsr-probitas/echo-servers/echo-grpc/server"
 )
@@ -14,12 +30,70 @@ import (
 func main() {
 	cfg := LoadConfig()
 
-	lis, err := net.Listen("tcp", cfg.Addr())
+	m := metrics.New()
+	opts := []grpc.ServerOption{grpc.StatsHandler(m), grpc.StatsHandler(server.CompressionStats())}
+
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		log.Fatalf("Failed to configure TLS: %v", err)
+	}
+	listeners, err := cfg.Listeners()
 	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+		log.Fatalf("Failed to configure listeners: %v", err)
+	}
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		// Only listeners with TLS enabled perform the handshake
+		creds = newListenerCredentials(tlsConfig)
+	}
+	// Let the Admin service send GOAWAY on or close connections
+	conns := server.NewConnections()
+	opts = append(opts, grpc.Creds(conns.Credentials(creds)))
+	opts = append(opts, cfg.KeepaliveOptions()...)
+	opts = append(opts, cfg.LimitOptions()...)
+
+	// Log each RPC as a JSON line correlated by x-request-id
+	if cfg.AccessLog {
+		accessLog := accesslog.New(os.Stdout)
+		opts = append(opts,
+			grpc.StatsHandler(accessLog),
+			grpc.ChainUnaryInterceptor(accessLog.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(accessLog.StreamInterceptor()),
+		)
+	}
+
+	// Load descriptors of dynamic services, which mock rules may refer to
+	var files *protoregistry.Files
+	if cfg.HasDynamicServices() {
+		files, err = server.LoadDescriptors(cfg.DescriptorSetFiles, cfg.ProtoFiles, cfg.ProtoImportPaths)
+		if err != nil {
+			log.Fatalf("Failed to load descriptors: %v", err)
+		}
+	}
+
+	// Answer calls matching mock rules with canned responses
+	if cfg.MockRulesFile != "" {
+		rules, err := server.LoadMockRules(cfg.MockRulesFile, files)
+		if err != nil {
+			log.Fatalf("Failed to load mock rules: %v", err)
+		}
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(rules.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(rules.StreamInterceptor()),
+		)
+		log.Printf("Loaded mock rules from %s", cfg.MockRulesFile)
+	}
+
+	var lns []net.Listener
+	for _, l := range listeners {
+		lis, err := l.Listen()
+		if err != nil {
+			log.Fatalf("Failed to listen on %s: %v", l, err)
+		}
+		lns = append(lns, lis)
 	}
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(opts...)
 
 	// Register echo service
 	echoServer := server.NewEchoServer()
@@ -29,11 +103,187 @@ func main() {
 	healthServer := server.NewHealthServer()
 	healthpb.RegisterHealthServer(s, healthServer)
 
+	// Register admin service (runtime health control)
+	pb.RegisterAdminServer(s, server.NewAdminServer(healthServer, conns))
+
+	// Serve services loaded from descriptor sets and .proto files
+	if files != nil {
+		services, err := server.RegisterDynamicServices(s, files)
+		if err != nil {
+			log.Fatalf("Failed to register dynamic services: %v", err)
+		}
+		for _, name := range services {
+			log.Printf("Serving dynamic service %s", name)
+		}
+	}
+
 	// Enable server reflection (v1 and v1alpha)
-	server.RegisterReflection(s, cfg.ReflectionIncludeDeps, cfg.DisableReflectionV1, cfg.DisableReflectionV1Alpha)
+	server.RegisterReflection(s, files, cfg.ReflectionIncludeDeps, cfg.DisableReflectionV1, cfg.DisableReflectionV1Alpha)
+
+	// Serve Prometheus metrics on a separate admin port
+	go func() {
+		log.Printf("Starting metrics server on %s", cfg.MetricsAddr())
+		if err := m.Serve(cfg.MetricsAddr()); err != nil {
+			log.Fatalf("Failed to serve metrics: %v", err)
+		}
+	}()
+
+	// Serve listeners with transport=http through net/http, which lets
+	// streaming RPCs reset their stream and EchoError send malformed statuses
+	tracker := &requestTracker{}
+	httpHandler := tracker.Middleware(server.NewHTTPHandler(s))
+	httpServers := make(map[int]*http.Server)
+	for i, l := range listeners {
+		if !l.HTTP {
+			continue
+		}
+		srv, err := newHTTPServer(cfg, l, httpHandler, tlsConfig)
+		if err != nil {
+			log.Fatalf("Failed to configure %s: %v", l, err)
+		}
+		httpServers[i] = srv
+	}
+
+	// Graceful shutdown: report NOT_SERVING, drain, then send GOAWAY and wait
+	// for in-flight RPCs, forcing them closed after the shutdown timeout
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
+
+		log.Println("Shutting down server...")
+		healthServer.Shutdown()
+		if cfg.ShutdownDrainPeriod > 0 {
+			log.Printf("Draining for %s", cfg.ShutdownDrainPeriod)
+			time.Sleep(cfg.ShutdownDrainPeriod)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		// Listeners with transport=http go first, since GracefulStop cannot
+		// drain calls served through ServeHTTP. Shutdown does not wait for h2c
+		// connections, which are hijacked from net/http, so wait for the
+		// tracked requests as well.
+		if err := shutdownHTTPServers(ctx, httpServers, tracker); err != nil {
+			log.Printf("Shutdown timeout exceeded, closing remaining RPCs: %v", err)
+			s.Stop()
+			return
+		}
+
+		done := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			log.Println("Shutdown timeout exceeded, closing remaining RPCs")
+			s.Stop()
+		}
+	}()
+
+	// Serve every listener; Serve returns once GracefulStop or Stop is called,
+	// and the net/http servers once they are shut down
+	errc := make(chan error, len(lns))
+	for i, lis := range lns {
+		go func() {
+			log.Printf("Listening on %s", listeners[i])
+			srv, ok := httpServers[i]
+			if !ok {
+				errc <- s.Serve(lis)
+				return
+			}
+			var err error
+			if listeners[i].TLS {
+				err = srv.ServeTLS(lis, "", "")
+			} else {
+				err = srv.Serve(lis)
+			}
+			if errors.Is(err, http.ErrServerClosed) {
+				err = nil
+			}
+			errc <- err
+		}()
+	}
+	for range lns {
+		if err := <-errc; err != nil {
+			log.Fatalf("Failed to serve: %v", err)
+		}
+	}
+
+	<-stopped
+	log.Println("Server stopped")
+}
+
+// newHTTPServer creates the server of a listener with transport=http, with
+// h2c support (HTTP/2 without TLS); gRPC needs HTTP/2.
+func newHTTPServer(cfg *Config, l Listener, handler http.Handler, tlsConfig *tls.Config) (*http.Server, error) {
+	h2s := &http2.Server{}
+	srv := &http.Server{
+		Handler:           h2c.NewHandler(handler, h2s),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if l.TLS {
+		srv.TLSConfig = tlsConfig.Clone()
+	}
+	cfg.ConfigureLimits(srv, h2s)
+
+	// Let Shutdown send GOAWAY on HTTP/2 connections, including h2c ones
+	if err := http2.ConfigureServer(srv, h2s); err != nil {
+		return nil, fmt.Errorf("configure HTTP/2: %w", err)
+	}
+	return srv, nil
+}
+
+// shutdownHTTPServers shuts the servers down and waits for the requests they
+// serve, closing the servers if ctx is done first.
+func shutdownHTTPServers(ctx context.Context, servers map[int]*http.Server, tracker *requestTracker) error {
+	var errs []error
+	for _, srv := range servers {
+		errs = append(errs, srv.Shutdown(ctx))
+	}
+	err := errors.Join(errs...)
+	if err == nil {
+		err = tracker.Wait(ctx)
+	}
+	if err != nil {
+		for _, srv := range servers {
+			_ = srv.Close()
+		}
+	}
+	return err
+}
+
+// requestTracker counts the requests currently being served.
+type requestTracker struct {
+	active atomic.Int64
+}
+
+func (t *requestTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.active.Add(1)
+		defer t.active.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Wait blocks until no request is being served or ctx is done.
+func (t *requestTracker) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
 
-	log.Printf("Starting server on %s", cfg.Addr())
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve: %v", err)
+	for t.active.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
+	return nil
 }
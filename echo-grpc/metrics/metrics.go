@@ -0,0 +1,185 @@
+// Package metrics exposes Prometheus metrics for the echo gRPC server.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics is a grpc stats.Handler that records per-method RPC metrics.
+type Metrics struct {
+	registry      *prometheus.Registry
+	started       *prometheus.CounterVec
+	handled       *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	inFlight      *prometheus.GaugeVec
+	activeStreams *prometheus.GaugeVec
+	msgReceived   *prometheus.CounterVec
+	msgSent       *prometheus.CounterVec
+	bytesReceived *prometheus.CounterVec
+	bytesSent     *prometheus.CounterVec
+}
+
+// New creates a Metrics instance backed by its own registry, which also
+// includes the Go runtime and process collectors.
+func New() *Metrics {
+	labels := []string{"grpc_service", "grpc_method", "grpc_type"}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "Total number of RPCs started on the server.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+		}, append(labels, "grpc_code")),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Latency of RPCs handled by the server.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "grpc_server_in_flight",
+			Help: "Number of RPCs currently being handled.",
+		}, labels),
+		activeStreams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "grpc_server_active_streams",
+			Help: "Number of streaming RPCs currently open.",
+		}, labels),
+		msgReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Total number of messages received from clients.",
+		}, labels),
+		msgSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Total number of messages sent to clients.",
+		}, labels),
+		bytesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_received_bytes_total",
+			Help: "Total wire bytes of messages received from clients.",
+		}, labels),
+		bytesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_sent_bytes_total",
+			Help: "Total wire bytes of messages sent to clients.",
+		}, labels),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.started,
+		m.handled,
+		m.duration,
+		m.inFlight,
+		m.activeStreams,
+		m.msgReceived,
+		m.msgSent,
+		m.bytesReceived,
+		m.bytesSent,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+type rpcInfoKey struct{}
+
+type rpcInfo struct {
+	service string
+	method  string
+	rpcType string
+}
+
+func (i *rpcInfo) labels() []string {
+	return []string{i.service, i.method, i.rpcType}
+}
+
+// TagRPC attaches the service and method name to the RPC context.
+func (m *Metrics) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	name := strings.TrimPrefix(info.FullMethodName, "/")
+	service, method, ok := strings.Cut(name, "/")
+	if !ok {
+		service, method = "unknown", name
+	}
+	return context.WithValue(ctx, rpcInfoKey{}, &rpcInfo{service: service, method: method})
+}
+
+// HandleRPC records the RPC lifecycle events.
+func (m *Metrics) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	info, ok := ctx.Value(rpcInfoKey{}).(*rpcInfo)
+	if !ok || s.IsClient() {
+		return
+	}
+
+	switch s := s.(type) {
+	case *stats.Begin:
+		info.rpcType = rpcType(s.IsClientStream, s.IsServerStream)
+		m.started.WithLabelValues(info.labels()...).Inc()
+		m.inFlight.WithLabelValues(info.labels()...).Inc()
+		if s.IsClientStream || s.IsServerStream {
+			m.activeStreams.WithLabelValues(info.labels()...).Inc()
+		}
+	case *stats.InPayload:
+		m.msgReceived.WithLabelValues(info.labels()...).Inc()
+		m.bytesReceived.WithLabelValues(info.labels()...).Add(float64(s.WireLength))
+	case *stats.OutPayload:
+		m.msgSent.WithLabelValues(info.labels()...).Inc()
+		m.bytesSent.WithLabelValues(info.labels()...).Add(float64(s.WireLength))
+	case *stats.End:
+		labels := info.labels()
+		m.inFlight.WithLabelValues(labels...).Dec()
+		if info.rpcType != "unary" {
+			m.activeStreams.WithLabelValues(labels...).Dec()
+		}
+		m.handled.WithLabelValues(append(labels, status.Code(s.Error).String())...).Inc()
+		m.duration.WithLabelValues(labels...).Observe(s.EndTime.Sub(s.BeginTime).Seconds())
+	}
+}
+
+// TagConn is a no-op; metrics are only recorded per RPC.
+func (m *Metrics) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn is a no-op; metrics are only recorded per RPC.
+func (m *Metrics) HandleConn(context.Context, stats.ConnStats) {}
+
+func rpcType(clientStream, serverStream bool) string {
+	switch {
+	case clientStream && serverStream:
+		return "bidi_stream"
+	case clientStream:
+		return "client_stream"
+	case serverStream:
+		return "server_stream"
+	default:
+		return "unary"
+	}
+}
+
+// Serve starts an HTTP server exposing /metrics on addr.
+func (m *Metrics) Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
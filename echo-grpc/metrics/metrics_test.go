@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+	"github.com/jsr-probitas/echo-servers/echo-grpc/server"
+)
+
+func setupTestServer(t *testing.T, m *Metrics) (pb.EchoClient, func()) {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.StatsHandler(m))
+	pb.RegisterEchoServer(s, server.NewEchoServer())
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	cleanup := func() {
+		_ = conn.Close()
+		s.Stop()
+	}
+
+	return pb.NewEchoClient(conn), cleanup
+}
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestMetrics_RecordsUnaryRPCs(t *testing.T) {
+	m := New()
+	client, cleanup := setupTestServer(t, m)
+	defer cleanup()
+
+	if _, err := client.Echo(context.Background(), &pb.EchoRequest{Message: "hello"}); err != nil {
+		t.Fatalf("Echo failed: %v", err)
+	}
+	_, _ = client.EchoError(context.Background(), &pb.EchoErrorRequest{Code: 5})
+
+	waitForMetric(t, m, `grpc_server_in_flight{grpc_method="EchoError",grpc_service="echo.v1.Echo",grpc_type="unary"} 0`)
+	body := scrape(t, m)
+
+	expected := []string{
+		`grpc_server_started_total{grpc_method="Echo",grpc_service="echo.v1.Echo",grpc_type="unary"} 1`,
+		`grpc_server_handled_total{grpc_code="OK",grpc_method="Echo",grpc_service="echo.v1.Echo",grpc_type="unary"} 1`,
+		`grpc_server_handled_total{grpc_code="NotFound",grpc_method="EchoError",grpc_service="echo.v1.Echo",grpc_type="unary"} 1`,
+		`grpc_server_msg_received_total{grpc_method="Echo",grpc_service="echo.v1.Echo",grpc_type="unary"} 1`,
+		`grpc_server_in_flight{grpc_method="Echo",grpc_service="echo.v1.Echo",grpc_type="unary"} 0`,
+		`grpc_server_handling_seconds_count{grpc_method="Echo",grpc_service="echo.v1.Echo",grpc_type="unary"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected metrics to contain %q", line)
+		}
+	}
+	if !strings.Contains(body, `grpc_server_received_bytes_total{grpc_method="Echo"`) {
+		t.Error("expected received bytes to be recorded")
+	}
+}
+
+func TestMetrics_TracksActiveStreams(t *testing.T) {
+	m := New()
+	client, cleanup := setupTestServer(t, m)
+	defer cleanup()
+
+	stream, err := client.BidirectionalStream(context.Background())
+	if err != nil {
+		t.Fatalf("BidirectionalStream failed: %v", err)
+	}
+	if err := stream.Send(&pb.EchoRequest{Message: "ping"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+
+	active := `grpc_server_active_streams{grpc_method="BidirectionalStream",grpc_service="echo.v1.Echo",grpc_type="bidi_stream"}`
+	if body := scrape(t, m); !strings.Contains(body, active+" 1") {
+		t.Errorf("expected %s 1 while the stream is open", active)
+	}
+
+	_ = stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+
+	waitForMetric(t, m, active+" 0")
+}
+
+// waitForMetric polls the metrics until line appears, since the server records
+// the end of an RPC after the client has already seen its status.
+func waitForMetric(t *testing.T, m *Metrics, line string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(scrape(t, m), line) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("expected metrics to contain %q", line)
+}
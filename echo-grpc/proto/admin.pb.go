@@ -0,0 +1,74 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: admin.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_admin_proto protoreflect.FileDescriptor
+
+const file_admin_proto_rawDesc = "" +
+	"\n" +
+	"\vadmin.proto\x12\aecho.v1\x1a\x17admin_connections.proto\x1a\x12admin_health.proto2\xa4\x01\n" +
+	"\x05Admin\x12B\n" +
+	"\tSetHealth\x12\x19.echo.v1.SetHealthRequest\x1a\x1a.echo.v1.SetHealthResponse\x12W\n" +
+	"\x10CloseConnections\x12 .echo.v1.CloseConnectionsRequest\x1a!.echo.v1.CloseConnectionsResponseB6Z4github.com/jsr-probitas/echo-servers/echo-grpc/protob\x06proto3"
+
+var file_admin_proto_goTypes = []any{
+	(*SetHealthRequest)(nil),         // 0: echo.v1.SetHealthRequest
+	(*CloseConnectionsRequest)(nil),  // 1: echo.v1.CloseConnectionsRequest
+	(*SetHealthResponse)(nil),        // 2: echo.v1.SetHealthResponse
+	(*CloseConnectionsResponse)(nil), // 3: echo.v1.CloseConnectionsResponse
+}
+var file_admin_proto_depIdxs = []int32{
+	0, // 0: echo.v1.Admin.SetHealth:input_type -> echo.v1.SetHealthRequest
+	1, // 1: echo.v1.Admin.CloseConnections:input_type -> echo.v1.CloseConnectionsRequest
+	2, // 2: echo.v1.Admin.SetHealth:output_type -> echo.v1.SetHealthResponse
+	3, // 3: echo.v1.Admin.CloseConnections:output_type -> echo.v1.CloseConnectionsResponse
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_admin_proto_init() }
+func file_admin_proto_init() {
+	if File_admin_proto != nil {
+		return
+	}
+	file_admin_connections_proto_init()
+	file_admin_health_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_proto_rawDesc), len(file_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admin_proto_goTypes,
+		DependencyIndexes: file_admin_proto_depIdxs,
+	}.Build()
+	File_admin_proto = out.File
+	file_admin_proto_goTypes = nil
+	file_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: admin_connections.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CloseMode int32
+
+const (
+	CloseMode_CLOSE_MODE_GOAWAY CloseMode = 0 // Send GOAWAY; calls in flight finish, new ones go to a new connection
+	CloseMode_CLOSE_MODE_CLOSE  CloseMode = 1 // Close the transport, failing calls in flight
+)
+
+// Enum value maps for CloseMode.
+var (
+	CloseMode_name = map[int32]string{
+		0: "CLOSE_MODE_GOAWAY",
+		1: "CLOSE_MODE_CLOSE",
+	}
+	CloseMode_value = map[string]int32{
+		"CLOSE_MODE_GOAWAY": 0,
+		"CLOSE_MODE_CLOSE":  1,
+	}
+)
+
+func (x CloseMode) Enum() *CloseMode {
+	p := new(CloseMode)
+	*p = x
+	return p
+}
+
+func (x CloseMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CloseMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_admin_connections_proto_enumTypes[0].Descriptor()
+}
+
+func (CloseMode) Type() protoreflect.EnumType {
+	return &file_admin_connections_proto_enumTypes[0]
+}
+
+func (x CloseMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CloseMode.Descriptor instead.
+func (CloseMode) EnumDescriptor() ([]byte, []int) {
+	return file_admin_connections_proto_rawDescGZIP(), []int{0}
+}
+
+// CloseConnections - Send GOAWAY on, or close, every client connection
+type CloseConnectionsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Mode            CloseMode              `protobuf:"varint,1,opt,name=mode,proto3,enum=echo.v1.CloseMode" json:"mode,omitempty"`
+	GoawayErrorCode uint32                 `protobuf:"varint,2,opt,name=goaway_error_code,json=goawayErrorCode,proto3" json:"goaway_error_code,omitempty"` // HTTP/2 error code of GOAWAY (0 = NO_ERROR, 11 = ENHANCE_YOUR_CALM)
+	GoawayDebugData string                 `protobuf:"bytes,3,opt,name=goaway_debug_data,json=goawayDebugData,proto3" json:"goaway_debug_data,omitempty"`  // GOAWAY debug data, such as "too_many_pings"
+	DelayMs         int32                  `protobuf:"varint,4,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`                           // Wait before acting, e.g. so the caller receives the response first
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CloseConnectionsRequest) Reset() {
+	*x = CloseConnectionsRequest{}
+	mi := &file_admin_connections_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseConnectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseConnectionsRequest) ProtoMessage() {}
+
+func (x *CloseConnectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_connections_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseConnectionsRequest.ProtoReflect.Descriptor instead.
+func (*CloseConnectionsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_connections_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CloseConnectionsRequest) GetMode() CloseMode {
+	if x != nil {
+		return x.Mode
+	}
+	return CloseMode_CLOSE_MODE_GOAWAY
+}
+
+func (x *CloseConnectionsRequest) GetGoawayErrorCode() uint32 {
+	if x != nil {
+		return x.GoawayErrorCode
+	}
+	return 0
+}
+
+func (x *CloseConnectionsRequest) GetGoawayDebugData() string {
+	if x != nil {
+		return x.GoawayDebugData
+	}
+	return ""
+}
+
+func (x *CloseConnectionsRequest) GetDelayMs() int32 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+type CloseConnectionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Connections   int32                  `protobuf:"varint,1,opt,name=connections,proto3" json:"connections,omitempty"` // Connections open when the request was made
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseConnectionsResponse) Reset() {
+	*x = CloseConnectionsResponse{}
+	mi := &file_admin_connections_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseConnectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseConnectionsResponse) ProtoMessage() {}
+
+func (x *CloseConnectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_connections_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseConnectionsResponse.ProtoReflect.Descriptor instead.
+func (*CloseConnectionsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_connections_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CloseConnectionsResponse) GetConnections() int32 {
+	if x != nil {
+		return x.Connections
+	}
+	return 0
+}
+
+var File_admin_connections_proto protoreflect.FileDescriptor
+
+const file_admin_connections_proto_rawDesc = "" +
+	"\n" +
+	"\x17admin_connections.proto\x12\aecho.v1\"\xb4\x01\n" +
+	"\x17CloseConnectionsRequest\x12&\n" +
+	"\x04mode\x18\x01 \x01(\x0e2\x12.echo.v1.CloseModeR\x04mode\x12*\n" +
+	"\x11goaway_error_code\x18\x02 \x01(\rR\x0fgoawayErrorCode\x12*\n" +
+	"\x11goaway_debug_data\x18\x03 \x01(\tR\x0fgoawayDebugData\x12\x19\n" +
+	"\bdelay_ms\x18\x04 \x01(\x05R\adelayMs\"<\n" +
+	"\x18CloseConnectionsResponse\x12 \n" +
+	"\vconnections\x18\x01 \x01(\x05R\vconnections*8\n" +
+	"\tCloseMode\x12\x15\n" +
+	"\x11CLOSE_MODE_GOAWAY\x10\x00\x12\x14\n" +
+	"\x10CLOSE_MODE_CLOSE\x10\x01B6Z4github.com/jsr-probitas/echo-servers/echo-grpc/protob\x06proto3"
+
+var (
+	file_admin_connections_proto_rawDescOnce sync.Once
+	file_admin_connections_proto_rawDescData []byte
+)
+
+func file_admin_connections_proto_rawDescGZIP() []byte {
+	file_admin_connections_proto_rawDescOnce.Do(func() {
+		file_admin_connections_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_connections_proto_rawDesc), len(file_admin_connections_proto_rawDesc)))
+	})
+	return file_admin_connections_proto_rawDescData
+}
+
+var file_admin_connections_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_admin_connections_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_admin_connections_proto_goTypes = []any{
+	(CloseMode)(0),                   // 0: echo.v1.CloseMode
+	(*CloseConnectionsRequest)(nil),  // 1: echo.v1.CloseConnectionsRequest
+	(*CloseConnectionsResponse)(nil), // 2: echo.v1.CloseConnectionsResponse
+}
+var file_admin_connections_proto_depIdxs = []int32{
+	0, // 0: echo.v1.CloseConnectionsRequest.mode:type_name -> echo.v1.CloseMode
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_admin_connections_proto_init() }
+func file_admin_connections_proto_init() {
+	if File_admin_connections_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_connections_proto_rawDesc), len(file_admin_connections_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_admin_connections_proto_goTypes,
+		DependencyIndexes: file_admin_connections_proto_depIdxs,
+		EnumInfos:         file_admin_connections_proto_enumTypes,
+		MessageInfos:      file_admin_connections_proto_msgTypes,
+	}.Build()
+	File_admin_connections_proto = out.File
+	file_admin_connections_proto_goTypes = nil
+	file_admin_connections_proto_depIdxs = nil
+}
@@ -26,19 +26,24 @@ var File_echo_proto protoreflect.FileDescriptor
 const file_echo_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"echo.proto\x12\aecho.v1\x1a\x13echo_deadline.proto\x1a\x13echo_metadata.proto\x1a\x12echo_payload.proto\x1a\x13echo_response.proto\x1a\x11echo_stream.proto\x1a\x10echo_unary.proto2\xb9\x06\n" +
-	"\x04Echo\x123\n" +
-	"\x04Echo\x12\x14.echo.v1.EchoRequest\x1a\x15.echo.v1.EchoResponse\x12E\n" +
-	"\rEchoWithDelay\x12\x1d.echo.v1.EchoWithDelayRequest\x1a\x15.echo.v1.EchoResponse\x12=\n" +
-	"\tEchoError\x12\x19.echo.v1.EchoErrorRequest\x1a\x15.echo.v1.EchoResponse\x12`\n" +
-	"\x13EchoRequestMetadata\x12#.echo.v1.EchoRequestMetadataRequest\x1a$.echo.v1.EchoRequestMetadataResponse\x12K\n" +
-	"\x10EchoWithTrailers\x12 .echo.v1.EchoWithTrailersRequest\x1a\x15.echo.v1.EchoResponse\x12W\n" +
-	"\x10EchoLargePayload\x12 .echo.v1.EchoLargePayloadRequest\x1a!.echo.v1.EchoLargePayloadResponse\x12K\n" +
-	"\fEchoDeadline\x12\x1c.echo.v1.EchoDeadlineRequest\x1a\x1d.echo.v1.EchoDeadlineResponse\x12S\n" +
-	"\x14EchoErrorWithDetails\x12$.echo.v1.EchoErrorWithDetailsRequest\x1a\x15.echo.v1.EchoResponse\x12E\n" +
+	"echo.proto\x12\aecho.v1\x1a\x16echo_compression.proto\x1a\x13echo_deadline.proto\x1a\x10echo_flaky.proto\x1a\x0fecho_flow.proto\x1a\x13echo_metadata.proto\x1a\x12echo_payload.proto\x1a\x13echo_response.proto\x1a\x11echo_stream.proto\x1a\x10echo_unary.proto2\xdd\t\n" +
+	"\x04Echo\x128\n" +
+	"\x04Echo\x12\x14.echo.v1.EchoRequest\x1a\x15.echo.v1.EchoResponse\"\x03\x90\x02\x01\x12J\n" +
+	"\rEchoWithDelay\x12\x1d.echo.v1.EchoWithDelayRequest\x1a\x15.echo.v1.EchoResponse\"\x03\x90\x02\x01\x12B\n" +
+	"\tEchoError\x12\x19.echo.v1.EchoErrorRequest\x1a\x15.echo.v1.EchoResponse\"\x03\x90\x02\x01\x12e\n" +
+	"\x13EchoRequestMetadata\x12#.echo.v1.EchoRequestMetadataRequest\x1a$.echo.v1.EchoRequestMetadataResponse\"\x03\x90\x02\x01\x12P\n" +
+	"\x10EchoWithTrailers\x12 .echo.v1.EchoWithTrailersRequest\x1a\x15.echo.v1.EchoResponse\"\x03\x90\x02\x01\x12P\n" +
+	"\x10EchoWithMetadata\x12 .echo.v1.EchoWithMetadataRequest\x1a\x15.echo.v1.EchoResponse\"\x03\x90\x02\x01\x12\\\n" +
+	"\x10EchoLargePayload\x12 .echo.v1.EchoLargePayloadRequest\x1a!.echo.v1.EchoLargePayloadResponse\"\x03\x90\x02\x01\x12Y\n" +
+	"\x0fEchoCompression\x12\x1f.echo.v1.EchoCompressionRequest\x1a .echo.v1.EchoCompressionResponse\"\x03\x90\x02\x01\x12P\n" +
+	"\fEchoDeadline\x12\x1c.echo.v1.EchoDeadlineRequest\x1a\x1d.echo.v1.EchoDeadlineResponse\"\x03\x90\x02\x01\x12X\n" +
+	"\x14EchoErrorWithDetails\x12$.echo.v1.EchoErrorWithDetailsRequest\x1a\x15.echo.v1.EchoResponse\"\x03\x90\x02\x01\x12B\n" +
+	"\tEchoFlaky\x12\x19.echo.v1.EchoFlakyRequest\x1a\x1a.echo.v1.EchoFlakyResponse\x12E\n" +
 	"\fServerStream\x12\x1c.echo.v1.ServerStreamRequest\x1a\x15.echo.v1.EchoResponse0\x01\x12=\n" +
 	"\fClientStream\x12\x14.echo.v1.EchoRequest\x1a\x15.echo.v1.EchoResponse(\x01\x12F\n" +
-	"\x13BidirectionalStream\x12\x14.echo.v1.EchoRequest\x1a\x15.echo.v1.EchoResponse(\x010\x01B6Z4github.com/jsr-probitas/echo-servers/echo-grpc/protob\x06proto3"
+	"\x13BidirectionalStream\x12\x14.echo.v1.EchoRequest\x1a\x15.echo.v1.EchoResponse(\x010\x01\x12>\n" +
+	"\vFloodStream\x12\x15.echo.v1.FloodRequest\x1a\x16.echo.v1.FloodResponse0\x01\x12I\n" +
+	"\x0eSlowReadStream\x12\x18.echo.v1.SlowReadRequest\x1a\x19.echo.v1.SlowReadResponse(\x010\x01B6Z4github.com/jsr-probitas/echo-servers/echo-grpc/protob\x06proto3"
 
 var file_echo_proto_goTypes = []any{
 	(*EchoRequest)(nil),                 // 0: echo.v1.EchoRequest
@@ -46,14 +51,23 @@ var file_echo_proto_goTypes = []any{
 	(*EchoErrorRequest)(nil),            // 2: echo.v1.EchoErrorRequest
 	(*EchoRequestMetadataRequest)(nil),  // 3: echo.v1.EchoRequestMetadataRequest
 	(*EchoWithTrailersRequest)(nil),     // 4: echo.v1.EchoWithTrailersRequest
-	(*EchoLargePayloadRequest)(nil),     // 5: echo.v1.EchoLargePayloadRequest
-	(*EchoDeadlineRequest)(nil),         // 6: echo.v1.EchoDeadlineRequest
-	(*EchoErrorWithDetailsRequest)(nil), // 7: echo.v1.EchoErrorWithDetailsRequest
-	(*ServerStreamRequest)(nil),         // 8: echo.v1.ServerStreamRequest
-	(*EchoResponse)(nil),                // 9: echo.v1.EchoResponse
-	(*EchoRequestMetadataResponse)(nil), // 10: echo.v1.EchoRequestMetadataResponse
-	(*EchoLargePayloadResponse)(nil),    // 11: echo.v1.EchoLargePayloadResponse
-	(*EchoDeadlineResponse)(nil),        // 12: echo.v1.EchoDeadlineResponse
+	(*EchoWithMetadataRequest)(nil),     // 5: echo.v1.EchoWithMetadataRequest
+	(*EchoLargePayloadRequest)(nil),     // 6: echo.v1.EchoLargePayloadRequest
+	(*EchoCompressionRequest)(nil),      // 7: echo.v1.EchoCompressionRequest
+	(*EchoDeadlineRequest)(nil),         // 8: echo.v1.EchoDeadlineRequest
+	(*EchoErrorWithDetailsRequest)(nil), // 9: echo.v1.EchoErrorWithDetailsRequest
+	(*EchoFlakyRequest)(nil),            // 10: echo.v1.EchoFlakyRequest
+	(*ServerStreamRequest)(nil),         // 11: echo.v1.ServerStreamRequest
+	(*FloodRequest)(nil),                // 12: echo.v1.FloodRequest
+	(*SlowReadRequest)(nil),             // 13: echo.v1.SlowReadRequest
+	(*EchoResponse)(nil),                // 14: echo.v1.EchoResponse
+	(*EchoRequestMetadataResponse)(nil), // 15: echo.v1.EchoRequestMetadataResponse
+	(*EchoLargePayloadResponse)(nil),    // 16: echo.v1.EchoLargePayloadResponse
+	(*EchoCompressionResponse)(nil),     // 17: echo.v1.EchoCompressionResponse
+	(*EchoDeadlineResponse)(nil),        // 18: echo.v1.EchoDeadlineResponse
+	(*EchoFlakyResponse)(nil),           // 19: echo.v1.EchoFlakyResponse
+	(*FloodResponse)(nil),               // 20: echo.v1.FloodResponse
+	(*SlowReadResponse)(nil),            // 21: echo.v1.SlowReadResponse
 }
 var file_echo_proto_depIdxs = []int32{
 	0,  // 0: echo.v1.Echo.Echo:input_type -> echo.v1.EchoRequest
@@ -61,25 +75,35 @@ var file_echo_proto_depIdxs = []int32{
 	2,  // 2: echo.v1.Echo.EchoError:input_type -> echo.v1.EchoErrorRequest
 	3,  // 3: echo.v1.Echo.EchoRequestMetadata:input_type -> echo.v1.EchoRequestMetadataRequest
 	4,  // 4: echo.v1.Echo.EchoWithTrailers:input_type -> echo.v1.EchoWithTrailersRequest
-	5,  // 5: echo.v1.Echo.EchoLargePayload:input_type -> echo.v1.EchoLargePayloadRequest
-	6,  // 6: echo.v1.Echo.EchoDeadline:input_type -> echo.v1.EchoDeadlineRequest
-	7,  // 7: echo.v1.Echo.EchoErrorWithDetails:input_type -> echo.v1.EchoErrorWithDetailsRequest
-	8,  // 8: echo.v1.Echo.ServerStream:input_type -> echo.v1.ServerStreamRequest
-	0,  // 9: echo.v1.Echo.ClientStream:input_type -> echo.v1.EchoRequest
-	0,  // 10: echo.v1.Echo.BidirectionalStream:input_type -> echo.v1.EchoRequest
-	9,  // 11: echo.v1.Echo.Echo:output_type -> echo.v1.EchoResponse
-	9,  // 12: echo.v1.Echo.EchoWithDelay:output_type -> echo.v1.EchoResponse
-	9,  // 13: echo.v1.Echo.EchoError:output_type -> echo.v1.EchoResponse
-	10, // 14: echo.v1.Echo.EchoRequestMetadata:output_type -> echo.v1.EchoRequestMetadataResponse
-	9,  // 15: echo.v1.Echo.EchoWithTrailers:output_type -> echo.v1.EchoResponse
-	11, // 16: echo.v1.Echo.EchoLargePayload:output_type -> echo.v1.EchoLargePayloadResponse
-	12, // 17: echo.v1.Echo.EchoDeadline:output_type -> echo.v1.EchoDeadlineResponse
-	9,  // 18: echo.v1.Echo.EchoErrorWithDetails:output_type -> echo.v1.EchoResponse
-	9,  // 19: echo.v1.Echo.ServerStream:output_type -> echo.v1.EchoResponse
-	9,  // 20: echo.v1.Echo.ClientStream:output_type -> echo.v1.EchoResponse
-	9,  // 21: echo.v1.Echo.BidirectionalStream:output_type -> echo.v1.EchoResponse
-	11, // [11:22] is the sub-list for method output_type
-	0,  // [0:11] is the sub-list for method input_type
+	5,  // 5: echo.v1.Echo.EchoWithMetadata:input_type -> echo.v1.EchoWithMetadataRequest
+	6,  // 6: echo.v1.Echo.EchoLargePayload:input_type -> echo.v1.EchoLargePayloadRequest
+	7,  // 7: echo.v1.Echo.EchoCompression:input_type -> echo.v1.EchoCompressionRequest
+	8,  // 8: echo.v1.Echo.EchoDeadline:input_type -> echo.v1.EchoDeadlineRequest
+	9,  // 9: echo.v1.Echo.EchoErrorWithDetails:input_type -> echo.v1.EchoErrorWithDetailsRequest
+	10, // 10: echo.v1.Echo.EchoFlaky:input_type -> echo.v1.EchoFlakyRequest
+	11, // 11: echo.v1.Echo.ServerStream:input_type -> echo.v1.ServerStreamRequest
+	0,  // 12: echo.v1.Echo.ClientStream:input_type -> echo.v1.EchoRequest
+	0,  // 13: echo.v1.Echo.BidirectionalStream:input_type -> echo.v1.EchoRequest
+	12, // 14: echo.v1.Echo.FloodStream:input_type -> echo.v1.FloodRequest
+	13, // 15: echo.v1.Echo.SlowReadStream:input_type -> echo.v1.SlowReadRequest
+	14, // 16: echo.v1.Echo.Echo:output_type -> echo.v1.EchoResponse
+	14, // 17: echo.v1.Echo.EchoWithDelay:output_type -> echo.v1.EchoResponse
+	14, // 18: echo.v1.Echo.EchoError:output_type -> echo.v1.EchoResponse
+	15, // 19: echo.v1.Echo.EchoRequestMetadata:output_type -> echo.v1.EchoRequestMetadataResponse
+	14, // 20: echo.v1.Echo.EchoWithTrailers:output_type -> echo.v1.EchoResponse
+	14, // 21: echo.v1.Echo.EchoWithMetadata:output_type -> echo.v1.EchoResponse
+	16, // 22: echo.v1.Echo.EchoLargePayload:output_type -> echo.v1.EchoLargePayloadResponse
+	17, // 23: echo.v1.Echo.EchoCompression:output_type -> echo.v1.EchoCompressionResponse
+	18, // 24: echo.v1.Echo.EchoDeadline:output_type -> echo.v1.EchoDeadlineResponse
+	14, // 25: echo.v1.Echo.EchoErrorWithDetails:output_type -> echo.v1.EchoResponse
+	19, // 26: echo.v1.Echo.EchoFlaky:output_type -> echo.v1.EchoFlakyResponse
+	14, // 27: echo.v1.Echo.ServerStream:output_type -> echo.v1.EchoResponse
+	14, // 28: echo.v1.Echo.ClientStream:output_type -> echo.v1.EchoResponse
+	14, // 29: echo.v1.Echo.BidirectionalStream:output_type -> echo.v1.EchoResponse
+	20, // 30: echo.v1.Echo.FloodStream:output_type -> echo.v1.FloodResponse
+	21, // 31: echo.v1.Echo.SlowReadStream:output_type -> echo.v1.SlowReadResponse
+	16, // [16:32] is the sub-list for method output_type
+	0,  // [0:16] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -90,7 +114,10 @@ func file_echo_proto_init() {
 	if File_echo_proto != nil {
 		return
 	}
+	file_echo_compression_proto_init()
 	file_echo_deadline_proto_init()
+	file_echo_flaky_proto_init()
+	file_echo_flow_proto_init()
 	file_echo_metadata_proto_init()
 	file_echo_payload_proto_init()
 	file_echo_response_proto_init()
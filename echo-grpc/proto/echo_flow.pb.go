@@ -0,0 +1,355 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: echo_flow.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FloodStream - Send messages back-to-back without pacing
+type FloodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int32                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`                                      // Number of messages (default 1000, max 100000)
+	PayloadBytes  int32                  `protobuf:"varint,2,opt,name=payload_bytes,json=payloadBytes,proto3" json:"payload_bytes,omitempty"`    // Payload size of each message (max 65536)
+	OversizeAt    int32                  `protobuf:"varint,3,opt,name=oversize_at,json=oversizeAt,proto3" json:"oversize_at,omitempty"`          // 1-based index of the message sent with oversize_bytes instead (0 = none)
+	OversizeBytes int32                  `protobuf:"varint,4,opt,name=oversize_bytes,json=oversizeBytes,proto3" json:"oversize_bytes,omitempty"` // Payload size of the oversize message (max 10MB)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FloodRequest) Reset() {
+	*x = FloodRequest{}
+	mi := &file_echo_flow_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FloodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FloodRequest) ProtoMessage() {}
+
+func (x *FloodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_flow_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FloodRequest.ProtoReflect.Descriptor instead.
+func (*FloodRequest) Descriptor() ([]byte, []int) {
+	return file_echo_flow_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FloodRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *FloodRequest) GetPayloadBytes() int32 {
+	if x != nil {
+		return x.PayloadBytes
+	}
+	return 0
+}
+
+func (x *FloodRequest) GetOversizeAt() int32 {
+	if x != nil {
+		return x.OversizeAt
+	}
+	return 0
+}
+
+func (x *FloodRequest) GetOversizeBytes() int32 {
+	if x != nil {
+		return x.OversizeBytes
+	}
+	return 0
+}
+
+type FloodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      int32                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`          // 1-based message index
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"` // When the server started sending the message
+	Payload       []byte                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FloodResponse) Reset() {
+	*x = FloodResponse{}
+	mi := &file_echo_flow_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FloodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FloodResponse) ProtoMessage() {}
+
+func (x *FloodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_flow_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FloodResponse.ProtoReflect.Descriptor instead.
+func (*FloodResponse) Descriptor() ([]byte, []int) {
+	return file_echo_flow_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FloodResponse) GetSequence() int32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *FloodResponse) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *FloodResponse) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// SlowReadStream - Echo messages, but stop reading for a while to fill the
+// flow-control window. Options are taken from the first message.
+type SlowReadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	PauseAfter    int32                  `protobuf:"varint,2,opt,name=pause_after,json=pauseAfter,proto3" json:"pause_after,omitempty"` // Stop reading after this many messages (default 1)
+	PauseMs       int32                  `protobuf:"varint,3,opt,name=pause_ms,json=pauseMs,proto3" json:"pause_ms,omitempty"`          // How long to stop reading (max 60000)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlowReadRequest) Reset() {
+	*x = SlowReadRequest{}
+	mi := &file_echo_flow_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlowReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlowReadRequest) ProtoMessage() {}
+
+func (x *SlowReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_flow_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlowReadRequest.ProtoReflect.Descriptor instead.
+func (*SlowReadRequest) Descriptor() ([]byte, []int) {
+	return file_echo_flow_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SlowReadRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SlowReadRequest) GetPauseAfter() int32 {
+	if x != nil {
+		return x.PauseAfter
+	}
+	return 0
+}
+
+func (x *SlowReadRequest) GetPauseMs() int32 {
+	if x != nil {
+		return x.PauseMs
+	}
+	return 0
+}
+
+type SlowReadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Sequence      int32                  `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`                      // 1-based message index
+	ReceivedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"` // When the server read the message
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlowReadResponse) Reset() {
+	*x = SlowReadResponse{}
+	mi := &file_echo_flow_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlowReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlowReadResponse) ProtoMessage() {}
+
+func (x *SlowReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_flow_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlowReadResponse.ProtoReflect.Descriptor instead.
+func (*SlowReadResponse) Descriptor() ([]byte, []int) {
+	return file_echo_flow_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SlowReadResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SlowReadResponse) GetSequence() int32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *SlowReadResponse) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+var File_echo_flow_proto protoreflect.FileDescriptor
+
+const file_echo_flow_proto_rawDesc = "" +
+	"\n" +
+	"\x0fecho_flow.proto\x12\aecho.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x91\x01\n" +
+	"\fFloodRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\x12#\n" +
+	"\rpayload_bytes\x18\x02 \x01(\x05R\fpayloadBytes\x12\x1f\n" +
+	"\voversize_at\x18\x03 \x01(\x05R\n" +
+	"oversizeAt\x12%\n" +
+	"\x0eoversize_bytes\x18\x04 \x01(\x05R\roversizeBytes\"z\n" +
+	"\rFloodResponse\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x05R\bsequence\x123\n" +
+	"\asent_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\"g\n" +
+	"\x0fSlowReadRequest\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1f\n" +
+	"\vpause_after\x18\x02 \x01(\x05R\n" +
+	"pauseAfter\x12\x19\n" +
+	"\bpause_ms\x18\x03 \x01(\x05R\apauseMs\"\x85\x01\n" +
+	"\x10SlowReadResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x05R\bsequence\x12;\n" +
+	"\vreceived_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"receivedAtB6Z4github.com/jsr-probitas/echo-servers/echo-grpc/protob\x06proto3"
+
+var (
+	file_echo_flow_proto_rawDescOnce sync.Once
+	file_echo_flow_proto_rawDescData []byte
+)
+
+func file_echo_flow_proto_rawDescGZIP() []byte {
+	file_echo_flow_proto_rawDescOnce.Do(func() {
+		file_echo_flow_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_echo_flow_proto_rawDesc), len(file_echo_flow_proto_rawDesc)))
+	})
+	return file_echo_flow_proto_rawDescData
+}
+
+var file_echo_flow_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_echo_flow_proto_goTypes = []any{
+	(*FloodRequest)(nil),          // 0: echo.v1.FloodRequest
+	(*FloodResponse)(nil),         // 1: echo.v1.FloodResponse
+	(*SlowReadRequest)(nil),       // 2: echo.v1.SlowReadRequest
+	(*SlowReadResponse)(nil),      // 3: echo.v1.SlowReadResponse
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_echo_flow_proto_depIdxs = []int32{
+	4, // 0: echo.v1.FloodResponse.sent_at:type_name -> google.protobuf.Timestamp
+	4, // 1: echo.v1.SlowReadResponse.received_at:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_echo_flow_proto_init() }
+func file_echo_flow_proto_init() {
+	if File_echo_flow_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_echo_flow_proto_rawDesc), len(file_echo_flow_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_echo_flow_proto_goTypes,
+		DependencyIndexes: file_echo_flow_proto_depIdxs,
+		MessageInfos:      file_echo_flow_proto_msgTypes,
+	}.Build()
+	File_echo_flow_proto = out.File
+	file_echo_flow_proto_goTypes = nil
+	file_echo_flow_proto_depIdxs = nil
+}
@@ -25,12 +25,17 @@ const (
 	Echo_EchoError_FullMethodName            = "/echo.v1.Echo/EchoError"
 	Echo_EchoRequestMetadata_FullMethodName  = "/echo.v1.Echo/EchoRequestMetadata"
 	Echo_EchoWithTrailers_FullMethodName     = "/echo.v1.Echo/EchoWithTrailers"
+	Echo_EchoWithMetadata_FullMethodName     = "/echo.v1.Echo/EchoWithMetadata"
 	Echo_EchoLargePayload_FullMethodName     = "/echo.v1.Echo/EchoLargePayload"
+	Echo_EchoCompression_FullMethodName      = "/echo.v1.Echo/EchoCompression"
 	Echo_EchoDeadline_FullMethodName         = "/echo.v1.Echo/EchoDeadline"
 	Echo_EchoErrorWithDetails_FullMethodName = "/echo.v1.Echo/EchoErrorWithDetails"
+	Echo_EchoFlaky_FullMethodName            = "/echo.v1.Echo/EchoFlaky"
 	Echo_ServerStream_FullMethodName         = "/echo.v1.Echo/ServerStream"
 	Echo_ClientStream_FullMethodName         = "/echo.v1.Echo/ClientStream"
 	Echo_BidirectionalStream_FullMethodName  = "/echo.v1.Echo/BidirectionalStream"
+	Echo_FloodStream_FullMethodName          = "/echo.v1.Echo/FloodStream"
+	Echo_SlowReadStream_FullMethodName       = "/echo.v1.Echo/SlowReadStream"
 )
 
 // EchoClient is the client API for Echo service.
@@ -46,16 +51,24 @@ type EchoClient interface {
 	// Metadata/Headers RPCs
 	EchoRequestMetadata(ctx context.Context, in *EchoRequestMetadataRequest, opts ...grpc.CallOption) (*EchoRequestMetadataResponse, error)
 	EchoWithTrailers(ctx context.Context, in *EchoWithTrailersRequest, opts ...grpc.CallOption) (*EchoResponse, error)
+	EchoWithMetadata(ctx context.Context, in *EchoWithMetadataRequest, opts ...grpc.CallOption) (*EchoResponse, error)
 	// Payload Testing RPCs
 	EchoLargePayload(ctx context.Context, in *EchoLargePayloadRequest, opts ...grpc.CallOption) (*EchoLargePayloadResponse, error)
+	// Compression Testing RPCs
+	EchoCompression(ctx context.Context, in *EchoCompressionRequest, opts ...grpc.CallOption) (*EchoCompressionResponse, error)
 	// Deadline/Timeout RPCs
 	EchoDeadline(ctx context.Context, in *EchoDeadlineRequest, opts ...grpc.CallOption) (*EchoDeadlineResponse, error)
 	// Error Scenarios RPCs
 	EchoErrorWithDetails(ctx context.Context, in *EchoErrorWithDetailsRequest, opts ...grpc.CallOption) (*EchoResponse, error)
+	// Retry Testing RPCs
+	EchoFlaky(ctx context.Context, in *EchoFlakyRequest, opts ...grpc.CallOption) (*EchoFlakyResponse, error)
 	// Streaming RPCs
 	ServerStream(ctx context.Context, in *ServerStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EchoResponse], error)
 	ClientStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[EchoRequest, EchoResponse], error)
 	BidirectionalStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[EchoRequest, EchoResponse], error)
+	// Flow Control Testing RPCs
+	FloodStream(ctx context.Context, in *FloodRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FloodResponse], error)
+	SlowReadStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SlowReadRequest, SlowReadResponse], error)
 }
 
 type echoClient struct {
@@ -116,6 +129,16 @@ func (c *echoClient) EchoWithTrailers(ctx context.Context, in *EchoWithTrailersR
 	return out, nil
 }
 
+func (c *echoClient) EchoWithMetadata(ctx context.Context, in *EchoWithMetadataRequest, opts ...grpc.CallOption) (*EchoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EchoResponse)
+	err := c.cc.Invoke(ctx, Echo_EchoWithMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *echoClient) EchoLargePayload(ctx context.Context, in *EchoLargePayloadRequest, opts ...grpc.CallOption) (*EchoLargePayloadResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(EchoLargePayloadResponse)
@@ -126,6 +149,16 @@ func (c *echoClient) EchoLargePayload(ctx context.Context, in *EchoLargePayloadR
 	return out, nil
 }
 
+func (c *echoClient) EchoCompression(ctx context.Context, in *EchoCompressionRequest, opts ...grpc.CallOption) (*EchoCompressionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EchoCompressionResponse)
+	err := c.cc.Invoke(ctx, Echo_EchoCompression_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *echoClient) EchoDeadline(ctx context.Context, in *EchoDeadlineRequest, opts ...grpc.CallOption) (*EchoDeadlineResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(EchoDeadlineResponse)
@@ -146,6 +179,16 @@ func (c *echoClient) EchoErrorWithDetails(ctx context.Context, in *EchoErrorWith
 	return out, nil
 }
 
+func (c *echoClient) EchoFlaky(ctx context.Context, in *EchoFlakyRequest, opts ...grpc.CallOption) (*EchoFlakyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EchoFlakyResponse)
+	err := c.cc.Invoke(ctx, Echo_EchoFlaky_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *echoClient) ServerStream(ctx context.Context, in *ServerStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EchoResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &Echo_ServiceDesc.Streams[0], Echo_ServerStream_FullMethodName, cOpts...)
@@ -191,6 +234,38 @@ func (c *echoClient) BidirectionalStream(ctx context.Context, opts ...grpc.CallO
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Echo_BidirectionalStreamClient = grpc.BidiStreamingClient[EchoRequest, EchoResponse]
 
+func (c *echoClient) FloodStream(ctx context.Context, in *FloodRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FloodResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Echo_ServiceDesc.Streams[3], Echo_FloodStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FloodRequest, FloodResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Echo_FloodStreamClient = grpc.ServerStreamingClient[FloodResponse]
+
+func (c *echoClient) SlowReadStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SlowReadRequest, SlowReadResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Echo_ServiceDesc.Streams[4], Echo_SlowReadStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SlowReadRequest, SlowReadResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Echo_SlowReadStreamClient = grpc.BidiStreamingClient[SlowReadRequest, SlowReadResponse]
+
 // EchoServer is the server API for Echo service.
 // All implementations must embed UnimplementedEchoServer
 // for forward compatibility.
@@ -204,16 +279,24 @@ type EchoServer interface {
 	// Metadata/Headers RPCs
 	EchoRequestMetadata(context.Context, *EchoRequestMetadataRequest) (*EchoRequestMetadataResponse, error)
 	EchoWithTrailers(context.Context, *EchoWithTrailersRequest) (*EchoResponse, error)
+	EchoWithMetadata(context.Context, *EchoWithMetadataRequest) (*EchoResponse, error)
 	// Payload Testing RPCs
 	EchoLargePayload(context.Context, *EchoLargePayloadRequest) (*EchoLargePayloadResponse, error)
+	// Compression Testing RPCs
+	EchoCompression(context.Context, *EchoCompressionRequest) (*EchoCompressionResponse, error)
 	// Deadline/Timeout RPCs
 	EchoDeadline(context.Context, *EchoDeadlineRequest) (*EchoDeadlineResponse, error)
 	// Error Scenarios RPCs
 	EchoErrorWithDetails(context.Context, *EchoErrorWithDetailsRequest) (*EchoResponse, error)
+	// Retry Testing RPCs
+	EchoFlaky(context.Context, *EchoFlakyRequest) (*EchoFlakyResponse, error)
 	// Streaming RPCs
 	ServerStream(*ServerStreamRequest, grpc.ServerStreamingServer[EchoResponse]) error
 	ClientStream(grpc.ClientStreamingServer[EchoRequest, EchoResponse]) error
 	BidirectionalStream(grpc.BidiStreamingServer[EchoRequest, EchoResponse]) error
+	// Flow Control Testing RPCs
+	FloodStream(*FloodRequest, grpc.ServerStreamingServer[FloodResponse]) error
+	SlowReadStream(grpc.BidiStreamingServer[SlowReadRequest, SlowReadResponse]) error
 	mustEmbedUnimplementedEchoServer()
 }
 
@@ -239,15 +322,24 @@ func (UnimplementedEchoServer) EchoRequestMetadata(context.Context, *EchoRequest
 func (UnimplementedEchoServer) EchoWithTrailers(context.Context, *EchoWithTrailersRequest) (*EchoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EchoWithTrailers not implemented")
 }
+func (UnimplementedEchoServer) EchoWithMetadata(context.Context, *EchoWithMetadataRequest) (*EchoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EchoWithMetadata not implemented")
+}
 func (UnimplementedEchoServer) EchoLargePayload(context.Context, *EchoLargePayloadRequest) (*EchoLargePayloadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EchoLargePayload not implemented")
 }
+func (UnimplementedEchoServer) EchoCompression(context.Context, *EchoCompressionRequest) (*EchoCompressionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EchoCompression not implemented")
+}
 func (UnimplementedEchoServer) EchoDeadline(context.Context, *EchoDeadlineRequest) (*EchoDeadlineResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EchoDeadline not implemented")
 }
 func (UnimplementedEchoServer) EchoErrorWithDetails(context.Context, *EchoErrorWithDetailsRequest) (*EchoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EchoErrorWithDetails not implemented")
 }
+func (UnimplementedEchoServer) EchoFlaky(context.Context, *EchoFlakyRequest) (*EchoFlakyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EchoFlaky not implemented")
+}
 func (UnimplementedEchoServer) ServerStream(*ServerStreamRequest, grpc.ServerStreamingServer[EchoResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method ServerStream not implemented")
 }
@@ -257,6 +349,12 @@ func (UnimplementedEchoServer) ClientStream(grpc.ClientStreamingServer[EchoReque
 func (UnimplementedEchoServer) BidirectionalStream(grpc.BidiStreamingServer[EchoRequest, EchoResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method BidirectionalStream not implemented")
 }
+func (UnimplementedEchoServer) FloodStream(*FloodRequest, grpc.ServerStreamingServer[FloodResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method FloodStream not implemented")
+}
+func (UnimplementedEchoServer) SlowReadStream(grpc.BidiStreamingServer[SlowReadRequest, SlowReadResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method SlowReadStream not implemented")
+}
 func (UnimplementedEchoServer) mustEmbedUnimplementedEchoServer() {}
 func (UnimplementedEchoServer) testEmbeddedByValue()              {}
 
@@ -368,6 +466,24 @@ func _Echo_EchoWithTrailers_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Echo_EchoWithMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EchoWithMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchoServer).EchoWithMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Echo_EchoWithMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchoServer).EchoWithMetadata(ctx, req.(*EchoWithMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Echo_EchoLargePayload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(EchoLargePayloadRequest)
 	if err := dec(in); err != nil {
@@ -386,6 +502,24 @@ func _Echo_EchoLargePayload_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Echo_EchoCompression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EchoCompressionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchoServer).EchoCompression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Echo_EchoCompression_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchoServer).EchoCompression(ctx, req.(*EchoCompressionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Echo_EchoDeadline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(EchoDeadlineRequest)
 	if err := dec(in); err != nil {
@@ -422,6 +556,24 @@ func _Echo_EchoErrorWithDetails_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Echo_EchoFlaky_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EchoFlakyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchoServer).EchoFlaky(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Echo_EchoFlaky_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchoServer).EchoFlaky(ctx, req.(*EchoFlakyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Echo_ServerStream_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(ServerStreamRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -447,6 +599,24 @@ func _Echo_BidirectionalStream_Handler(srv interface{}, stream grpc.ServerStream
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Echo_BidirectionalStreamServer = grpc.BidiStreamingServer[EchoRequest, EchoResponse]
 
+func _Echo_FloodStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FloodRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EchoServer).FloodStream(m, &grpc.GenericServerStream[FloodRequest, FloodResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Echo_FloodStreamServer = grpc.ServerStreamingServer[FloodResponse]
+
+func _Echo_SlowReadStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EchoServer).SlowReadStream(&grpc.GenericServerStream[SlowReadRequest, SlowReadResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Echo_SlowReadStreamServer = grpc.BidiStreamingServer[SlowReadRequest, SlowReadResponse]
+
 // Echo_ServiceDesc is the grpc.ServiceDesc for Echo service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -474,10 +644,18 @@ var Echo_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "EchoWithTrailers",
 			Handler:    _Echo_EchoWithTrailers_Handler,
 		},
+		{
+			MethodName: "EchoWithMetadata",
+			Handler:    _Echo_EchoWithMetadata_Handler,
+		},
 		{
 			MethodName: "EchoLargePayload",
 			Handler:    _Echo_EchoLargePayload_Handler,
 		},
+		{
+			MethodName: "EchoCompression",
+			Handler:    _Echo_EchoCompression_Handler,
+		},
 		{
 			MethodName: "EchoDeadline",
 			Handler:    _Echo_EchoDeadline_Handler,
@@ -486,6 +664,10 @@ var Echo_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "EchoErrorWithDetails",
 			Handler:    _Echo_EchoErrorWithDetails_Handler,
 		},
+		{
+			MethodName: "EchoFlaky",
+			Handler:    _Echo_EchoFlaky_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -504,6 +686,17 @@ var Echo_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "FloodStream",
+			Handler:       _Echo_FloodStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SlowReadStream",
+			Handler:       _Echo_SlowReadStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "echo.proto",
 }
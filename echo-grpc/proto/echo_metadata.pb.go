@@ -209,6 +209,144 @@ func (x *EchoWithTrailersRequest) GetTrailers() map[string]string {
 	return nil
 }
 
+// MetadataEntry - One metadata value; repeat a key to send several values
+type MetadataEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`                                // Text value
+	BinaryValue   []byte                 `protobuf:"bytes,3,opt,name=binary_value,json=binaryValue,proto3" json:"binary_value,omitempty"` // Raw value, only for keys ending in "-bin"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetadataEntry) Reset() {
+	*x = MetadataEntry{}
+	mi := &file_echo_metadata_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetadataEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetadataEntry) ProtoMessage() {}
+
+func (x *MetadataEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_metadata_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetadataEntry.ProtoReflect.Descriptor instead.
+func (*MetadataEntry) Descriptor() ([]byte, []int) {
+	return file_echo_metadata_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MetadataEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *MetadataEntry) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *MetadataEntry) GetBinaryValue() []byte {
+	if x != nil {
+		return x.BinaryValue
+	}
+	return nil
+}
+
+// EchoWithMetadata - Return response with chosen headers, trailers and status
+type EchoWithMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Headers       []*MetadataEntry       `protobuf:"bytes,2,rep,name=headers,proto3" json:"headers,omitempty"`                          // Initial metadata (response headers)
+	Trailers      []*MetadataEntry       `protobuf:"bytes,3,rep,name=trailers,proto3" json:"trailers,omitempty"`                        // Trailing metadata
+	StatusCode    int32                  `protobuf:"varint,4,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"` // Status to return (0 = OK, no response message otherwise)
+	StatusMessage string                 `protobuf:"bytes,5,opt,name=status_message,json=statusMessage,proto3" json:"status_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EchoWithMetadataRequest) Reset() {
+	*x = EchoWithMetadataRequest{}
+	mi := &file_echo_metadata_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EchoWithMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EchoWithMetadataRequest) ProtoMessage() {}
+
+func (x *EchoWithMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_metadata_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EchoWithMetadataRequest.ProtoReflect.Descriptor instead.
+func (*EchoWithMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_echo_metadata_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EchoWithMetadataRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EchoWithMetadataRequest) GetHeaders() []*MetadataEntry {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *EchoWithMetadataRequest) GetTrailers() []*MetadataEntry {
+	if x != nil {
+		return x.Trailers
+	}
+	return nil
+}
+
+func (x *EchoWithMetadataRequest) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *EchoWithMetadataRequest) GetStatusMessage() string {
+	if x != nil {
+		return x.StatusMessage
+	}
+	return ""
+}
+
 var File_echo_metadata_proto protoreflect.FileDescriptor
 
 const file_echo_metadata_proto_rawDesc = "" +
@@ -228,7 +366,18 @@ const file_echo_metadata_proto_rawDesc = "" +
 	"\btrailers\x18\x02 \x03(\v2..echo.v1.EchoWithTrailersRequest.TrailersEntryR\btrailers\x1a;\n" +
 	"\rTrailersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B6Z4github.com/jsr-probitas/echo-servers/echo-grpc/protob\x06proto3"
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"Z\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12!\n" +
+	"\fbinary_value\x18\x03 \x01(\fR\vbinaryValue\"\xe1\x01\n" +
+	"\x17EchoWithMetadataRequest\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x120\n" +
+	"\aheaders\x18\x02 \x03(\v2\x16.echo.v1.MetadataEntryR\aheaders\x122\n" +
+	"\btrailers\x18\x03 \x03(\v2\x16.echo.v1.MetadataEntryR\btrailers\x12\x1f\n" +
+	"\vstatus_code\x18\x04 \x01(\x05R\n" +
+	"statusCode\x12%\n" +
+	"\x0estatus_message\x18\x05 \x01(\tR\rstatusMessageB6Z4github.com/jsr-probitas/echo-servers/echo-grpc/protob\x06proto3"
 
 var (
 	file_echo_metadata_proto_rawDescOnce sync.Once
@@ -242,24 +391,28 @@ func file_echo_metadata_proto_rawDescGZIP() []byte {
 	return file_echo_metadata_proto_rawDescData
 }
 
-var file_echo_metadata_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_echo_metadata_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_echo_metadata_proto_goTypes = []any{
 	(*MetadataValues)(nil),              // 0: echo.v1.MetadataValues
 	(*EchoRequestMetadataRequest)(nil),  // 1: echo.v1.EchoRequestMetadataRequest
 	(*EchoRequestMetadataResponse)(nil), // 2: echo.v1.EchoRequestMetadataResponse
 	(*EchoWithTrailersRequest)(nil),     // 3: echo.v1.EchoWithTrailersRequest
-	nil,                                 // 4: echo.v1.EchoRequestMetadataResponse.MetadataEntry
-	nil,                                 // 5: echo.v1.EchoWithTrailersRequest.TrailersEntry
+	(*MetadataEntry)(nil),               // 4: echo.v1.MetadataEntry
+	(*EchoWithMetadataRequest)(nil),     // 5: echo.v1.EchoWithMetadataRequest
+	nil,                                 // 6: echo.v1.EchoRequestMetadataResponse.MetadataEntry
+	nil,                                 // 7: echo.v1.EchoWithTrailersRequest.TrailersEntry
 }
 var file_echo_metadata_proto_depIdxs = []int32{
-	4, // 0: echo.v1.EchoRequestMetadataResponse.metadata:type_name -> echo.v1.EchoRequestMetadataResponse.MetadataEntry
-	5, // 1: echo.v1.EchoWithTrailersRequest.trailers:type_name -> echo.v1.EchoWithTrailersRequest.TrailersEntry
-	0, // 2: echo.v1.EchoRequestMetadataResponse.MetadataEntry.value:type_name -> echo.v1.MetadataValues
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	6, // 0: echo.v1.EchoRequestMetadataResponse.metadata:type_name -> echo.v1.EchoRequestMetadataResponse.MetadataEntry
+	7, // 1: echo.v1.EchoWithTrailersRequest.trailers:type_name -> echo.v1.EchoWithTrailersRequest.TrailersEntry
+	4, // 2: echo.v1.EchoWithMetadataRequest.headers:type_name -> echo.v1.MetadataEntry
+	4, // 3: echo.v1.EchoWithMetadataRequest.trailers:type_name -> echo.v1.MetadataEntry
+	0, // 4: echo.v1.EchoRequestMetadataResponse.MetadataEntry.value:type_name -> echo.v1.MetadataValues
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_echo_metadata_proto_init() }
@@ -273,7 +426,7 @@ func file_echo_metadata_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_echo_metadata_proto_rawDesc), len(file_echo_metadata_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
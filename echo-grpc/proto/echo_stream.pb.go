@@ -27,6 +27,7 @@ type ServerStreamRequest struct {
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`                             // Number of responses to stream
 	IntervalMs    int32                  `protobuf:"varint,3,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"` // Interval between responses
+	Failure       *StreamFailure         `protobuf:"bytes,4,opt,name=failure,proto3" json:"failure,omitempty"`                          // End the stream early
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,16 +83,108 @@ func (x *ServerStreamRequest) GetIntervalMs() int32 {
 	return 0
 }
 
+func (x *ServerStreamRequest) GetFailure() *StreamFailure {
+	if x != nil {
+		return x.Failure
+	}
+	return nil
+}
+
+// StreamFailure - End a stream early with an error status or RST_STREAM
+type StreamFailure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	After         int32                  `protobuf:"varint,1,opt,name=after,proto3" json:"after,omitempty"`                          // Messages to send (ClientStream: receive) before failing
+	Code          int32                  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`                            // gRPC status code (0 = end with OK)
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                       // Status message
+	Trailers      []*MetadataEntry       `protobuf:"bytes,4,rep,name=trailers,proto3" json:"trailers,omitempty"`                     // Trailers sent with the status
+	RstStream     bool                   `protobuf:"varint,5,opt,name=rst_stream,json=rstStream,proto3" json:"rst_stream,omitempty"` // Abort the HTTP/2 stream with RST_STREAM instead of a status
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamFailure) Reset() {
+	*x = StreamFailure{}
+	mi := &file_echo_stream_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamFailure) ProtoMessage() {}
+
+func (x *StreamFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_stream_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamFailure.ProtoReflect.Descriptor instead.
+func (*StreamFailure) Descriptor() ([]byte, []int) {
+	return file_echo_stream_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StreamFailure) GetAfter() int32 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+func (x *StreamFailure) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *StreamFailure) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *StreamFailure) GetTrailers() []*MetadataEntry {
+	if x != nil {
+		return x.Trailers
+	}
+	return nil
+}
+
+func (x *StreamFailure) GetRstStream() bool {
+	if x != nil {
+		return x.RstStream
+	}
+	return false
+}
+
 var File_echo_stream_proto protoreflect.FileDescriptor
 
 const file_echo_stream_proto_rawDesc = "" +
 	"\n" +
-	"\x11echo_stream.proto\x12\aecho.v1\"f\n" +
+	"\x11echo_stream.proto\x12\aecho.v1\x1a\x13echo_metadata.proto\"\x98\x01\n" +
 	"\x13ServerStreamRequest\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x14\n" +
 	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x1f\n" +
 	"\vinterval_ms\x18\x03 \x01(\x05R\n" +
-	"intervalMsB6Z4github.com/jsr-probitas/echo-servers/echo-grpc/protob\x06proto3"
+	"intervalMs\x120\n" +
+	"\afailure\x18\x04 \x01(\v2\x16.echo.v1.StreamFailureR\afailure\"\xa6\x01\n" +
+	"\rStreamFailure\x12\x14\n" +
+	"\x05after\x18\x01 \x01(\x05R\x05after\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x122\n" +
+	"\btrailers\x18\x04 \x03(\v2\x16.echo.v1.MetadataEntryR\btrailers\x12\x1d\n" +
+	"\n" +
+	"rst_stream\x18\x05 \x01(\bR\trstStreamB6Z4github.com/jsr-probitas/echo-servers/echo-grpc/protob\x06proto3"
 
 var (
 	file_echo_stream_proto_rawDescOnce sync.Once
@@ -105,16 +198,20 @@ func file_echo_stream_proto_rawDescGZIP() []byte {
 	return file_echo_stream_proto_rawDescData
 }
 
-var file_echo_stream_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_echo_stream_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_echo_stream_proto_goTypes = []any{
 	(*ServerStreamRequest)(nil), // 0: echo.v1.ServerStreamRequest
+	(*StreamFailure)(nil),       // 1: echo.v1.StreamFailure
+	(*MetadataEntry)(nil),       // 2: echo.v1.MetadataEntry
 }
 var file_echo_stream_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: echo.v1.ServerStreamRequest.failure:type_name -> echo.v1.StreamFailure
+	2, // 1: echo.v1.StreamFailure.trailers:type_name -> echo.v1.MetadataEntry
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_echo_stream_proto_init() }
@@ -122,13 +219,14 @@ func file_echo_stream_proto_init() {
 	if File_echo_stream_proto != nil {
 		return
 	}
+	file_echo_metadata_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_echo_stream_proto_rawDesc), len(file_echo_stream_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
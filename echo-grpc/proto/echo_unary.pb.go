@@ -25,6 +25,7 @@ const (
 type EchoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Failure       *StreamFailure         `protobuf:"bytes,2,opt,name=failure,proto3" json:"failure,omitempty"` // ClientStream/BidirectionalStream only, read from the first message
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +67,13 @@ func (x *EchoRequest) GetMessage() string {
 	return ""
 }
 
+func (x *EchoRequest) GetFailure() *StreamFailure {
+	if x != nil {
+		return x.Failure
+	}
+	return nil
+}
+
 type EchoWithDelayRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
@@ -123,6 +131,7 @@ type EchoErrorRequest struct {
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	Code          int32                  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`      // gRPC status code (0-16)
 	Details       string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"` // Error details message
+	Wire          *WireStatus            `protobuf:"bytes,4,opt,name=wire,proto3" json:"wire,omitempty"`       // How the status is sent on the wire
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -178,6 +187,13 @@ func (x *EchoErrorRequest) GetDetails() string {
 	return ""
 }
 
+func (x *EchoErrorRequest) GetWire() *WireStatus {
+	if x != nil {
+		return x.Wire
+	}
+	return nil
+}
+
 // EchoErrorWithDetails - Return error with rich error details (google.rpc.Status)
 type EchoErrorWithDetailsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -243,16 +259,18 @@ var File_echo_unary_proto protoreflect.FileDescriptor
 
 const file_echo_unary_proto_rawDesc = "" +
 	"\n" +
-	"\x10echo_unary.proto\x12\aecho.v1\x1a\x11echo_errors.proto\"'\n" +
+	"\x10echo_unary.proto\x12\aecho.v1\x1a\x11echo_errors.proto\x1a\x11echo_stream.proto\"Y\n" +
 	"\vEchoRequest\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"K\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x120\n" +
+	"\afailure\x18\x02 \x01(\v2\x16.echo.v1.StreamFailureR\afailure\"K\n" +
 	"\x14EchoWithDelayRequest\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x19\n" +
-	"\bdelay_ms\x18\x02 \x01(\x05R\adelayMs\"Z\n" +
+	"\bdelay_ms\x18\x02 \x01(\x05R\adelayMs\"\x83\x01\n" +
 	"\x10EchoErrorRequest\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
 	"\x04code\x18\x02 \x01(\x05R\x04code\x12\x18\n" +
-	"\adetails\x18\x03 \x01(\tR\adetails\"{\n" +
+	"\adetails\x18\x03 \x01(\tR\adetails\x12'\n" +
+	"\x04wire\x18\x04 \x01(\v2\x13.echo.v1.WireStatusR\x04wire\"{\n" +
 	"\x1bEchoErrorWithDetailsRequest\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12.\n" +
@@ -276,15 +294,19 @@ var file_echo_unary_proto_goTypes = []any{
 	(*EchoWithDelayRequest)(nil),        // 1: echo.v1.EchoWithDelayRequest
 	(*EchoErrorRequest)(nil),            // 2: echo.v1.EchoErrorRequest
 	(*EchoErrorWithDetailsRequest)(nil), // 3: echo.v1.EchoErrorWithDetailsRequest
-	(*ErrorDetail)(nil),                 // 4: echo.v1.ErrorDetail
+	(*StreamFailure)(nil),               // 4: echo.v1.StreamFailure
+	(*WireStatus)(nil),                  // 5: echo.v1.WireStatus
+	(*ErrorDetail)(nil),                 // 6: echo.v1.ErrorDetail
 }
 var file_echo_unary_proto_depIdxs = []int32{
-	4, // 0: echo.v1.EchoErrorWithDetailsRequest.details:type_name -> echo.v1.ErrorDetail
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4, // 0: echo.v1.EchoRequest.failure:type_name -> echo.v1.StreamFailure
+	5, // 1: echo.v1.EchoErrorRequest.wire:type_name -> echo.v1.WireStatus
+	6, // 2: echo.v1.EchoErrorWithDetailsRequest.details:type_name -> echo.v1.ErrorDetail
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_echo_unary_proto_init() }
@@ -293,6 +315,7 @@ func file_echo_unary_proto_init() {
 		return
 	}
 	file_echo_errors_proto_init()
+	file_echo_stream_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+// maxHealthStepDuration caps how long a single SetHealth step may last.
+const maxHealthStepDuration = time.Hour
+
+// AdminServer implements echo.v1.Admin for changing server behavior at runtime.
+type AdminServer struct {
+	pb.UnimplementedAdminServer
+	health *HealthServer
+	conns  *Connections
+}
+
+// NewAdminServer creates an Admin service that controls health and, unless
+// conns is nil, client connections.
+func NewAdminServer(health *HealthServer, conns *Connections) *AdminServer {
+	return &AdminServer{health: health, conns: conns}
+}
+
+func (s *AdminServer) SetHealth(_ context.Context, req *pb.SetHealthRequest) (*pb.SetHealthResponse, error) {
+	steps, err := healthSteps(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	previous, err := s.health.Schedule(req.Service, steps, req.Repeat)
+	if errors.Is(err, ErrHealthShutdown) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &pb.SetHealthResponse{
+		Service:        req.Service,
+		PreviousStatus: pb.ServingStatus(previous),
+		Status:         pb.ServingStatus(steps[0].Status),
+	}, nil
+}
+
+// healthSteps converts a SetHealth request into schedule steps. A request
+// without a schedule becomes a single step holding status.
+func healthSteps(req *pb.SetHealthRequest) ([]HealthStep, error) {
+	if len(req.Schedule) == 0 {
+		if req.Repeat {
+			return nil, errors.New("repeat requires a schedule")
+		}
+		if _, ok := pb.ServingStatus_name[int32(req.Status)]; !ok {
+			return nil, errors.New("unknown status")
+		}
+		return []HealthStep{{Status: healthpb.HealthCheckResponse_ServingStatus(req.Status)}}, nil
+	}
+
+	steps := make([]HealthStep, 0, len(req.Schedule))
+	for _, step := range req.Schedule {
+		if _, ok := pb.ServingStatus_name[int32(step.Status)]; !ok {
+			return nil, errors.New("unknown status in schedule")
+		}
+		duration := time.Duration(step.DurationMs) * time.Millisecond
+		if duration < 0 || duration > maxHealthStepDuration {
+			return nil, errors.New("duration_ms must be between 0 and 3600000")
+		}
+		// A zero-length step would make a repeating schedule spin
+		if req.Repeat && duration == 0 {
+			return nil, errors.New("duration_ms must be positive for every step of a repeating schedule")
+		}
+		steps = append(steps, HealthStep{
+			Status:   healthpb.HealthCheckResponse_ServingStatus(step.Status),
+			Duration: duration,
+		})
+	}
+	return steps, nil
+}
+
+// maxCloseDelay caps how long CloseConnections may wait before acting.
+const maxCloseDelay = time.Hour
+
+func (s *AdminServer) CloseConnections(_ context.Context, req *pb.CloseConnectionsRequest) (*pb.CloseConnectionsResponse, error) {
+	if s.conns == nil {
+		return nil, status.Error(codes.FailedPrecondition, "connection tracking is not enabled")
+	}
+	delay := time.Duration(req.DelayMs) * time.Millisecond
+	if delay < 0 || delay > maxCloseDelay {
+		return nil, status.Error(codes.InvalidArgument, "delay_ms must be between 0 and 3600000")
+	}
+
+	var act func()
+	switch req.Mode {
+	case pb.CloseMode_CLOSE_MODE_GOAWAY:
+		// Larger frames are a connection error (FRAME_SIZE_ERROR) for clients
+		if len(req.GoawayDebugData) > maxGoAwayDebugData {
+			return nil, status.Errorf(codes.InvalidArgument, "goaway_debug_data must not be longer than %d bytes", maxGoAwayDebugData)
+		}
+		act = func() { s.conns.GoAway(req.GoawayErrorCode, []byte(req.GoawayDebugData)) }
+	case pb.CloseMode_CLOSE_MODE_CLOSE:
+		act = func() { s.conns.Close() }
+	default:
+		return nil, status.Error(codes.InvalidArgument, "unknown mode")
+	}
+
+	resp := &pb.CloseConnectionsResponse{Connections: int32(s.conns.Len())}
+	if delay > 0 {
+		time.AfterFunc(delay, act)
+	} else {
+		act()
+	}
+	return resp, nil
+}
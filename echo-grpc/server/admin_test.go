@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+func setupAdminTestServer(t *testing.T) (pb.AdminClient, healthpb.HealthClient, *HealthServer, func()) {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	healthServer := NewHealthServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	pb.RegisterAdminServer(s, NewAdminServer(healthServer, nil))
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	cleanup := func() {
+		_ = conn.Close()
+		s.Stop()
+	}
+
+	return pb.NewAdminClient(conn), healthpb.NewHealthClient(conn), healthServer, cleanup
+}
+
+func TestAdmin_SetHealth(t *testing.T) {
+	admin, health, _, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	resp, err := admin.SetHealth(context.Background(), &pb.SetHealthRequest{
+		Service: "echo.v1.Echo",
+		Status:  pb.ServingStatus_SERVING_STATUS_NOT_SERVING,
+	})
+	if err != nil {
+		t.Fatalf("SetHealth failed: %v", err)
+	}
+	if resp.PreviousStatus != pb.ServingStatus_SERVING_STATUS_SERVING {
+		t.Errorf("expected previous status SERVING, got %v", resp.PreviousStatus)
+	}
+	if resp.Status != pb.ServingStatus_SERVING_STATUS_NOT_SERVING {
+		t.Errorf("expected status NOT_SERVING, got %v", resp.Status)
+	}
+
+	check, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "echo.v1.Echo"})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if check.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING, got %v", check.Status)
+	}
+}
+
+func TestAdmin_SetHealth_WatchSeesFlapping(t *testing.T) {
+	admin, health, _, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := health.Watch(ctx, &healthpb.HealthCheckRequest{Service: "flappy"})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	// Unknown services are reported as SERVICE_UNKNOWN until they are set
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if first.Status != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Fatalf("expected SERVICE_UNKNOWN, got %v", first.Status)
+	}
+
+	_, err = admin.SetHealth(ctx, &pb.SetHealthRequest{
+		Service: "flappy",
+		Schedule: []*pb.HealthStep{
+			{Status: pb.ServingStatus_SERVING_STATUS_SERVING, DurationMs: 20},
+			{Status: pb.ServingStatus_SERVING_STATUS_NOT_SERVING, DurationMs: 20},
+		},
+		Repeat: true,
+	})
+	if err != nil {
+		t.Fatalf("SetHealth failed: %v", err)
+	}
+
+	expected := []healthpb.HealthCheckResponse_ServingStatus{
+		healthpb.HealthCheckResponse_SERVING,
+		healthpb.HealthCheckResponse_NOT_SERVING,
+		healthpb.HealthCheckResponse_SERVING,
+		healthpb.HealthCheckResponse_NOT_SERVING,
+	}
+	for i, want := range expected {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv %d failed: %v", i, err)
+		}
+		if resp.Status != want {
+			t.Errorf("transition %d: expected %v, got %v", i, want, resp.Status)
+		}
+	}
+}
+
+func TestAdmin_SetHealth_InvalidArgument(t *testing.T) {
+	admin, _, _, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		req  *pb.SetHealthRequest
+	}{
+		{
+			name: "unknown status",
+			req:  &pb.SetHealthRequest{Status: 42},
+		},
+		{
+			name: "repeat without schedule",
+			req:  &pb.SetHealthRequest{Repeat: true},
+		},
+		{
+			name: "zero duration in repeating schedule",
+			req: &pb.SetHealthRequest{
+				Schedule: []*pb.HealthStep{{Status: pb.ServingStatus_SERVING_STATUS_SERVING}},
+				Repeat:   true,
+			},
+		},
+		{
+			name: "negative duration",
+			req: &pb.SetHealthRequest{
+				Schedule: []*pb.HealthStep{{Status: pb.ServingStatus_SERVING_STATUS_SERVING, DurationMs: -1}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := admin.SetHealth(context.Background(), tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAdmin_SetHealth_AfterShutdown(t *testing.T) {
+	admin, _, healthServer, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	healthServer.Shutdown()
+
+	_, err := admin.SetHealth(context.Background(), &pb.SetHealthRequest{
+		Status: pb.ServingStatus_SERVING_STATUS_SERVING,
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition, got %v", err)
+	}
+}
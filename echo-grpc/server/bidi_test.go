@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+// runBidi sends inputs with the given metadata, half-closes and returns all
+// messages received until the server ends the stream.
+func runBidi(t *testing.T, client pb.EchoClient, md metadata.MD, inputs []string) []string {
+	t.Helper()
+
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+	stream, err := client.BidirectionalStream(ctx)
+	if err != nil {
+		t.Fatalf("BidirectionalStream failed: %v", err)
+	}
+
+	for _, input := range inputs {
+		if err := stream.Send(&pb.EchoRequest{Message: input}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend failed: %v", err)
+	}
+
+	var outputs []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return outputs
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		outputs = append(outputs, resp.Message)
+	}
+}
+
+func TestBidirectionalStream_Modes(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	inputs := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		expected []string
+	}{
+		{
+			name:     "echo",
+			expected: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:     "batch",
+			md:       metadata.Pairs(bidiBatchKey, "2"),
+			expected: []string{"a, b", "c, d", "e"},
+		},
+		{
+			name:     "repeat",
+			md:       metadata.Pairs(bidiRepeatKey, "2"),
+			expected: []string{"a [1/2]", "a [2/2]", "b [1/2]", "b [2/2]", "c [1/2]", "c [2/2]", "d [1/2]", "d [2/2]", "e [1/2]", "e [2/2]"},
+		},
+		{
+			name:     "reorder",
+			md:       metadata.Pairs(bidiReorderKey, "2"),
+			expected: []string{"b", "a", "d", "c", "e"},
+		},
+		{
+			name:     "after close",
+			md:       metadata.Pairs(bidiAfterCloseKey, "2"),
+			expected: []string{"a", "b", "c", "d", "e", "after close [1/2]", "after close [2/2]"},
+		},
+		{
+			name:     "batch and reorder",
+			md:       metadata.Pairs(bidiBatchKey, "2", bidiReorderKey, "3"),
+			expected: []string{"e", "c, d", "a, b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs := runBidi(t, client, tt.md, inputs)
+			if !slices.Equal(outputs, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, outputs)
+			}
+		})
+	}
+}
+
+func TestBidirectionalStream_Delay(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	start := time.Now()
+	outputs := runBidi(t, client, metadata.Pairs(bidiDelayKey, "50"), []string{"a", "b"})
+
+	if len(outputs) != 2 {
+		t.Fatalf("expected 2 outputs, got %q", outputs)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected replies to be delayed by 50ms each, took %s", elapsed)
+	}
+}
+
+func TestBidirectionalStream_InvalidMode(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	tests := []metadata.MD{
+		metadata.Pairs(bidiBatchKey, "0"),
+		metadata.Pairs(bidiRepeatKey, "abc"),
+		metadata.Pairs(bidiReorderKey, "1001"),
+		metadata.Pairs(bidiDelayKey, "-1"),
+		metadata.Pairs(bidiAfterCloseKey, "-1"),
+	}
+
+	for _, md := range tests {
+		ctx := metadata.NewOutgoingContext(context.Background(), md)
+		stream, err := client.BidirectionalStream(ctx)
+		if err != nil {
+			t.Fatalf("BidirectionalStream failed: %v", err)
+		}
+		if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: expected InvalidArgument, got %v", md, err)
+		}
+	}
+}
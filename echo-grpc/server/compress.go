@@ -0,0 +1,129 @@
+package server
+
+import (
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // Registers the gzip compressor
+	"google.golang.org/grpc/stats"
+)
+
+// maxZstdWindow caps the memory a zstd request message may make the server
+// allocate for its decoding window.
+const maxZstdWindow = 64 * 1024 * 1024
+
+// Compressors lists the message compressors the server can decode and
+// encode, in addition to identity.
+var Compressors = []string{"gzip", "deflate", "zstd", "snappy"}
+
+func init() {
+	encoding.RegisterCompressor(deflateCompressor{})
+	encoding.RegisterCompressor(zstdCompressor{})
+	encoding.RegisterCompressor(snappyCompressor{})
+}
+
+// deflateCompressor implements the "deflate" encoding, which like HTTP
+// Content-Encoding means the zlib format.
+type deflateCompressor struct{}
+
+func (deflateCompressor) Name() string { return "deflate" }
+
+func (deflateCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return zlib.NewWriter(w), nil
+}
+
+func (deflateCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return zlib.NewReader(r)
+}
+
+type zstdCompressor struct{}
+
+func (zstdCompressor) Name() string { return "zstd" }
+
+func (zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+}
+
+func (zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	// A single-threaded decoder starts no goroutines, so it needs no Close
+	return zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxZstdWindow))
+}
+
+// snappyCompressor implements the "snappy" encoding with the framed Snappy
+// stream format.
+type snappyCompressor struct{}
+
+func (snappyCompressor) Name() string { return "snappy" }
+
+func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return s2.NewWriter(w, s2.WriterSnappyCompat(), s2.WriterConcurrency(1)), nil
+}
+
+func (snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return s2.NewReader(r), nil
+}
+
+// setResponseCompressor selects the compressor for the response messages of
+// the call. The client must have advertised it in grpc-accept-encoding.
+func setResponseCompressor(ctx context.Context, name string) error {
+	if name != encoding.Identity && !slices.Contains(Compressors, name) {
+		return fmt.Errorf("unknown compressor %q", name)
+	}
+	return grpc.SetSendCompressor(ctx, name)
+}
+
+type requestCompressorKey struct{}
+
+// compressionStats records the grpc-encoding of each call's request, which
+// grpc-go keeps out of the incoming metadata.
+type compressionStats struct{}
+
+// CompressionStats returns a grpc stats.Handler that lets EchoCompression
+// report the request's compressor. Install it with grpc.StatsHandler.
+func CompressionStats() stats.Handler {
+	return compressionStats{}
+}
+
+func (compressionStats) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, requestCompressorKey{}, new(string))
+}
+
+func (compressionStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InHeader); ok {
+		if name, ok := ctx.Value(requestCompressorKey{}).(*string); ok {
+			*name = messageEncoding(in.Compression)
+		}
+	}
+}
+
+func (compressionStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (compressionStats) HandleConn(context.Context, stats.ConnStats) {}
+
+// requestCompressor reports the compressor of the call's request messages,
+// as recorded by CompressionStats.
+func requestCompressor(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(requestCompressorKey{}).(*string)
+	if !ok {
+		return "", false
+	}
+	return *name, true
+}
+
+// messageEncoding reports an encoding name, using identity for uncompressed
+// messages.
+func messageEncoding(name string) string {
+	if name == "" {
+		return encoding.Identity
+	}
+	return name
+}
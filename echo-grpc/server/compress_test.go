@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+func TestEchoCompression_RequestEncodings(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	message := strings.Repeat("compressible ", 1000)
+
+	for _, name := range append([]string{"identity"}, Compressors...) {
+		t.Run(name, func(t *testing.T) {
+			var opts []grpc.CallOption
+			if name != "identity" {
+				opts = append(opts, grpc.UseCompressor(name))
+			}
+
+			resp, err := client.EchoCompression(context.Background(), &pb.EchoCompressionRequest{Message: message}, opts...)
+			if err != nil {
+				t.Fatalf("EchoCompression failed: %v", err)
+			}
+			if resp.Message != message {
+				t.Error("expected message to round-trip")
+			}
+			if resp.RequestEncoding != name {
+				t.Errorf("expected request encoding %q, got %q", name, resp.RequestEncoding)
+			}
+			// The response uses the request's compressor by default
+			if resp.ResponseEncoding != name {
+				t.Errorf("expected response encoding %q, got %q", name, resp.ResponseEncoding)
+			}
+			for _, c := range Compressors {
+				if !slices.Contains(resp.AcceptEncodings, c) {
+					t.Errorf("expected %q in accept encodings %v", c, resp.AcceptEncodings)
+				}
+			}
+			if !slices.Equal(resp.SupportedEncodings, Compressors) {
+				t.Errorf("expected supported encodings %v, got %v", Compressors, resp.SupportedEncodings)
+			}
+		})
+	}
+}
+
+func TestEchoCompression_ResponseCompressor(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	tests := []struct {
+		name     string
+		useGzip  bool
+		req      *pb.EchoCompressionRequest
+		expected string
+	}{
+		{
+			name:     "compress uncompressed request",
+			req:      &pb.EchoCompressionRequest{ResponseCompressor: "zstd"},
+			expected: "zstd",
+		},
+		{
+			name:     "different compressor than request",
+			useGzip:  true,
+			req:      &pb.EchoCompressionRequest{ResponseCompressor: "snappy"},
+			expected: "snappy",
+		},
+		{
+			name:     "identity for compressed request",
+			useGzip:  true,
+			req:      &pb.EchoCompressionRequest{ResponseCompressor: "identity"},
+			expected: "identity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []grpc.CallOption
+			if tt.useGzip {
+				opts = append(opts, grpc.UseCompressor("gzip"))
+			}
+
+			tt.req.Message = "hello"
+			resp, err := client.EchoCompression(context.Background(), tt.req, opts...)
+			if err != nil {
+				t.Fatalf("EchoCompression failed: %v", err)
+			}
+			if resp.Message != "hello" {
+				t.Errorf("expected message 'hello', got %q", resp.Message)
+			}
+			if resp.ResponseEncoding != tt.expected {
+				t.Errorf("expected response encoding %q, got %q", tt.expected, resp.ResponseEncoding)
+			}
+		})
+	}
+}
+
+// TestEchoCompression_HTTPTransport checks that listeners with transport=http
+// report the request's compressor. grpc-go does not track
+// grpc-accept-encoding there, so only identity can be selected.
+func TestEchoCompression_HTTPTransport(t *testing.T) {
+	client := setupHTTPTestServer(t)
+
+	for _, tt := range []struct{ compressor, expected string }{{"", "gzip"}, {"identity", "identity"}} {
+		resp, err := client.EchoCompression(context.Background(), &pb.EchoCompressionRequest{
+			Message:            "hello",
+			ResponseCompressor: tt.compressor,
+		}, grpc.UseCompressor("gzip"))
+		if err != nil {
+			t.Fatalf("EchoCompression failed: %v", err)
+		}
+		if resp.RequestEncoding != "gzip" {
+			t.Errorf("expected request encoding gzip, got %q", resp.RequestEncoding)
+		}
+		if resp.ResponseEncoding != tt.expected {
+			t.Errorf("expected response encoding %q, got %q", tt.expected, resp.ResponseEncoding)
+		}
+	}
+
+	_, err := client.EchoCompression(context.Background(), &pb.EchoCompressionRequest{
+		Message:            "hello",
+		ResponseCompressor: "zstd",
+	}, grpc.UseCompressor("gzip"))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestEchoCompression_UnknownCompressor(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	_, err := client.EchoCompression(context.Background(), &pb.EchoCompressionRequest{
+		Message:            "hello",
+		ResponseCompressor: "brotli",
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// HTTP/2 framing constants used by trackedConn (RFC 9113, section 4.1 and
+// 6.8)
+const (
+	frameHeaderLen  = 9
+	frameTypeGoAway = 0x7
+	goAwayFixedLen  = 8     // last stream ID and error code
+	maxFrameLen     = 16384 // SETTINGS_MAX_FRAME_SIZE every peer accepts
+)
+
+// maxGoAwayDebugData is the most GOAWAY debug data that fits in a frame.
+const maxGoAwayDebugData = maxFrameLen - goAwayFixedLen
+
+// Connections tracks the server's client connections so that the Admin
+// service can send GOAWAY on them or close them. grpc-go offers neither short
+// of stopping the server. Only connections accepted by grpc-go's own
+// transport are tracked; listeners with transport=http are served by net/http
+// and are not covered.
+type Connections struct {
+	mu    sync.Mutex
+	conns map[*trackedConn]struct{}
+}
+
+// NewConnections creates an empty connection registry.
+func NewConnections() *Connections {
+	return &Connections{conns: make(map[*trackedConn]struct{})}
+}
+
+// Credentials wraps creds so that connections are tracked. Use insecure
+// credentials for plaintext servers.
+func (c *Connections) Credentials(creds credentials.TransportCredentials) credentials.TransportCredentials {
+	return &trackedCredentials{TransportCredentials: creds, conns: c}
+}
+
+// Len returns the number of open connections.
+func (c *Connections) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.conns)
+}
+
+// GoAway sends GOAWAY with code and debug data on every connection. The last
+// stream ID is the largest possible, so calls in flight, and any the client
+// starts before it reads the frame, are still served. debugData must not be
+// longer than maxGoAwayDebugData. It returns the number of connections.
+func (c *Connections) GoAway(code uint32, debugData []byte) int {
+	frame := appendFrameHeader(nil, goAwayFixedLen+len(debugData), frameTypeGoAway, 0, []byte{0, 0, 0, 0})
+	frame = binary.BigEndian.AppendUint32(frame, math.MaxInt32)
+	frame = binary.BigEndian.AppendUint32(frame, code)
+	frame = append(frame, debugData...)
+
+	conns := c.snapshot()
+	for _, conn := range conns {
+		conn.inject(frame)
+	}
+	return len(conns)
+}
+
+// Close closes every connection and returns their number.
+func (c *Connections) Close() int {
+	conns := c.snapshot()
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+	return len(conns)
+}
+
+func (c *Connections) snapshot() []*trackedConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conns := make([]*trackedConn, 0, len(c.conns))
+	for conn := range c.conns {
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+func (c *Connections) add(conn *trackedConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conns[conn] = struct{}{}
+}
+
+func (c *Connections) remove(conn *trackedConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.conns, conn)
+}
+
+type trackedCredentials struct {
+	credentials.TransportCredentials
+	conns *Connections
+}
+
+func (c *trackedCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, info, err := c.TransportCredentials.ServerHandshake(rawConn)
+	if err != nil {
+		return nil, nil, err
+	}
+	tracked := &trackedConn{Conn: conn, conns: c.conns}
+	c.conns.add(tracked)
+	return tracked, info, nil
+}
+
+func (c *trackedCredentials) Clone() credentials.TransportCredentials {
+	return &trackedCredentials{TransportCredentials: c.TransportCredentials.Clone(), conns: c.conns}
+}
+
+// trackedConn holds back incomplete frames so that frames can be injected
+// between the server's own.
+type trackedConn struct {
+	net.Conn
+	conns *Connections
+
+	mu      sync.Mutex
+	pending []byte // incomplete frame held back until the rest is written
+	started bool   // the server's SETTINGS frame, which must come first, was written
+	queued  []byte // frames injected before the server started writing
+}
+
+func (c *trackedConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := p
+	if len(c.pending) > 0 {
+		c.pending = append(c.pending, p...)
+		data = c.pending
+	}
+
+	complete := 0
+	for {
+		rest := data[complete:]
+		if len(rest) < frameHeaderLen {
+			break
+		}
+		n := frameHeaderLen + (int(rest[0])<<16 | int(rest[1])<<8 | int(rest[2]))
+		if len(rest) < n {
+			break
+		}
+		complete += n
+	}
+
+	var err error
+	if complete > 0 {
+		_, err = c.Conn.Write(data[:complete])
+		if err == nil && !c.started {
+			c.started = true
+			if len(c.queued) > 0 {
+				_, err = c.Conn.Write(c.queued)
+				c.queued = nil
+			}
+		}
+	}
+	c.pending = append(c.pending[:0], data[complete:]...)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// inject writes frame between two of the server's frames.
+func (c *trackedConn) inject(frame []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.started {
+		c.queued = append(c.queued, frame...)
+		return
+	}
+	_, _ = c.Conn.Write(frame)
+}
+
+func (c *trackedConn) Close() error {
+	c.conns.remove(c)
+	return c.Conn.Close()
+}
+
+func appendFrameHeader(out []byte, length int, frameType, flags byte, streamID []byte) []byte {
+	out = append(out, byte(length>>16), byte(length>>8), byte(length), frameType, flags)
+	return append(out, streamID...)
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+// setupConnectionsTestServer serves the Echo and Admin services with tracked
+// connections, as main does.
+func setupConnectionsTestServer(t *testing.T) (pb.EchoClient, pb.AdminClient, *Connections) {
+	t.Helper()
+
+	conns := NewConnections()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.Creds(conns.Credentials(insecure.NewCredentials())))
+	pb.RegisterEchoServer(s, NewEchoServer())
+	pb.RegisterAdminServer(s, NewAdminServer(NewHealthServer(), conns))
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		s.Stop()
+	})
+
+	return pb.NewEchoClient(conn), pb.NewAdminClient(conn), conns
+}
+
+// waitConnReplaced waits until old is closed and another connection is open.
+func waitConnReplaced(t *testing.T, conns *Connections, old *trackedConn) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		current := conns.snapshot()
+		if len(current) == 1 && current[0] != old {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("connection was not replaced, have %d connections", conns.Len())
+}
+
+func TestAdmin_CloseConnectionsGoAway(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.CloseConnectionsRequest
+	}{
+		{name: "no error", req: &pb.CloseConnectionsRequest{}},
+		{name: "too many pings", req: &pb.CloseConnectionsRequest{GoawayErrorCode: 11, GoawayDebugData: "too_many_pings"}},
+		{name: "largest debug data", req: &pb.CloseConnectionsRequest{GoawayDebugData: strings.Repeat("x", maxGoAwayDebugData)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			echo, admin, conns := setupConnectionsTestServer(t)
+
+			// A call in flight survives GOAWAY
+			stream, err := echo.ServerStream(context.Background(), &pb.ServerStreamRequest{Message: "hello", Count: 3, IntervalMs: 100})
+			if err != nil {
+				t.Fatalf("ServerStream failed: %v", err)
+			}
+			if _, err := stream.Recv(); err != nil {
+				t.Fatalf("Recv failed: %v", err)
+			}
+			old := conns.snapshot()[0]
+
+			resp, err := admin.CloseConnections(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("CloseConnections failed: %v", err)
+			}
+			if resp.Connections != 1 {
+				t.Errorf("expected 1 connection, got %d", resp.Connections)
+			}
+
+			for i := 1; i < 3; i++ {
+				if _, err := stream.Recv(); err != nil {
+					t.Fatalf("Recv %d after GOAWAY failed: %v", i, err)
+				}
+			}
+
+			// New calls go to a new connection
+			if _, err := echo.Echo(context.Background(), &pb.EchoRequest{Message: "hello"}); err != nil {
+				t.Fatalf("Echo after GOAWAY failed: %v", err)
+			}
+			waitConnReplaced(t, conns, old)
+		})
+	}
+}
+
+func TestAdmin_CloseConnectionsClose(t *testing.T) {
+	echo, admin, conns := setupConnectionsTestServer(t)
+
+	stream, err := echo.ServerStream(context.Background(), &pb.ServerStreamRequest{Message: "hello", Count: 3, IntervalMs: 200})
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	old := conns.snapshot()[0]
+
+	// Delay so that the response reaches the caller before the close
+	resp, err := admin.CloseConnections(context.Background(), &pb.CloseConnectionsRequest{
+		Mode:    pb.CloseMode_CLOSE_MODE_CLOSE,
+		DelayMs: 50,
+	})
+	if err != nil {
+		t.Fatalf("CloseConnections failed: %v", err)
+	}
+	if resp.Connections != 1 {
+		t.Errorf("expected 1 connection, got %d", resp.Connections)
+	}
+
+	// The call in flight fails
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if status.Code(err) != codes.Unavailable {
+				t.Errorf("expected Unavailable, got %v", err)
+			}
+			break
+		}
+	}
+
+	if _, err := echo.Echo(context.Background(), &pb.EchoRequest{Message: "hello"}, grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("Echo after close failed: %v", err)
+	}
+	waitConnReplaced(t, conns, old)
+}
+
+func TestAdmin_CloseConnectionsErrors(t *testing.T) {
+	_, admin, _ := setupConnectionsTestServer(t)
+
+	tests := []struct {
+		name string
+		req  *pb.CloseConnectionsRequest
+	}{
+		{name: "negative delay", req: &pb.CloseConnectionsRequest{DelayMs: -1}},
+		{name: "delay too long", req: &pb.CloseConnectionsRequest{DelayMs: 3600001}},
+		{name: "unknown mode", req: &pb.CloseConnectionsRequest{Mode: 42}},
+		{name: "debug data too long", req: &pb.CloseConnectionsRequest{GoawayDebugData: strings.Repeat("x", maxGoAwayDebugData+1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := admin.CloseConnections(context.Background(), tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAdmin_CloseConnectionsUntracked(t *testing.T) {
+	admin, _, _, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	_, err := admin.CloseConnections(context.Background(), &pb.CloseConnectionsRequest{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// LoadDescriptors reads FileDescriptorSet files and compiles .proto files,
+// whose names are relative to importPaths, into a registry holding them and
+// their imports. Imports that are compiled into the server, such as the
+// well-known types, are used from the global registry instead.
+func LoadDescriptors(descriptorSets, protoFiles, importPaths []string) (*protoregistry.Files, error) {
+	l := &descriptorLoader{
+		protos: make(map[string]*descriptorpb.FileDescriptorProto),
+		files:  new(protoregistry.Files),
+	}
+
+	var names []string
+	for _, path := range descriptorSets {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("%s: invalid descriptor set: %w", path, err)
+		}
+		for _, fdp := range set.File {
+			l.protos[fdp.GetName()] = fdp
+			names = append(names, fdp.GetName())
+		}
+	}
+
+	if len(protoFiles) > 0 {
+		compiler := protocompile.Compiler{
+			Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{ImportPaths: importPaths}),
+		}
+		compiled, err := compiler.Compile(context.Background(), protoFiles...)
+		if err != nil {
+			return nil, err
+		}
+		for _, fd := range compiled {
+			l.addCompiled(fd)
+			names = append(names, fd.Path())
+		}
+	}
+
+	for _, name := range names {
+		if _, err := l.build(name); err != nil {
+			return nil, err
+		}
+	}
+	return l.files, nil
+}
+
+type descriptorLoader struct {
+	protos map[string]*descriptorpb.FileDescriptorProto
+	files  *protoregistry.Files
+}
+
+// addCompiled keeps a compiled file and its imports as descriptor protos, so
+// they are built the same way as descriptor set entries.
+func (l *descriptorLoader) addCompiled(fd protoreflect.FileDescriptor) {
+	if _, ok := l.protos[fd.Path()]; ok {
+		return
+	}
+	l.protos[fd.Path()] = protodesc.ToFileDescriptorProto(fd)
+	for i := 0; i < fd.Imports().Len(); i++ {
+		l.addCompiled(fd.Imports().Get(i).FileDescriptor)
+	}
+}
+
+// build creates the file descriptor for name after its imports.
+func (l *descriptorLoader) build(name string) (protoreflect.FileDescriptor, error) {
+	if fd, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+		return fd, nil
+	}
+	if fd, err := l.files.FindFileByPath(name); err == nil {
+		return fd, nil
+	}
+
+	fdp, ok := l.protos[name]
+	if !ok {
+		return nil, fmt.Errorf("missing import %q", name)
+	}
+	for _, dep := range fdp.Dependency {
+		if _, err := l.build(dep); err != nil {
+			return nil, err
+		}
+	}
+
+	fd, err := protodesc.NewFile(fdp, combinedResolver{protoregistry.GlobalFiles, l.files})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := l.files.RegisterFile(fd); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return fd, nil
+}
+
+// combinedResolver looks descriptors up in each resolver in turn.
+type combinedResolver []protodesc.Resolver
+
+func (r combinedResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	for _, resolver := range r {
+		if fd, err := resolver.FindFileByPath(path); err == nil {
+			return fd, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+func (r combinedResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	for _, resolver := range r {
+		if d, err := resolver.FindDescriptorByName(name); err == nil {
+			return d, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+// RegisterDynamicServices serves every service defined in files on s and
+// returns their names. A service that is already registered, such as
+// echo.v1.Echo, is an error.
+func RegisterDynamicServices(s *grpc.Server, files *protoregistry.Files) ([]string, error) {
+	registered := s.GetServiceInfo()
+
+	var descs []*grpc.ServiceDesc
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		for i := 0; i < fd.Services().Len(); i++ {
+			descs = append(descs, dynamicServiceDesc(fd, fd.Services().Get(i)))
+		}
+		return true
+	})
+
+	names := make([]string, 0, len(descs))
+	for _, desc := range descs {
+		if _, ok := registered[desc.ServiceName]; ok {
+			return nil, fmt.Errorf("service %s is already registered", desc.ServiceName)
+		}
+		names = append(names, desc.ServiceName)
+	}
+	sort.Strings(names)
+
+	for _, desc := range descs {
+		s.RegisterService(desc, nil)
+	}
+	return names, nil
+}
+
+// dynamicServiceDesc describes every method as a stream; unary calls look
+// the same on the wire.
+func dynamicServiceDesc(fd protoreflect.FileDescriptor, sd protoreflect.ServiceDescriptor) *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: string(sd.FullName()),
+		Metadata:    fd.Path(),
+	}
+	for i := 0; i < sd.Methods().Len(); i++ {
+		method := sd.Methods().Get(i)
+		desc.Streams = append(desc.Streams, grpc.StreamDesc{
+			StreamName:    string(method.Name()),
+			Handler:       dynamicHandler(method),
+			ServerStreams: method.IsStreamingServer(),
+			ClientStreams: method.IsStreamingClient(),
+		})
+	}
+	return desc
+}
+
+// dynamicHandler answers each request of unary and bidirectional methods,
+// the single request of server streaming methods once, and client
+// streaming methods once with the last request.
+func dynamicHandler(method protoreflect.MethodDescriptor) grpc.StreamHandler {
+	return func(_ any, stream grpc.ServerStream) error {
+		if !method.IsStreamingClient() {
+			req := dynamicpb.NewMessage(method.Input())
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			return stream.SendMsg(dynamicResponse(method, req))
+		}
+
+		last := dynamicpb.NewMessage(method.Input())
+		for {
+			req := dynamicpb.NewMessage(method.Input())
+			err := stream.RecvMsg(req)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			if method.IsStreamingServer() {
+				if err := stream.SendMsg(dynamicResponse(method, req)); err != nil {
+					return err
+				}
+				continue
+			}
+			last = req
+		}
+
+		if method.IsStreamingServer() {
+			return nil
+		}
+		return stream.SendMsg(dynamicResponse(method, last))
+	}
+}
+
+// dynamicResponse echoes req when the method's input and output types match.
+// Otherwise it returns a default output message with the request fields of
+// the same name and type copied over.
+func dynamicResponse(method protoreflect.MethodDescriptor, req *dynamicpb.Message) proto.Message {
+	if method.Output().FullName() == method.Input().FullName() {
+		return req
+	}
+
+	resp := dynamicpb.NewMessage(method.Output())
+	fields := method.Output().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		out := fields.Get(i)
+		in := method.Input().Fields().ByName(out.Name())
+		if in == nil || !sameFieldType(in, out) || !req.Has(in) {
+			continue
+		}
+
+		// Lists and maps belong to their field, so copy their elements
+		switch {
+		case out.IsList():
+			src, dst := req.Get(in).List(), resp.Mutable(out).List()
+			for j := 0; j < src.Len(); j++ {
+				dst.Append(src.Get(j))
+			}
+		case out.IsMap():
+			dst := resp.Mutable(out).Map()
+			req.Get(in).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				dst.Set(k, v)
+				return true
+			})
+		default:
+			resp.Set(out, req.Get(in))
+		}
+	}
+	return resp
+}
+
+func sameFieldType(a, b protoreflect.FieldDescriptor) bool {
+	if a.Kind() != b.Kind() || a.Cardinality() != b.Cardinality() || a.IsMap() != b.IsMap() {
+		return false
+	}
+	switch {
+	case a.IsMap():
+		// Map entry messages are named after their field, so compare contents
+		return sameFieldType(a.MapKey(), b.MapKey()) && sameFieldType(a.MapValue(), b.MapValue())
+	case a.Message() != nil:
+		return a.Message().FullName() == b.Message().FullName()
+	case a.Enum() != nil:
+		return a.Enum().FullName() == b.Enum().FullName()
+	}
+	return true
+}
@@ -0,0 +1,397 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+const greeterProto = `syntax = "proto3";
+
+package greeter.v1;
+
+import "google/protobuf/timestamp.proto";
+
+service Greeter {
+  rpc Same(HelloRequest) returns (HelloRequest);
+  rpc Hello(HelloRequest) returns (HelloReply);
+  rpc Watch(HelloRequest) returns (stream HelloReply);
+  rpc Collect(stream HelloRequest) returns (HelloReply);
+  rpc Chat(stream HelloRequest) returns (stream HelloReply);
+}
+
+message HelloRequest {
+  string name = 1;
+  repeated string tags = 2;
+  map<string, int32> counts = 3;
+  google.protobuf.Timestamp sent_at = 4;
+  int64 id = 5;
+}
+
+message HelloReply {
+  string name = 1;
+  repeated string tags = 2;
+  map<string, int32> counts = 3;
+  google.protobuf.Timestamp sent_at = 4;
+  string id = 5;
+  string greeting = 6;
+}
+`
+
+// writeGreeterProto writes greeter.proto to a temporary import path.
+func writeGreeterProto(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "greeter.proto"), []byte(greeterProto), 0o600); err != nil {
+		t.Fatalf("failed to write proto: %v", err)
+	}
+	return dir
+}
+
+func setupDynamicTestServer(t *testing.T, files *protoregistry.Files, opts ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(opts...)
+	pb.RegisterEchoServer(s, NewEchoServer())
+	if _, err := RegisterDynamicServices(s, files); err != nil {
+		t.Fatalf("RegisterDynamicServices failed: %v", err)
+	}
+	RegisterReflection(s, files, false, false, false)
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		s.Stop()
+	})
+	return conn
+}
+
+func loadGreeter(t *testing.T) *protoregistry.Files {
+	t.Helper()
+
+	files, err := LoadDescriptors(nil, []string{"greeter.proto"}, []string{writeGreeterProto(t)})
+	if err != nil {
+		t.Fatalf("LoadDescriptors failed: %v", err)
+	}
+	return files
+}
+
+func greeterMessage(t *testing.T, files *protoregistry.Files, name string) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	d, err := files.FindDescriptorByName(protoreflect.FullName("greeter.v1." + name))
+	if err != nil {
+		t.Fatalf("message %s not found: %v", name, err)
+	}
+	return d.(protoreflect.MessageDescriptor)
+}
+
+func newHelloRequest(t *testing.T, files *protoregistry.Files, name string) *dynamicpb.Message {
+	t.Helper()
+
+	md := greeterMessage(t, files, "HelloRequest")
+	req := dynamicpb.NewMessage(md)
+	req.Set(md.Fields().ByName("name"), protoreflect.ValueOfString(name))
+	req.Set(md.Fields().ByName("id"), protoreflect.ValueOfInt64(42))
+
+	tags := req.Mutable(md.Fields().ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("a"))
+	tags.Append(protoreflect.ValueOfString("b"))
+
+	counts := req.Mutable(md.Fields().ByName("counts")).Map()
+	counts.Set(protoreflect.ValueOfString("x").MapKey(), protoreflect.ValueOfInt32(1))
+
+	sentAt := req.Mutable(md.Fields().ByName("sent_at")).Message()
+	sentAt.Set(sentAt.Descriptor().Fields().ByName("seconds"), protoreflect.ValueOfInt64(100))
+	return req
+}
+
+func TestLoadDescriptors_ProtoFiles(t *testing.T) {
+	files := loadGreeter(t)
+
+	if _, err := files.FindFileByPath("greeter.proto"); err != nil {
+		t.Errorf("expected greeter.proto to be loaded: %v", err)
+	}
+	// Well-known imports come from the global registry
+	if _, err := files.FindFileByPath("google/protobuf/timestamp.proto"); err == nil {
+		t.Error("expected timestamp.proto to be shared with the global registry")
+	}
+}
+
+func TestLoadDescriptors_DescriptorSet(t *testing.T) {
+	fd, err := loadGreeter(t).FindFileByPath("greeter.proto")
+	if err != nil {
+		t.Fatalf("greeter.proto not found: %v", err)
+	}
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(fd)}}
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to marshal descriptor set: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "greeter.binpb")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write descriptor set: %v", err)
+	}
+
+	files, err := LoadDescriptors([]string{path}, nil, nil)
+	if err != nil {
+		t.Fatalf("LoadDescriptors failed: %v", err)
+	}
+	if _, err := files.FindDescriptorByName("greeter.v1.Greeter"); err != nil {
+		t.Errorf("expected Greeter service: %v", err)
+	}
+}
+
+func TestLoadDescriptors_Errors(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid.binpb")
+	if err := os.WriteFile(invalid, []byte("not a descriptor set"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	missingImport := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:       proto.String("a.proto"),
+		Dependency: []string{"missing.proto"},
+	}}}
+	data, _ := proto.Marshal(missingImport)
+	incomplete := filepath.Join(t.TempDir(), "incomplete.binpb")
+	if err := os.WriteFile(incomplete, data, 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		sets        []string
+		protos      []string
+		importPaths []string
+	}{
+		{name: "missing descriptor set", sets: []string{filepath.Join(t.TempDir(), "missing.binpb")}},
+		{name: "invalid descriptor set", sets: []string{invalid}},
+		{name: "missing import", sets: []string{incomplete}},
+		{name: "missing proto file", protos: []string{"missing.proto"}, importPaths: []string{t.TempDir()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadDescriptors(tt.sets, tt.protos, tt.importPaths); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestRegisterDynamicServices_AlreadyRegistered(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterEchoServer(s, NewEchoServer())
+
+	files := new(protoregistry.Files)
+	if err := files.RegisterFile(pb.File_echo_proto); err != nil {
+		t.Fatalf("failed to register file: %v", err)
+	}
+	if _, err := RegisterDynamicServices(s, files); err == nil {
+		t.Error("expected error for echo.v1.Echo")
+	}
+}
+
+func TestDynamic_Unary(t *testing.T) {
+	files := loadGreeter(t)
+	conn := setupDynamicTestServer(t, files)
+	reply := greeterMessage(t, files, "HelloReply")
+
+	t.Run("same type echoes request", func(t *testing.T) {
+		req := newHelloRequest(t, files, "alice")
+		resp := dynamicpb.NewMessage(req.Descriptor())
+		if err := conn.Invoke(context.Background(), "/greeter.v1.Greeter/Same", req, resp); err != nil {
+			t.Fatalf("Same failed: %v", err)
+		}
+		if !proto.Equal(req, resp) {
+			t.Errorf("expected %v, got %v", req, resp)
+		}
+	})
+
+	t.Run("different type copies matching fields", func(t *testing.T) {
+		resp := dynamicpb.NewMessage(reply)
+		if err := conn.Invoke(context.Background(), "/greeter.v1.Greeter/Hello", newHelloRequest(t, files, "alice"), resp); err != nil {
+			t.Fatalf("Hello failed: %v", err)
+		}
+
+		fields := reply.Fields()
+		if got := resp.Get(fields.ByName("name")).String(); got != "alice" {
+			t.Errorf("expected name 'alice', got %q", got)
+		}
+		if got := resp.Get(fields.ByName("tags")).List().Len(); got != 2 {
+			t.Errorf("expected 2 tags, got %d", got)
+		}
+		if got := resp.Get(fields.ByName("counts")).Map().Len(); got != 1 {
+			t.Errorf("expected 1 count, got %d", got)
+		}
+		sentAt := resp.Get(fields.ByName("sent_at")).Message()
+		if got := sentAt.Get(sentAt.Descriptor().Fields().ByName("seconds")).Int(); got != 100 {
+			t.Errorf("expected sent_at seconds 100, got %d", got)
+		}
+		// Fields whose type differs are left at their default
+		if resp.Has(fields.ByName("id")) || resp.Has(fields.ByName("greeting")) {
+			t.Errorf("expected id and greeting to be unset, got %v", resp)
+		}
+	})
+}
+
+func TestDynamic_Streaming(t *testing.T) {
+	files := loadGreeter(t)
+	conn := setupDynamicTestServer(t, files)
+	reply := greeterMessage(t, files, "HelloReply")
+	nameField := reply.Fields().ByName("name")
+
+	tests := []struct {
+		name     string
+		method   string
+		desc     grpc.StreamDesc
+		requests []string
+		expected []string
+	}{
+		{
+			name:     "server streaming",
+			method:   "Watch",
+			desc:     grpc.StreamDesc{ServerStreams: true},
+			requests: []string{"alice"},
+			expected: []string{"alice"},
+		},
+		{
+			name:     "client streaming",
+			method:   "Collect",
+			desc:     grpc.StreamDesc{ClientStreams: true},
+			requests: []string{"alice", "bob"},
+			expected: []string{"bob"},
+		},
+		{
+			name:     "bidirectional streaming",
+			method:   "Chat",
+			desc:     grpc.StreamDesc{ServerStreams: true, ClientStreams: true},
+			requests: []string{"alice", "bob"},
+			expected: []string{"alice", "bob"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := conn.NewStream(context.Background(), &tt.desc, "/greeter.v1.Greeter/"+tt.method)
+			if err != nil {
+				t.Fatalf("NewStream failed: %v", err)
+			}
+			for _, name := range tt.requests {
+				if err := stream.SendMsg(newHelloRequest(t, files, name)); err != nil {
+					t.Fatalf("SendMsg failed: %v", err)
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatalf("CloseSend failed: %v", err)
+			}
+
+			var got []string
+			for {
+				resp := dynamicpb.NewMessage(reply)
+				err := stream.RecvMsg(resp)
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("RecvMsg failed: %v", err)
+				}
+				got = append(got, resp.Get(nameField).String())
+			}
+
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected replies %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("expected replies %v, got %v", tt.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDynamic_Reflection(t *testing.T) {
+	conn := setupDynamicTestServer(t, loadGreeter(t))
+
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("ServerReflectionInfo failed: %v", err)
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	if err := stream.Send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	found := false
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		if svc.Name == "greeter.v1.Greeter" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected greeter.v1.Greeter in %v", resp.GetListServicesResponse())
+	}
+
+	if err := stream.Send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: "greeter.v1.Greeter",
+		},
+	}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	resp, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	files := resp.GetFileDescriptorResponse().GetFileDescriptorProto()
+	// Dependencies are omitted by default
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d: %v", len(files), resp)
+	}
+	var fdp descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(files[0], &fdp); err != nil {
+		t.Fatalf("failed to unmarshal descriptor: %v", err)
+	}
+	if fdp.GetName() != "greeter.proto" {
+		t.Errorf("expected greeter.proto, got %q", fdp.GetName())
+	}
+}
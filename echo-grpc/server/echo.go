@@ -13,6 +13,8 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 
 	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
@@ -25,10 +27,13 @@ const (
 
 type EchoServer struct {
 	pb.UnimplementedEchoServer
+	flaky *flakyTracker
 }
 
 func NewEchoServer() *EchoServer {
-	return &EchoServer{}
+	return &EchoServer{
+		flaky: newFlakyTracker(),
+	}
 }
 
 func (s *EchoServer) Echo(ctx context.Context, req *pb.EchoRequest) (*pb.EchoResponse, error) {
@@ -46,6 +51,10 @@ func (s *EchoServer) Echo(ctx context.Context, req *pb.EchoRequest) (*pb.EchoRes
 		_ = grpc.SetTrailer(ctx, md)
 	}
 
+	for k, v := range tlsMetadata(ctx) {
+		resp.Metadata[k] = v
+	}
+
 	return resp, nil
 }
 
@@ -72,12 +81,16 @@ func (s *EchoServer) EchoWithDelay(ctx context.Context, req *pb.EchoWithDelayReq
 		_ = grpc.SetTrailer(ctx, md)
 	}
 
+	for k, v := range tlsMetadata(ctx) {
+		resp.Metadata[k] = v
+	}
+
 	return resp, nil
 }
 
-func (s *EchoServer) EchoError(_ context.Context, req *pb.EchoErrorRequest) (*pb.EchoResponse, error) {
+func (s *EchoServer) EchoError(ctx context.Context, req *pb.EchoErrorRequest) (*pb.EchoResponse, error) {
 	code := codes.Code(req.Code)
-	if code > 16 {
+	if code > 16 && !req.Wire.GetRawCode() {
 		code = codes.Unknown
 	}
 
@@ -86,6 +99,13 @@ func (s *EchoServer) EchoError(_ context.Context, req *pb.EchoErrorRequest) (*pb
 		details = fmt.Sprintf("error with code %d: %s", req.Code, req.Message)
 	}
 
+	// Malformed statuses are written by NewHTTPHandler
+	if req.Wire != nil {
+		if err := setWireStatus(ctx, req.Wire); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, status.Error(code, details)
 }
 
@@ -131,6 +151,10 @@ func (s *EchoServer) EchoWithTrailers(ctx context.Context, req *pb.EchoWithTrail
 		}
 	}
 
+	for k, v := range tlsMetadata(ctx) {
+		resp.Metadata[k] = v
+	}
+
 	// Set specified trailers
 	if len(req.Trailers) > 0 {
 		trailerMD := metadata.New(nil)
@@ -143,6 +167,56 @@ func (s *EchoServer) EchoWithTrailers(ctx context.Context, req *pb.EchoWithTrail
 	return resp, nil
 }
 
+func (s *EchoServer) EchoWithMetadata(ctx context.Context, req *pb.EchoWithMetadataRequest) (*pb.EchoResponse, error) {
+	header, err := metadataFromEntries(req.Headers)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid headers: %v", err)
+	}
+	trailer, err := metadataFromEntries(req.Trailers)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid trailers: %v", err)
+	}
+
+	if len(header) > 0 {
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to set headers: %v", err)
+		}
+	}
+	if len(trailer) > 0 {
+		_ = grpc.SetTrailer(ctx, trailer)
+	}
+
+	// An error status ends the call without a response message: headers (if
+	// any) are followed directly by trailers, otherwise the response is
+	// Trailers-Only
+	if req.StatusCode != 0 {
+		code := codes.Code(req.StatusCode)
+		if code > 16 {
+			code = codes.Unknown
+		}
+		return nil, status.Error(code, req.StatusMessage)
+	}
+
+	resp := &pb.EchoResponse{
+		Message:  req.Message,
+		Metadata: make(map[string]string),
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for k, v := range md {
+			if len(v) > 0 {
+				resp.Metadata[k] = v[0]
+			}
+		}
+	}
+
+	for k, v := range tlsMetadata(ctx) {
+		resp.Metadata[k] = v
+	}
+
+	return resp, nil
+}
+
 func (s *EchoServer) EchoLargePayload(_ context.Context, req *pb.EchoLargePayloadRequest) (*pb.EchoLargePayloadResponse, error) {
 	size := int(req.SizeBytes)
 	if size <= 0 {
@@ -168,6 +242,34 @@ func (s *EchoServer) EchoLargePayload(_ context.Context, req *pb.EchoLargePayloa
 	}, nil
 }
 
+func (s *EchoServer) EchoCompression(ctx context.Context, req *pb.EchoCompressionRequest) (*pb.EchoCompressionResponse, error) {
+	requestEncoding, ok := requestCompressor(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "EchoCompression needs the server's CompressionStats handler")
+	}
+	if req.ResponseCompressor != "" {
+		if err := setResponseCompressor(ctx, req.ResponseCompressor); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	// grpc-go answers with the request's compressor unless told otherwise
+	resp := &pb.EchoCompressionResponse{
+		Message:            req.Message,
+		RequestEncoding:    requestEncoding,
+		ResponseEncoding:   messageEncoding(req.ResponseCompressor),
+		SupportedEncodings: Compressors,
+	}
+	if req.ResponseCompressor == "" {
+		resp.ResponseEncoding = requestEncoding
+	}
+	if accepted, err := grpc.ClientSupportedCompressors(ctx); err == nil {
+		resp.AcceptEncodings = accepted
+	}
+
+	return resp, nil
+}
+
 func (s *EchoServer) EchoDeadline(ctx context.Context, req *pb.EchoDeadlineRequest) (*pb.EchoDeadlineResponse, error) {
 	resp := &pb.EchoDeadlineResponse{
 		Message:     req.Message,
@@ -197,48 +299,108 @@ func (s *EchoServer) EchoErrorWithDetails(_ context.Context, req *pb.EchoErrorWi
 		message = fmt.Sprintf("error with code %d", req.Code)
 	}
 
-	st := status.New(code, message)
+	st := status.New(code, message).Proto()
 
 	// Add rich error details
 	for _, detail := range req.Details {
-		var err error
-		switch detail.Type {
-		case "bad_request":
-			br := &errdetails.BadRequest{}
-			for _, fv := range detail.FieldViolations {
-				br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
-					Field:       fv.Field,
-					Description: fv.Description,
-				})
-			}
-			st, err = st.WithDetails(br)
-		case "retry_info":
-			ri := &errdetails.RetryInfo{
-				RetryDelay: durationpb.New(time.Duration(detail.RetryDelayMs) * time.Millisecond),
-			}
-			st, err = st.WithDetails(ri)
-		case "debug_info":
-			di := &errdetails.DebugInfo{
-				StackEntries: detail.StackEntries,
-				Detail:       detail.DebugDetail,
-			}
-			st, err = st.WithDetails(di)
-		case "quota_failure":
-			qf := &errdetails.QuotaFailure{}
-			for _, qv := range detail.QuotaViolations {
-				qf.Violations = append(qf.Violations, &errdetails.QuotaFailure_Violation{
-					Subject:     qv.Subject,
-					Description: qv.Description,
-				})
-			}
-			st, err = st.WithDetails(qf)
-		}
+		msg, err := errorDetail(detail)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to attach error details: %v", err)
+			return nil, status.Error(codes.InvalidArgument, err.Error())
 		}
+		// Passthrough details are already an Any
+		detailAny, ok := msg.(*anypb.Any)
+		if !ok {
+			if detailAny, err = anypb.New(msg); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to attach error details: %v", err)
+			}
+		}
+		st.Details = append(st.Details, detailAny)
 	}
 
-	return nil, st.Err()
+	return nil, status.FromProto(st).Err()
+}
+
+// errorDetail builds the google.rpc error detail message of detail's type, or
+// returns its Any as is for the "any" type.
+func errorDetail(detail *pb.ErrorDetail) (proto.Message, error) {
+	switch detail.Type {
+	case "bad_request":
+		br := &errdetails.BadRequest{}
+		for _, fv := range detail.FieldViolations {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fv.Field,
+				Description: fv.Description,
+			})
+		}
+		return br, nil
+	case "retry_info":
+		return &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(time.Duration(detail.RetryDelayMs) * time.Millisecond),
+		}, nil
+	case "debug_info":
+		return &errdetails.DebugInfo{
+			StackEntries: detail.StackEntries,
+			Detail:       detail.DebugDetail,
+		}, nil
+	case "quota_failure":
+		qf := &errdetails.QuotaFailure{}
+		for _, qv := range detail.QuotaViolations {
+			qf.Violations = append(qf.Violations, &errdetails.QuotaFailure_Violation{
+				Subject:     qv.Subject,
+				Description: qv.Description,
+			})
+		}
+		return qf, nil
+	case "error_info":
+		return &errdetails.ErrorInfo{
+			Reason:   detail.Reason,
+			Domain:   detail.Domain,
+			Metadata: detail.Metadata,
+		}, nil
+	case "precondition_failure":
+		pf := &errdetails.PreconditionFailure{}
+		for _, pv := range detail.PreconditionViolations {
+			pf.Violations = append(pf.Violations, &errdetails.PreconditionFailure_Violation{
+				Type:        pv.Type,
+				Subject:     pv.Subject,
+				Description: pv.Description,
+			})
+		}
+		return pf, nil
+	case "resource_info":
+		return &errdetails.ResourceInfo{
+			ResourceType: detail.ResourceType,
+			ResourceName: detail.ResourceName,
+			Owner:        detail.Owner,
+			Description:  detail.Description,
+		}, nil
+	case "request_info":
+		return &errdetails.RequestInfo{
+			RequestId:   detail.RequestId,
+			ServingData: detail.ServingData,
+		}, nil
+	case "help":
+		help := &errdetails.Help{}
+		for _, link := range detail.Links {
+			help.Links = append(help.Links, &errdetails.Help_Link{
+				Description: link.Description,
+				Url:         link.Url,
+			})
+		}
+		return help, nil
+	case "localized_message":
+		return &errdetails.LocalizedMessage{
+			Locale:  detail.Locale,
+			Message: detail.LocalizedMessage,
+		}, nil
+	case "any":
+		if detail.Any == nil {
+			return nil, fmt.Errorf("error detail type %q requires any", detail.Type)
+		}
+		return detail.Any, nil
+	default:
+		return nil, fmt.Errorf("unknown error detail type %q", detail.Type)
+	}
 }
 
 func (s *EchoServer) ServerStream(req *pb.ServerStreamRequest, stream grpc.ServerStreamingServer[pb.EchoResponse]) error {
@@ -260,7 +422,15 @@ func (s *EchoServer) ServerStream(req *pb.ServerStreamRequest, stream grpc.Serve
 
 	interval := time.Duration(req.IntervalMs) * time.Millisecond
 
-	for i := int32(0); i < count; i++ {
+	if err := checkStreamFailure(stream, req.Failure); err != nil {
+		return err
+	}
+	sent := count
+	if req.Failure != nil && req.Failure.After < count {
+		sent = req.Failure.After
+	}
+
+	for i := int32(0); i < sent; i++ {
 		select {
 		case <-ctx.Done():
 			return status.Error(codes.Canceled, "stream canceled")
@@ -276,7 +446,7 @@ func (s *EchoServer) ServerStream(req *pb.ServerStreamRequest, stream grpc.Serve
 			return err
 		}
 
-		if i < count-1 && interval > 0 {
+		if i < sent-1 && interval > 0 {
 			select {
 			case <-time.After(interval):
 			case <-ctx.Done():
@@ -285,6 +455,10 @@ func (s *EchoServer) ServerStream(req *pb.ServerStreamRequest, stream grpc.Serve
 		}
 	}
 
+	if req.Failure != nil {
+		return failStream(stream, req.Failure)
+	}
+
 	return nil
 }
 
@@ -300,7 +474,10 @@ func (s *EchoServer) ClientStream(stream grpc.ClientStreamingServer[pb.EchoReque
 		}
 	}
 
-	var messages []string
+	var (
+		messages []string
+		failure  *pb.StreamFailure
+	)
 
 	for {
 		req, err := stream.Recv()
@@ -311,6 +488,24 @@ func (s *EchoServer) ClientStream(stream grpc.ClientStreamingServer[pb.EchoReque
 			return err
 		}
 		messages = append(messages, req.Message)
+
+		if len(messages) == 1 && req.Failure != nil {
+			if err := checkStreamFailure(stream, req.Failure); err != nil {
+				return err
+			}
+			failure = req.Failure
+		}
+		// Stop reading once the failure is due
+		if failure != nil && int32(len(messages)) >= failure.After {
+			break
+		}
+	}
+
+	// A failure with code 0 only adds trailers to the normal response
+	if failure != nil {
+		if err := failStream(stream, failure); err != nil {
+			return err
+		}
 	}
 
 	resp := &pb.EchoResponse{
@@ -333,7 +528,22 @@ func (s *EchoServer) BidirectionalStream(stream grpc.BidiStreamingServer[pb.Echo
 		}
 	}
 
-	for {
+	mode, err := parseBidiMode(func(key string) string {
+		if v := metadata.ValueFromIncomingContext(ctx, key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var (
+		failure *pb.StreamFailure
+		sent    int32
+	)
+
+	for received := 0; ; received++ {
 		select {
 		case <-ctx.Done():
 			return status.Error(codes.Canceled, "stream canceled")
@@ -341,20 +551,54 @@ func (s *EchoServer) BidirectionalStream(stream grpc.BidiStreamingServer[pb.Echo
 		}
 
 		req, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
-		if err != nil {
+		closed := err == io.EOF
+		if err != nil && !closed {
 			return err
 		}
 
-		resp := &pb.EchoResponse{
-			Message:  req.Message,
-			Metadata: md,
+		var outputs []string
+		if closed {
+			// Keep sending after the client's half-close
+			outputs = mode.flush()
+		} else {
+			if received == 0 && req.Failure != nil {
+				if err := checkStreamFailure(stream, req.Failure); err != nil {
+					return err
+				}
+				failure = req.Failure
+			}
+			outputs = mode.push(req.Message)
 		}
 
-		if err := stream.Send(resp); err != nil {
-			return err
+		for _, message := range outputs {
+			if failure != nil && sent >= failure.After {
+				return failStream(stream, failure)
+			}
+
+			if mode.delay > 0 {
+				select {
+				case <-time.After(mode.delay):
+				case <-ctx.Done():
+					return status.Error(codes.Canceled, "stream canceled")
+				}
+			}
+
+			resp := &pb.EchoResponse{
+				Message:  message,
+				Metadata: md,
+			}
+
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			sent++
+		}
+
+		if closed {
+			if failure != nil {
+				return failStream(stream, failure)
+			}
+			return nil
 		}
 	}
 }
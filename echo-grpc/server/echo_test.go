@@ -14,6 +14,8 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 
 	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
 )
@@ -22,7 +24,7 @@ func setupTestServer(t *testing.T) (pb.EchoClient, func()) {
 	t.Helper()
 
 	lis := bufconn.Listen(1024 * 1024)
-	s := grpc.NewServer()
+	s := grpc.NewServer(grpc.StatsHandler(CompressionStats()))
 	pb.RegisterEchoServer(s, NewEchoServer())
 
 	go func() {
@@ -333,6 +335,98 @@ func TestEchoWithTrailers_NoTrailers(t *testing.T) {
 	}
 }
 
+func TestEchoWithMetadata_SetsHeadersAndTrailers(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	var header, trailer metadata.MD
+	resp, err := client.EchoWithMetadata(context.Background(),
+		&pb.EchoWithMetadataRequest{
+			Message: "hello",
+			Headers: []*pb.MetadataEntry{
+				{Key: "x-dup", Value: "one"},
+				{Key: "x-dup", Value: "two"},
+				{Key: "x-data-bin", BinaryValue: []byte{0x00, 0xff}},
+			},
+			Trailers: []*pb.MetadataEntry{
+				{Key: "X-Trailer", Value: "trailer-value"},
+			},
+		},
+		grpc.Header(&header),
+		grpc.Trailer(&trailer),
+	)
+
+	if err != nil {
+		t.Fatalf("EchoWithMetadata failed: %v", err)
+	}
+	if resp.Message != "hello" {
+		t.Errorf("expected message %q, got %q", "hello", resp.Message)
+	}
+	if vals := header.Get("x-dup"); len(vals) != 2 || vals[0] != "one" || vals[1] != "two" {
+		t.Errorf("expected header x-dup=[one two], got %v", vals)
+	}
+	if vals := header.Get("x-data-bin"); len(vals) != 1 || vals[0] != "\x00\xff" {
+		t.Errorf("expected binary header x-data-bin, got %q", vals)
+	}
+	if vals := trailer.Get("x-trailer"); len(vals) != 1 || vals[0] != "trailer-value" {
+		t.Errorf("expected trailer x-trailer=trailer-value, got %v", vals)
+	}
+}
+
+func TestEchoWithMetadata_ErrorStatus(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	var header, trailer metadata.MD
+	_, err := client.EchoWithMetadata(context.Background(),
+		&pb.EchoWithMetadataRequest{
+			Headers:       []*pb.MetadataEntry{{Key: "x-header", Value: "header-value"}},
+			Trailers:      []*pb.MetadataEntry{{Key: "x-trailer", Value: "trailer-value"}},
+			StatusCode:    int32(codes.PermissionDenied),
+			StatusMessage: "denied",
+		},
+		grpc.Header(&header),
+		grpc.Trailer(&trailer),
+	)
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.PermissionDenied || st.Message() != "denied" {
+		t.Fatalf("expected PermissionDenied \"denied\", got %v", err)
+	}
+	if vals := header.Get("x-header"); len(vals) != 1 || vals[0] != "header-value" {
+		t.Errorf("expected header x-header=header-value, got %v", vals)
+	}
+	if vals := trailer.Get("x-trailer"); len(vals) != 1 || vals[0] != "trailer-value" {
+		t.Errorf("expected trailer x-trailer=trailer-value, got %v", vals)
+	}
+}
+
+func TestEchoWithMetadata_InvalidEntries(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	tests := []struct {
+		name  string
+		entry *pb.MetadataEntry
+	}{
+		{name: "empty key", entry: &pb.MetadataEntry{Value: "v"}},
+		{name: "reserved key", entry: &pb.MetadataEntry{Key: "grpc-status", Value: "0"}},
+		{name: "invalid character", entry: &pb.MetadataEntry{Key: "x:key", Value: "v"}},
+		{name: "binary value without -bin", entry: &pb.MetadataEntry{Key: "x-data", BinaryValue: []byte{0x01}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.EchoWithMetadata(context.Background(),
+				&pb.EchoWithMetadataRequest{Headers: []*pb.MetadataEntry{tt.entry}},
+			)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
 func TestEchoRequestMetadata_ReturnsAllMetadata(t *testing.T) {
 	client, cleanup := setupTestServer(t)
 	defer cleanup()
@@ -659,3 +753,125 @@ func TestEchoErrorWithDetails_QuotaFailure(t *testing.T) {
 		t.Errorf("expected subject %q, got %q", "user:123", qf.Violations[0].Subject)
 	}
 }
+
+func TestEchoErrorWithDetails_ExtendedTypes(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	tests := []struct {
+		name     string
+		detail   *pb.ErrorDetail
+		expected proto.Message
+	}{
+		{
+			name:   "error_info",
+			detail: &pb.ErrorDetail{Type: "error_info", Reason: "API_DISABLED", Domain: "example.com", Metadata: map[string]string{"service": "echo"}},
+			expected: &errdetails.ErrorInfo{
+				Reason:   "API_DISABLED",
+				Domain:   "example.com",
+				Metadata: map[string]string{"service": "echo"},
+			},
+		},
+		{
+			name: "precondition_failure",
+			detail: &pb.ErrorDetail{Type: "precondition_failure", PreconditionViolations: []*pb.PreconditionViolation{
+				{Type: "TOS", Subject: "user:123", Description: "terms not accepted"},
+			}},
+			expected: &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+				{Type: "TOS", Subject: "user:123", Description: "terms not accepted"},
+			}},
+		},
+		{
+			name:   "resource_info",
+			detail: &pb.ErrorDetail{Type: "resource_info", ResourceType: "book", ResourceName: "books/1", Owner: "user:123", Description: "not found"},
+			expected: &errdetails.ResourceInfo{
+				ResourceType: "book",
+				ResourceName: "books/1",
+				Owner:        "user:123",
+				Description:  "not found",
+			},
+		},
+		{
+			name:     "request_info",
+			detail:   &pb.ErrorDetail{Type: "request_info", RequestId: "req-1", ServingData: "stack"},
+			expected: &errdetails.RequestInfo{RequestId: "req-1", ServingData: "stack"},
+		},
+		{
+			name:     "help",
+			detail:   &pb.ErrorDetail{Type: "help", Links: []*pb.HelpLink{{Description: "docs", Url: "https://example.com"}}},
+			expected: &errdetails.Help{Links: []*errdetails.Help_Link{{Description: "docs", Url: "https://example.com"}}},
+		},
+		{
+			name:     "localized_message",
+			detail:   &pb.ErrorDetail{Type: "localized_message", Locale: "en-US", LocalizedMessage: "Not found"},
+			expected: &errdetails.LocalizedMessage{Locale: "en-US", Message: "Not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.EchoErrorWithDetails(context.Background(), &pb.EchoErrorWithDetailsRequest{
+				Code:    int32(codes.FailedPrecondition),
+				Details: []*pb.ErrorDetail{tt.detail},
+			})
+
+			st := status.Convert(err)
+			if st.Code() != codes.FailedPrecondition {
+				t.Fatalf("expected FailedPrecondition, got %v", err)
+			}
+			details := st.Details()
+			if len(details) != 1 {
+				t.Fatalf("expected 1 detail, got %d", len(details))
+			}
+			detail, ok := details[0].(proto.Message)
+			if !ok || !proto.Equal(detail, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, details[0])
+			}
+		})
+	}
+}
+
+func TestEchoErrorWithDetails_AnyPassthrough(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	custom := &pb.EchoRequest{Message: "custom detail"}
+	detailAny, err := anypb.New(custom)
+	if err != nil {
+		t.Fatalf("failed to create Any: %v", err)
+	}
+
+	_, err = client.EchoErrorWithDetails(context.Background(), &pb.EchoErrorWithDetailsRequest{
+		Code:    int32(codes.Aborted),
+		Details: []*pb.ErrorDetail{{Type: "any", Any: detailAny}},
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.Aborted {
+		t.Fatalf("expected Aborted, got %v", err)
+	}
+	// The Any is sent as is, not wrapped in another Any
+	if len(st.Proto().Details) != 1 || !proto.Equal(st.Proto().Details[0], detailAny) {
+		t.Errorf("expected detail %v, got %v", detailAny, st.Proto().Details)
+	}
+	if details := st.Details(); len(details) != 1 || !proto.Equal(details[0].(proto.Message), custom) {
+		t.Errorf("expected %v, got %v", custom, details)
+	}
+}
+
+func TestEchoErrorWithDetails_InvalidDetail(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	for _, detail := range []*pb.ErrorDetail{{Type: "unknown"}, {Type: "any"}} {
+		t.Run(detail.Type, func(t *testing.T) {
+			_, err := client.EchoErrorWithDetails(context.Background(), &pb.EchoErrorWithDetailsRequest{
+				Code:    int32(codes.NotFound),
+				Details: []*pb.ErrorDetail{detail},
+			})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+// checkStreamFailure validates f before the stream sends anything. Resets
+// need the stream to be served by NewHTTPHandler.
+func checkStreamFailure(stream grpc.ServerStream, f *pb.StreamFailure) error {
+	if f == nil {
+		return nil
+	}
+	if f.After < 0 {
+		return status.Error(codes.InvalidArgument, "failure.after must not be negative")
+	}
+	if _, err := metadataFromEntries(f.Trailers); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid failure trailers: %v", err)
+	}
+	if _, ok := httpCallFromContext(stream.Context()); f.RstStream && !ok {
+		return status.Error(codes.Unimplemented, "failure.rst_stream needs a listener with transport=http")
+	}
+	return nil
+}
+
+// failStream ends the stream as f describes, with RST_STREAM or with f's
+// status and trailers. A zero code ends the stream with OK.
+func failStream(stream grpc.ServerStream, f *pb.StreamFailure) error {
+	if f.RstStream {
+		// Already checked by checkStreamFailure. The status is replaced by
+		// RST_STREAM once it has been written.
+		call, _ := httpCallFromContext(stream.Context())
+		call.reset.Store(true)
+		return status.Error(codes.Internal, "stream reset requested")
+	}
+
+	// Already validated by checkStreamFailure
+	trailer, _ := metadataFromEntries(f.Trailers)
+	stream.SetTrailer(trailer)
+
+	code := codes.Code(f.Code)
+	if code > 16 {
+		code = codes.Unknown
+	}
+	return status.Error(code, f.Message)
+}
@@ -0,0 +1,302 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+// setupHTTPTestServer serves an Echo server with NewHTTPHandler over h2c,
+// as main does for listeners with transport=http.
+func setupHTTPTestServer(t *testing.T) pb.EchoClient {
+	t.Helper()
+
+	s := grpc.NewServer(grpc.StatsHandler(CompressionStats()))
+	pb.RegisterEchoServer(s, NewEchoServer())
+	server := httptest.NewServer(h2c.NewHandler(NewHTTPHandler(s), &http2.Server{}))
+
+	conn, err := grpc.NewClient("passthrough:///"+server.Listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		server.Close()
+	})
+
+	return pb.NewEchoClient(conn)
+}
+
+// isRSTStream reports whether err comes from the server resetting the stream.
+func isRSTStream(err error) bool {
+	return status.Code(err) == codes.Internal && strings.Contains(err.Error(), "RST_STREAM")
+}
+
+func TestServerStream_FailsAfterMessages(t *testing.T) {
+	client := setupHTTPTestServer(t)
+
+	stream, err := client.ServerStream(context.Background(), &pb.ServerStreamRequest{
+		Message: "ping",
+		Count:   5,
+		Failure: &pb.StreamFailure{
+			After:    2,
+			Code:     int32(codes.Unavailable),
+			Message:  "backend went away",
+			Trailers: []*pb.MetadataEntry{{Key: "x-failure", Value: "injected"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+
+	var received int
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+		received++
+	}
+
+	if received != 2 {
+		t.Errorf("expected 2 messages before the failure, got %d", received)
+	}
+	st, _ := status.FromError(err)
+	if st.Code() != codes.Unavailable || st.Message() != "backend went away" {
+		t.Errorf("expected Unavailable \"backend went away\", got %v", err)
+	}
+	if vals := stream.Trailer().Get("x-failure"); len(vals) != 1 || vals[0] != "injected" {
+		t.Errorf("expected trailer x-failure=injected, got %v", vals)
+	}
+}
+
+func TestServerStream_ResetsStream(t *testing.T) {
+	client := setupHTTPTestServer(t)
+
+	stream, err := client.ServerStream(context.Background(), &pb.ServerStreamRequest{
+		Message: "ping",
+		Count:   5,
+		Failure: &pb.StreamFailure{After: 3, RstStream: true},
+	})
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+
+	var received int
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+		received++
+	}
+
+	if received != 3 {
+		t.Errorf("expected 3 messages before the reset, got %d", received)
+	}
+	if !isRSTStream(err) {
+		t.Errorf("expected RST_STREAM error, got %v", err)
+	}
+
+	// The connection stays usable after the reset
+	if _, err := client.Echo(context.Background(), &pb.EchoRequest{Message: "hello"}); err != nil {
+		t.Errorf("Echo after reset failed: %v", err)
+	}
+}
+
+func TestServerStream_ResetsStreamOverTLS(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterEchoServer(s, NewEchoServer())
+	server := httptest.NewUnstartedServer(NewHTTPHandler(s))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	conn, err := grpc.NewClient("passthrough:///"+server.Listener.Addr().String(),
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: roots})),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	stream, err := pb.NewEchoClient(conn).ServerStream(context.Background(), &pb.ServerStreamRequest{
+		Count:   2,
+		Failure: &pb.StreamFailure{After: 1, RstStream: true},
+	})
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if _, err := stream.Recv(); !isRSTStream(err) {
+		t.Errorf("expected RST_STREAM error, got %v", err)
+	}
+}
+
+// TestServerStream_ResetNeedsHTTPTransport checks that grpc-go's own
+// transport, which cannot reset streams, rejects rst_stream.
+func TestServerStream_ResetNeedsHTTPTransport(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	stream, err := client.ServerStream(context.Background(), &pb.ServerStreamRequest{
+		Count:   2,
+		Failure: &pb.StreamFailure{After: 1, RstStream: true},
+	})
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented, got %v", err)
+	}
+}
+
+func TestClientStream_FailsAfterMessages(t *testing.T) {
+	client := setupHTTPTestServer(t)
+
+	tests := []struct {
+		name    string
+		failure *pb.StreamFailure
+		check   func(t *testing.T, resp *pb.EchoResponse, trailer metadata.MD, err error)
+	}{
+		{
+			name:    "error status",
+			failure: &pb.StreamFailure{After: 2, Code: int32(codes.Aborted), Message: "aborted"},
+			check: func(t *testing.T, _ *pb.EchoResponse, _ metadata.MD, err error) {
+				if status.Code(err) != codes.Aborted {
+					t.Errorf("expected Aborted, got %v", err)
+				}
+			},
+		},
+		{
+			name:    "early response",
+			failure: &pb.StreamFailure{After: 2, Trailers: []*pb.MetadataEntry{{Key: "x-early", Value: "true"}}},
+			check: func(t *testing.T, resp *pb.EchoResponse, trailer metadata.MD, err error) {
+				if err != nil {
+					t.Fatalf("expected early response, got %v", err)
+				}
+				if resp.Message != "one, two" {
+					t.Errorf("expected %q, got %q", "one, two", resp.Message)
+				}
+				if vals := trailer.Get("x-early"); len(vals) != 1 || vals[0] != "true" {
+					t.Errorf("expected trailer x-early=true, got %v", vals)
+				}
+			},
+		},
+		{
+			name:    "reset",
+			failure: &pb.StreamFailure{After: 1, RstStream: true},
+			check: func(t *testing.T, _ *pb.EchoResponse, _ metadata.MD, err error) {
+				if !isRSTStream(err) {
+					t.Errorf("expected RST_STREAM error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.ClientStream(context.Background())
+			if err != nil {
+				t.Fatalf("ClientStream failed: %v", err)
+			}
+
+			requests := []*pb.EchoRequest{
+				{Message: "one", Failure: tt.failure},
+				{Message: "two"},
+				{Message: "three"},
+			}
+			for _, req := range requests {
+				// Sends fail with io.EOF once the server has ended the stream
+				if err := stream.Send(req); err != nil && err != io.EOF {
+					t.Fatalf("Send failed: %v", err)
+				}
+			}
+
+			resp, err := stream.CloseAndRecv()
+			tt.check(t, resp, stream.Trailer(), err)
+		})
+	}
+}
+
+func TestBidirectionalStream_FailsAfterMessages(t *testing.T) {
+	client := setupHTTPTestServer(t)
+
+	tests := []struct {
+		name    string
+		failure *pb.StreamFailure
+		matches func(error) bool
+	}{
+		{
+			name:    "error status",
+			failure: &pb.StreamFailure{After: 1, Code: int32(codes.DataLoss)},
+			matches: func(err error) bool { return status.Code(err) == codes.DataLoss },
+		},
+		{
+			name:    "reset",
+			failure: &pb.StreamFailure{After: 1, RstStream: true},
+			matches: isRSTStream,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.BidirectionalStream(context.Background())
+			if err != nil {
+				t.Fatalf("BidirectionalStream failed: %v", err)
+			}
+
+			if err := stream.Send(&pb.EchoRequest{Message: "one", Failure: tt.failure}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+			resp, err := stream.Recv()
+			if err != nil || resp.Message != "one" {
+				t.Fatalf("expected echo of first message, got %v, %v", resp, err)
+			}
+
+			if err := stream.Send(&pb.EchoRequest{Message: "two"}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+			if _, err := stream.Recv(); !tt.matches(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStreamFailure_InvalidArgument(t *testing.T) {
+	client := setupHTTPTestServer(t)
+
+	stream, err := client.ServerStream(context.Background(), &pb.ServerStreamRequest{
+		Count:   1,
+		Failure: &pb.StreamFailure{Trailers: []*pb.MetadataEntry{{Key: "grpc-status", Value: "0"}}},
+	})
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+const (
+	defaultFlakyTTL = time.Minute
+	// maxFlakySequences caps the sequences tracked at once, since clients
+	// choose both the sequence IDs and their TTL
+	maxFlakySequences = 10000
+)
+
+type flakySequence struct {
+	attempts  int32
+	expiresAt time.Time
+}
+
+// flakyTracker counts attempts per sequence ID. Sequences that see no attempt
+// within their TTL are evicted lazily on the next call, and the sequence
+// closest to expiry makes room for a new one once limit is reached.
+type flakyTracker struct {
+	mu        sync.Mutex
+	sequences map[string]*flakySequence
+	limit     int
+}
+
+func newFlakyTracker() *flakyTracker {
+	return &flakyTracker{
+		sequences: make(map[string]*flakySequence),
+		limit:     maxFlakySequences,
+	}
+}
+
+// next records an attempt for id and returns its 1-based attempt number.
+func (t *flakyTracker) next(id string, ttl time.Duration) int32 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for key, seq := range t.sequences {
+		if now.After(seq.expiresAt) {
+			delete(t.sequences, key)
+		}
+	}
+
+	seq, ok := t.sequences[id]
+	if !ok {
+		if len(t.sequences) >= t.limit {
+			t.evictNextToExpire()
+		}
+		seq = &flakySequence{}
+		t.sequences[id] = seq
+	}
+	seq.attempts++
+	seq.expiresAt = now.Add(ttl)
+
+	return seq.attempts
+}
+
+func (t *flakyTracker) evictNextToExpire() {
+	var oldest string
+	var oldestExpiry time.Time
+	for key, seq := range t.sequences {
+		if oldest == "" || seq.expiresAt.Before(oldestExpiry) {
+			oldest, oldestExpiry = key, seq.expiresAt
+		}
+	}
+	delete(t.sequences, oldest)
+}
+
+func (s *EchoServer) EchoFlaky(ctx context.Context, req *pb.EchoFlakyRequest) (*pb.EchoFlakyResponse, error) {
+	if req.SequenceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "sequence_id is required")
+	}
+
+	ttl := defaultFlakyTTL
+	if req.TtlMs > 0 {
+		ttl = time.Duration(req.TtlMs) * time.Millisecond
+	}
+
+	attempt := s.flaky.next(req.SequenceId, ttl)
+
+	var previousAttempts int32
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("grpc-previous-rpc-attempts"); len(v) > 0 {
+			if n, err := strconv.Atoi(v[0]); err == nil {
+				previousAttempts = int32(n)
+			}
+		}
+	}
+
+	// Report the attempt in trailers: sending headers would commit the RPC and
+	// prevent the client from retrying it.
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(
+		"x-flaky-attempt", strconv.Itoa(int(attempt)),
+		"x-flaky-previous-rpc-attempts", strconv.Itoa(int(previousAttempts)),
+	))
+
+	// Attempts beyond the configured list repeat the last code
+	var code codes.Code
+	if n := int32(len(req.Codes)); n > 0 {
+		code = codes.Code(req.Codes[min(attempt, n)-1])
+		if code > 16 {
+			code = codes.Unknown
+		}
+	}
+
+	if code != codes.OK {
+		return nil, status.Error(code, fmt.Sprintf("flaky failure on attempt %d of sequence %q", attempt, req.SequenceId))
+	}
+
+	return &pb.EchoFlakyResponse{
+		Message:             req.Message,
+		SequenceId:          req.SequenceId,
+		Attempt:             attempt,
+		PreviousRpcAttempts: previousAttempts,
+	}, nil
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+func TestEchoFlaky_ReturnsCodesInOrder(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	req := &pb.EchoFlakyRequest{
+		SequenceId: "order",
+		Codes:      []int32{int32(codes.Unavailable), int32(codes.Unavailable), int32(codes.OK)},
+		Message:    "hello",
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		var trailer metadata.MD
+		_, err := client.EchoFlaky(context.Background(), req, grpc.Trailer(&trailer))
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("attempt %d: expected Unavailable, got %v", attempt, err)
+		}
+		if got := trailer.Get("x-flaky-attempt"); len(got) == 0 || got[0] != strconv.Itoa(attempt) {
+			t.Errorf("attempt %d: expected x-flaky-attempt=%d, got %v", attempt, attempt, got)
+		}
+	}
+
+	resp, err := client.EchoFlaky(context.Background(), req)
+	if err != nil {
+		t.Fatalf("attempt 3: expected success, got %v", err)
+	}
+	if resp.Attempt != 3 {
+		t.Errorf("expected attempt 3, got %d", resp.Attempt)
+	}
+	if resp.Message != "hello" {
+		t.Errorf("expected message 'hello', got %q", resp.Message)
+	}
+
+	// The last code repeats once the list is exhausted
+	resp, err = client.EchoFlaky(context.Background(), req)
+	if err != nil {
+		t.Fatalf("attempt 4: expected success, got %v", err)
+	}
+	if resp.Attempt != 4 {
+		t.Errorf("expected attempt 4, got %d", resp.Attempt)
+	}
+}
+
+func TestEchoFlaky_SequencesAreIndependent(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	codesList := []int32{int32(codes.Aborted), int32(codes.OK)}
+
+	_, err := client.EchoFlaky(context.Background(), &pb.EchoFlakyRequest{SequenceId: "a", Codes: codesList})
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("expected Aborted for sequence a, got %v", err)
+	}
+
+	_, err = client.EchoFlaky(context.Background(), &pb.EchoFlakyRequest{SequenceId: "b", Codes: codesList})
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("expected Aborted for sequence b, got %v", err)
+	}
+}
+
+func TestEchoFlaky_ExpiresAfterTTL(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	req := &pb.EchoFlakyRequest{
+		SequenceId: "ttl",
+		Codes:      []int32{int32(codes.Unavailable), int32(codes.OK)},
+		TtlMs:      50,
+	}
+
+	_, err := client.EchoFlaky(context.Background(), req)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = client.EchoFlaky(context.Background(), req)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected sequence to restart after TTL, got %v", err)
+	}
+}
+
+func TestEchoFlaky_RequiresSequenceID(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	_, err := client.EchoFlaky(context.Background(), &pb.EchoFlakyRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestEchoFlaky_ReportsPreviousRPCAttempts(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pb.RegisterEchoServer(s, NewEchoServer())
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	defer s.Stop()
+
+	serviceConfig := `{
+		"methodConfig": [{
+			"name": [{"service": "echo.v1.Echo", "method": "EchoFlaky"}],
+			"retryPolicy": {
+				"maxAttempts": 4,
+				"initialBackoff": "0.01s",
+				"maxBackoff": "0.01s",
+				"backoffMultiplier": 1,
+				"retryableStatusCodes": ["UNAVAILABLE"]
+			}
+		}]
+	}`
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	resp, err := pb.NewEchoClient(conn).EchoFlaky(context.Background(), &pb.EchoFlakyRequest{
+		SequenceId: "retry",
+		Codes:      []int32{int32(codes.Unavailable), int32(codes.Unavailable), int32(codes.OK)},
+	})
+	if err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+
+	if resp.Attempt != 3 {
+		t.Errorf("expected attempt 3, got %d", resp.Attempt)
+	}
+	if resp.PreviousRpcAttempts != 2 {
+		t.Errorf("expected previous_rpc_attempts 2, got %d", resp.PreviousRpcAttempts)
+	}
+}
+
+func TestFlakyTracker_EvictsAtLimit(t *testing.T) {
+	tracker := newFlakyTracker()
+	tracker.limit = 2
+
+	tracker.next("short", time.Second)
+	tracker.next("long", time.Hour)
+	tracker.next("new", time.Hour)
+
+	if len(tracker.sequences) != 2 {
+		t.Fatalf("expected 2 tracked sequences, got %d", len(tracker.sequences))
+	}
+	if _, ok := tracker.sequences["short"]; ok {
+		t.Error("expected the sequence closest to expiry to be evicted")
+	}
+	if attempt := tracker.next("long", time.Hour); attempt != 2 {
+		t.Errorf("expected attempt 2 for a kept sequence, got %d", attempt)
+	}
+}
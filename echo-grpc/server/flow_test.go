@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+func TestFloodStream_SendsAllMessages(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	stream, err := client.FloodStream(context.Background(), &pb.FloodRequest{
+		Count:        5000,
+		PayloadBytes: 16,
+	})
+	if err != nil {
+		t.Fatalf("FloodStream failed: %v", err)
+	}
+
+	var received int32
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		received++
+		if resp.Sequence != received {
+			t.Fatalf("expected sequence %d, got %d", received, resp.Sequence)
+		}
+		if len(resp.Payload) != 16 {
+			t.Fatalf("expected 16-byte payload, got %d", len(resp.Payload))
+		}
+		if resp.SentAt == nil {
+			t.Fatal("expected sent_at to be set")
+		}
+	}
+
+	if received != 5000 {
+		t.Errorf("expected 5000 messages, got %d", received)
+	}
+}
+
+func TestFloodStream_OversizeMessage(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	stream, err := client.FloodStream(context.Background(), &pb.FloodRequest{
+		Count:         5,
+		PayloadBytes:  16,
+		OversizeAt:    3,
+		OversizeBytes: 4096,
+	}, grpc.MaxCallRecvMsgSize(1024))
+	if err != nil {
+		t.Fatalf("FloodStream failed: %v", err)
+	}
+
+	var received int
+	for {
+		_, err := stream.Recv()
+		if err != nil {
+			if status.Code(err) != codes.ResourceExhausted {
+				t.Fatalf("expected ResourceExhausted, got %v", err)
+			}
+			break
+		}
+		received++
+	}
+
+	if received != 2 {
+		t.Errorf("expected 2 messages before the oversize one, got %d", received)
+	}
+}
+
+func TestFloodStream_InvalidArgument(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		req  *pb.FloodRequest
+	}{
+		{name: "count over max", req: &pb.FloodRequest{Count: maxFloodCount + 1}},
+		{name: "payload over max", req: &pb.FloodRequest{PayloadBytes: maxFloodPayload + 1}},
+		{name: "oversize_at past count", req: &pb.FloodRequest{Count: 2, OversizeAt: 3}},
+		{name: "oversize_bytes over max", req: &pb.FloodRequest{OversizeAt: 1, OversizeBytes: MaxPayloadSize + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.FloodStream(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("FloodStream failed: %v", err)
+			}
+			if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSlowReadStream_PausesReading(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	stream, err := client.SlowReadStream(context.Background())
+	if err != nil {
+		t.Fatalf("SlowReadStream failed: %v", err)
+	}
+
+	requests := []*pb.SlowReadRequest{
+		{Message: "one", PauseAfter: 1, PauseMs: 200},
+		{Message: "two"},
+		{Message: "three"},
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend failed: %v", err)
+	}
+
+	var responses []*pb.SlowReadResponse
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+
+	if len(responses) != 3 {
+		t.Fatalf("expected 3 responses, got %d", len(responses))
+	}
+	for i, resp := range responses {
+		if resp.Sequence != int32(i+1) || resp.Message != requests[i].Message {
+			t.Errorf("expected %d %q, got %d %q", i+1, requests[i].Message, resp.Sequence, resp.Message)
+		}
+	}
+
+	gap := responses[1].ReceivedAt.AsTime().Sub(responses[0].ReceivedAt.AsTime())
+	if gap < 200*time.Millisecond {
+		t.Errorf("expected reading to pause for 200ms, second message read after %s", gap)
+	}
+}
+
+func TestSlowReadStream_InvalidArgument(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	stream, err := client.SlowReadStream(context.Background())
+	if err != nil {
+		t.Fatalf("SlowReadStream failed: %v", err)
+	}
+	if err := stream.Send(&pb.SlowReadRequest{PauseMs: -1}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
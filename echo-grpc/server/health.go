@@ -1,24 +1,44 @@
 package server
 
 import (
+	"errors"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// ErrHealthShutdown is returned by Schedule once Shutdown has been called.
+var ErrHealthShutdown = errors.New("health server is shutting down")
+
+// HealthStep is one entry of a health schedule: Status is reported for
+// Duration before moving on to the next step.
+type HealthStep struct {
+	Status   healthpb.HealthCheckResponse_ServingStatus
+	Duration time.Duration
+}
+
+// healthSchedule is closed to stop a running schedule.
+type healthSchedule struct {
+	stop chan struct{}
+}
+
 // HealthServer wraps the standard gRPC health server with service management.
 type HealthServer struct {
 	*health.Server
-	mu       sync.RWMutex
-	services map[string]healthpb.HealthCheckResponse_ServingStatus
+	mu        sync.RWMutex
+	services  map[string]healthpb.HealthCheckResponse_ServingStatus
+	schedules map[string]*healthSchedule
+	shutdown  bool
 }
 
 // NewHealthServer creates a new health server with default services.
 func NewHealthServer() *HealthServer {
 	h := &HealthServer{
-		Server:   health.NewServer(),
-		services: make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+		Server:    health.NewServer(),
+		services:  make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+		schedules: make(map[string]*healthSchedule),
 	}
 
 	// Set overall server status (empty service name = overall status)
@@ -30,12 +50,13 @@ func NewHealthServer() *HealthServer {
 	return h
 }
 
-// SetServingStatus updates the serving status for a service.
+// SetServingStatus updates the serving status for a service, stopping any
+// schedule running for it.
 func (h *HealthServer) SetServingStatus(service string, status healthpb.HealthCheckResponse_ServingStatus) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.services[service] = status
-	h.Server.SetServingStatus(service, status)
+	h.stopScheduleLocked(service)
+	h.setLocked(service, status)
 }
 
 // GetServingStatus returns the current serving status for a service.
@@ -48,12 +69,99 @@ func (h *HealthServer) GetServingStatus(service string) healthpb.HealthCheckResp
 	return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
 }
 
-// Shutdown sets all services to NOT_SERVING status.
+// Schedule replaces any schedule running for service. The first step takes
+// effect immediately and each following step after the previous one's
+// Duration; with repeat the schedule loops until replaced. It returns the
+// status the service had before the call.
+func (h *HealthServer) Schedule(service string, steps []HealthStep, repeat bool) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.shutdown {
+		return 0, ErrHealthShutdown
+	}
+
+	previous, ok := h.services[service]
+	if !ok {
+		previous = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+
+	h.stopScheduleLocked(service)
+	if len(steps) == 0 {
+		return previous, nil
+	}
+	h.setLocked(service, steps[0].Status)
+
+	if len(steps) > 1 || repeat {
+		schedule := &healthSchedule{stop: make(chan struct{})}
+		h.schedules[service] = schedule
+		go h.runSchedule(service, schedule, steps, repeat)
+	}
+
+	return previous, nil
+}
+
+func (h *HealthServer) runSchedule(service string, schedule *healthSchedule, steps []HealthStep, repeat bool) {
+	defer func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.schedules[service] == schedule {
+			delete(h.schedules, service)
+		}
+	}()
+
+	for i := 0; ; {
+		timer := time.NewTimer(steps[i].Duration)
+		select {
+		case <-schedule.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		i++
+		if i == len(steps) {
+			if !repeat {
+				return
+			}
+			i = 0
+		}
+
+		// Re-check under the lock so a replaced schedule never overwrites
+		// the status set by its successor
+		h.mu.Lock()
+		select {
+		case <-schedule.stop:
+			h.mu.Unlock()
+			return
+		default:
+		}
+		h.setLocked(service, steps[i].Status)
+		h.mu.Unlock()
+	}
+}
+
+// Shutdown stops all schedules and sets all services to NOT_SERVING status.
 func (h *HealthServer) Shutdown() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	h.shutdown = true
+	for service := range h.schedules {
+		h.stopScheduleLocked(service)
+	}
 	for service := range h.services {
-		h.services[service] = healthpb.HealthCheckResponse_NOT_SERVING
-		h.Server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+		h.setLocked(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+func (h *HealthServer) setLocked(service string, status healthpb.HealthCheckResponse_ServingStatus) {
+	h.services[service] = status
+	h.Server.SetServingStatus(service, status)
+}
+
+func (h *HealthServer) stopScheduleLocked(service string) {
+	if schedule, ok := h.schedules[service]; ok {
+		close(schedule.stop)
+		delete(h.schedules, service)
 	}
 }
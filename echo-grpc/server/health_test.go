@@ -2,6 +2,7 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
@@ -63,3 +64,72 @@ func TestHealthServer_Shutdown(t *testing.T) {
 		t.Errorf("expected test.service status NOT_SERVING after shutdown, got %v", status)
 	}
 }
+
+func TestHealthServer_Schedule(t *testing.T) {
+	h := NewHealthServer()
+
+	previous, err := h.Schedule("test.service", []HealthStep{
+		{Status: healthpb.HealthCheckResponse_NOT_SERVING, Duration: 20 * time.Millisecond},
+		{Status: healthpb.HealthCheckResponse_SERVING},
+	}, false)
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	if previous != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Errorf("expected previous status SERVICE_UNKNOWN, got %v", previous)
+	}
+
+	// The first step applies immediately
+	if status := h.GetServingStatus("test.service"); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING, got %v", status)
+	}
+
+	waitForStatus(t, h, "test.service", healthpb.HealthCheckResponse_SERVING)
+}
+
+func TestHealthServer_SetServingStatus_StopsSchedule(t *testing.T) {
+	h := NewHealthServer()
+
+	_, _ = h.Schedule("test.service", []HealthStep{
+		{Status: healthpb.HealthCheckResponse_NOT_SERVING, Duration: 10 * time.Millisecond},
+		{Status: healthpb.HealthCheckResponse_SERVING, Duration: 10 * time.Millisecond},
+	}, true)
+	h.SetServingStatus("test.service", healthpb.HealthCheckResponse_UNKNOWN)
+
+	time.Sleep(50 * time.Millisecond)
+	if status := h.GetServingStatus("test.service"); status != healthpb.HealthCheckResponse_UNKNOWN {
+		t.Errorf("expected the schedule to stop at UNKNOWN, got %v", status)
+	}
+}
+
+func TestHealthServer_Shutdown_StopsSchedules(t *testing.T) {
+	h := NewHealthServer()
+
+	_, _ = h.Schedule("", []HealthStep{
+		{Status: healthpb.HealthCheckResponse_SERVING, Duration: 10 * time.Millisecond},
+		{Status: healthpb.HealthCheckResponse_SERVING, Duration: 10 * time.Millisecond},
+	}, true)
+	h.Shutdown()
+
+	time.Sleep(50 * time.Millisecond)
+	if status := h.GetServingStatus(""); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING after shutdown, got %v", status)
+	}
+
+	if _, err := h.Schedule("", []HealthStep{{Status: healthpb.HealthCheckResponse_SERVING}}, false); err != ErrHealthShutdown {
+		t.Errorf("expected ErrHealthShutdown, got %v", err)
+	}
+}
+
+func waitForStatus(t *testing.T, h *HealthServer, service string, want healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if h.GetServingStatus(service) == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("expected %s to become %v, got %v", service, want, h.GetServingStatus(service))
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+type httpCallKey struct{}
+
+// httpCall lets a call served by NewHTTPHandler change how its response is
+// written, which grpc-go's own transport has no API for.
+type httpCall struct {
+	reset atomic.Bool
+	wire  atomic.Pointer[pb.WireStatus]
+}
+
+// NewHTTPHandler serves s with net/http through grpc.Server.ServeHTTP, for
+// listeners with transport=http. Unlike grpc-go's own transport, it lets
+// streaming RPCs abort their stream with RST_STREAM and EchoError send
+// malformed statuses. Serve it over HTTP/2, with h2c for plaintext listeners.
+func NewHTTPHandler(s *grpc.Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := &httpCall{}
+		ww := &wireStatusWriter{ResponseWriter: w, call: call}
+		s.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), httpCallKey{}, call)))
+		ww.finish()
+
+		// ServeHTTP returns once the status is in the trailers, which are
+		// only sent when the handler returns. The panic makes net/http
+		// reset the stream with RST_STREAM(INTERNAL_ERROR) instead, without
+		// logging.
+		if call.reset.Load() {
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
+
+func httpCallFromContext(ctx context.Context) (*httpCall, bool) {
+	call, ok := ctx.Value(httpCallKey{}).(*httpCall)
+	return call, ok
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+// metadataFromEntries builds metadata from entries, keeping repeated keys as
+// multiple values in order. Values of "-bin" keys are sent as raw bytes,
+// which gRPC base64-encodes on the wire.
+func metadataFromEntries(entries []*pb.MetadataEntry) (metadata.MD, error) {
+	md := metadata.MD{}
+	for _, entry := range entries {
+		key := strings.ToLower(entry.Key)
+		if err := validateMetadataKey(key); err != nil {
+			return nil, err
+		}
+
+		value := entry.Value
+		if len(entry.BinaryValue) > 0 {
+			if !strings.HasSuffix(key, "-bin") {
+				return nil, fmt.Errorf("binary_value requires a key ending in -bin, got %q", key)
+			}
+			value = string(entry.BinaryValue)
+		}
+		md.Append(key, value)
+	}
+	return md, nil
+}
+
+// validateMetadataKey rejects keys that are not valid gRPC metadata keys or
+// that are reserved for the protocol.
+func validateMetadataKey(key string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	if strings.HasPrefix(key, "grpc-") || strings.HasPrefix(key, "connect-") {
+		return fmt.Errorf("reserved key %q", key)
+	}
+	for _, c := range key {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' && c != '.' {
+			return fmt.Errorf("invalid character %q in key %q", c, key)
+		}
+	}
+	return nil
+}
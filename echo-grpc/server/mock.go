@@ -0,0 +1,599 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"gopkg.in/yaml.v3"
+)
+
+// MockRules answers calls matching a rule with a canned response instead of
+// the real handler. Rules are tried in file order and the first match wins.
+type MockRules struct {
+	methods map[string]*mockMethod
+}
+
+type mockMethod struct {
+	desc  protoreflect.MethodDescriptor
+	input protoreflect.MessageType
+	rules []*mockRule
+}
+
+type mockRule struct {
+	request   []mockFieldMatcher
+	metadata  map[string]*mockMatcher
+	delay     time.Duration
+	headers   metadata.MD
+	trailers  metadata.MD
+	responses []proto.Message
+	status    *status.Status
+}
+
+// mockRulesFile is the rules file, in YAML or JSON.
+type mockRulesFile struct {
+	Rules []mockRuleSpec `json:"rules"`
+}
+
+type mockRuleSpec struct {
+	Method    string                  `json:"method"`
+	Request   map[string]*mockMatcher `json:"request"`
+	Metadata  map[string]*mockMatcher `json:"metadata"`
+	Delay     string                  `json:"delay"`
+	Headers   map[string]string       `json:"headers"`
+	Trailers  map[string]string       `json:"trailers"`
+	Response  json.RawMessage         `json:"response"`
+	Responses []json.RawMessage       `json:"responses"`
+	Status    *mockStatusSpec         `json:"status"`
+}
+
+type mockStatusSpec struct {
+	Code    codes.Code        `json:"code"`
+	Message string            `json:"message"`
+	Details []json.RawMessage `json:"details"`
+}
+
+// mockMatcher matches a value exactly, given as a scalar or {equals: ...},
+// or by regular expression, given as {regex: ...}.
+type mockMatcher struct {
+	equals *string
+	regex  *regexp.Regexp
+}
+
+func (m *mockMatcher) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var spec struct {
+			Equals json.RawMessage `json:"equals"`
+			Regex  *string         `json:"regex"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&spec); err != nil {
+			return err
+		}
+		if (spec.Equals != nil) == (spec.Regex != nil) {
+			return errors.New("matcher needs exactly one of equals and regex")
+		}
+		if spec.Regex != nil {
+			re, err := regexp.Compile(*spec.Regex)
+			if err != nil {
+				return err
+			}
+			m.regex = re
+			return nil
+		}
+		data = spec.Equals
+	}
+
+	// Scalars compare as text with the formatted field value
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+	switch value.(type) {
+	case map[string]any, []any, nil:
+		return fmt.Errorf("matcher value must be a scalar, got %s", data)
+	}
+	equals := fmt.Sprint(value)
+	m.equals = &equals
+	return nil
+}
+
+func (m *mockMatcher) match(value string) bool {
+	if m.regex != nil {
+		return m.regex.MatchString(value)
+	}
+	return *m.equals == value
+}
+
+// mockFieldMatcher matches the scalar at a dotted field path of the request.
+type mockFieldMatcher struct {
+	path    mockFieldPath
+	matcher *mockMatcher
+}
+
+// mockFieldPath walks fields by proto or JSON name. A list index or map key
+// follows a repeated or map field, e.g. "tags.0" or "labels.env".
+type mockFieldPath []mockPathSegment
+
+type mockPathSegment struct {
+	field protoreflect.FieldDescriptor
+	index int
+	key   protoreflect.MapKey
+}
+
+func compileFieldPath(md protoreflect.MessageDescriptor, path string) (mockFieldPath, error) {
+	names := strings.Split(path, ".")
+	var fp mockFieldPath
+	var elem protoreflect.FieldDescriptor
+	for i := 0; i < len(names); i++ {
+		if md == nil {
+			return nil, fmt.Errorf("%s: %s is not a message", path, elem.FullName())
+		}
+		fd := md.Fields().ByName(protoreflect.Name(names[i]))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(names[i])
+		}
+		if fd == nil {
+			return nil, fmt.Errorf("%s: no field %q in %s", path, names[i], md.FullName())
+		}
+
+		seg := mockPathSegment{field: fd}
+		elem = fd
+		if fd.IsList() || fd.IsMap() {
+			if i+1 == len(names) {
+				return nil, fmt.Errorf("%s: %s needs an index or key", path, fd.Name())
+			}
+			i++
+			if fd.IsList() {
+				index, err := strconv.Atoi(names[i])
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("%s: invalid index %q", path, names[i])
+				}
+				seg.index = index
+			} else {
+				key, err := parseMapKey(fd.MapKey(), names[i])
+				if err != nil {
+					return nil, fmt.Errorf("%s: invalid key %q: %w", path, names[i], err)
+				}
+				seg.key = key
+				elem = fd.MapValue()
+			}
+		}
+		fp = append(fp, seg)
+		md = elem.Message()
+	}
+	if md != nil {
+		return nil, fmt.Errorf("%s: %s is a message, not a scalar", path, elem.FullName())
+	}
+	return fp, nil
+}
+
+func parseMapKey(fd protoreflect.FieldDescriptor, s string) (protoreflect.MapKey, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s).MapKey(), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(b).MapKey(), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)).MapKey(), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)).MapKey(), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(n).MapKey(), err
+	default:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(n).MapKey(), err
+	}
+}
+
+// value formats the scalar at the path like protojson, except that strings
+// are unquoted. Missing list elements and map entries have no value.
+func (p mockFieldPath) value(m protoreflect.Message) (string, bool) {
+	var fd protoreflect.FieldDescriptor
+	var v protoreflect.Value
+	for _, seg := range p {
+		fd = seg.field
+		v = m.Get(fd)
+		switch {
+		case fd.IsList():
+			if seg.index >= v.List().Len() {
+				return "", false
+			}
+			v = v.List().Get(seg.index)
+		case fd.IsMap():
+			if !v.Map().Has(seg.key) {
+				return "", false
+			}
+			v = v.Map().Get(seg.key)
+			fd = fd.MapValue()
+		}
+		if fd.Message() != nil {
+			m = v.Message()
+		}
+	}
+
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name()), true
+		}
+		return strconv.Itoa(int(v.Enum())), true
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes()), true
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), true
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+	}
+	return v.String(), true
+}
+
+// LoadMockRules reads a rules file. Methods, request fields and responses
+// are resolved against the compiled-in services and files, which may be nil.
+func LoadMockRules(path string, files *protoregistry.Files) (*MockRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON; converting it to JSON lets responses be
+	// parsed with protojson
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var file mockRulesFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	types := mockTypes{}
+	if files != nil {
+		types.dynamic = dynamicpb.NewTypes(files)
+	}
+	desc := descriptorResolver(files)
+
+	r := &MockRules{methods: make(map[string]*mockMethod)}
+	for i, spec := range file.Rules {
+		if err := r.add(spec, desc, types); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", path, i+1, err)
+		}
+	}
+	return r, nil
+}
+
+func (r *MockRules) add(spec mockRuleSpec, desc protodesc.Resolver, types mockTypes) error {
+	// Accept both "/pkg.Service/Method" and "pkg.Service/Method"
+	fullMethod := "/" + strings.TrimPrefix(spec.Method, "/")
+	service, name, ok := strings.Cut(fullMethod[1:], "/")
+	if !ok {
+		return fmt.Errorf("invalid method %q", spec.Method)
+	}
+
+	method, ok := r.methods[fullMethod]
+	if !ok {
+		d, err := desc.FindDescriptorByName(protoreflect.FullName(service))
+		sd, isService := d.(protoreflect.ServiceDescriptor)
+		if err != nil || !isService {
+			return fmt.Errorf("unknown service %q", service)
+		}
+		md := sd.Methods().ByName(protoreflect.Name(name))
+		if md == nil {
+			return fmt.Errorf("unknown method %q", spec.Method)
+		}
+		method = &mockMethod{desc: md, input: types.messageType(md.Input())}
+		r.methods[fullMethod] = method
+	}
+
+	rule := &mockRule{metadata: make(map[string]*mockMatcher)}
+	for path, matcher := range spec.Request {
+		fp, err := compileFieldPath(method.desc.Input(), path)
+		if err != nil {
+			return err
+		}
+		rule.request = append(rule.request, mockFieldMatcher{path: fp, matcher: matcher})
+	}
+	for key, matcher := range spec.Metadata {
+		rule.metadata[strings.ToLower(key)] = matcher
+	}
+
+	if spec.Delay != "" {
+		delay, err := time.ParseDuration(spec.Delay)
+		if err != nil {
+			return fmt.Errorf("invalid delay: %w", err)
+		}
+		rule.delay = delay
+	}
+	rule.headers = metadata.New(spec.Headers)
+	rule.trailers = metadata.New(spec.Trailers)
+
+	unmarshal := protojson.UnmarshalOptions{Resolver: types}
+	raw := spec.Responses
+	if spec.Response != nil {
+		if raw != nil {
+			return errors.New("use either response or responses")
+		}
+		raw = []json.RawMessage{spec.Response}
+	}
+	if len(raw) > 1 && !method.desc.IsStreamingServer() {
+		return fmt.Errorf("%s returns a single response", spec.Method)
+	}
+	if len(raw) == 0 {
+		raw = []json.RawMessage{[]byte("{}")}
+	}
+	output := types.messageType(method.desc.Output())
+	for _, data := range raw {
+		resp := output.New().Interface()
+		if err := unmarshal.Unmarshal(data, resp); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		rule.responses = append(rule.responses, resp)
+	}
+
+	if spec.Status != nil {
+		st := &spb.Status{Code: int32(spec.Status.Code), Message: spec.Status.Message}
+		for _, data := range spec.Status.Details {
+			detail := &anypb.Any{}
+			if err := unmarshal.Unmarshal(data, detail); err != nil {
+				return fmt.Errorf("invalid status detail: %w", err)
+			}
+			st.Details = append(st.Details, detail)
+		}
+		rule.status = status.FromProto(st)
+	}
+
+	method.rules = append(method.rules, rule)
+	return nil
+}
+
+func (r *mockRule) matchMetadata(md metadata.MD) bool {
+	for key, matcher := range r.metadata {
+		matched := false
+		for _, value := range md.Get(key) {
+			if matcher.match(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *mockRule) matchRequest(req proto.Message) bool {
+	m := req.ProtoReflect()
+	for _, fm := range r.request {
+		value, ok := fm.path.value(m)
+		if !ok || !fm.matcher.match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *mockRule) wait(ctx context.Context) error {
+	if r.delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(r.delay):
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// UnaryInterceptor answers unary calls matching a rule with its response, or
+// its status if that is not OK.
+func (r *MockRules) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method, ok := r.methods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, rule := range method.rules {
+			if !rule.matchMetadata(md) || !rule.matchRequest(req.(proto.Message)) {
+				continue
+			}
+
+			if err := rule.wait(ctx); err != nil {
+				return nil, err
+			}
+			_ = grpc.SetHeader(ctx, rule.headers)
+			_ = grpc.SetTrailer(ctx, rule.trailers)
+			if err := rule.status.Err(); err != nil {
+				return nil, err
+			}
+			return proto.Clone(rule.responses[0]), nil
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor answers streaming calls matching a rule with its
+// responses, then its status. Request fields are matched against the first
+// request, which is passed on to the handler if no rule matches. Methods of
+// dynamic services are all streams, so their unary methods come here too.
+func (r *MockRules) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		method, ok := r.methods[info.FullMethod]
+		if !ok {
+			return handler(srv, ss)
+		}
+
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		var peeked *peekedStream
+		for _, rule := range method.rules {
+			if !rule.matchMetadata(md) {
+				continue
+			}
+			if len(rule.request) > 0 {
+				if peeked == nil {
+					peeked = &peekedStream{ServerStream: ss, msg: method.input.New().Interface()}
+					peeked.err = ss.RecvMsg(peeked.msg)
+				}
+				if peeked.err != nil || !rule.matchRequest(peeked.msg) {
+					continue
+				}
+			}
+			return rule.serveStream(ss, method)
+		}
+
+		if peeked != nil {
+			return handler(srv, peeked)
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (r *mockRule) serveStream(ss grpc.ServerStream, method *mockMethod) error {
+	if err := r.wait(ss.Context()); err != nil {
+		return err
+	}
+	if err := ss.SetHeader(r.headers); err != nil {
+		return err
+	}
+	ss.SetTrailer(r.trailers)
+
+	// Client streaming methods reply once the client is done, unless the
+	// status replaces the reply
+	if !method.desc.IsStreamingServer() {
+		if err := drainStream(ss, method.input); err != nil {
+			return err
+		}
+		if err := r.status.Err(); err != nil {
+			return err
+		}
+		return ss.SendMsg(r.responses[0])
+	}
+
+	for _, resp := range r.responses {
+		if err := ss.SendMsg(resp); err != nil {
+			return err
+		}
+	}
+	if method.desc.IsStreamingClient() {
+		if err := drainStream(ss, method.input); err != nil {
+			return err
+		}
+	}
+	return r.status.Err()
+}
+
+// drainStream reads requests until the client closes its side.
+func drainStream(ss grpc.ServerStream, input protoreflect.MessageType) error {
+	for {
+		err := ss.RecvMsg(input.New().Interface())
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// peekedStream replays the request an interceptor read before the handler.
+type peekedStream struct {
+	grpc.ServerStream
+	msg      proto.Message
+	err      error
+	replayed bool
+}
+
+func (s *peekedStream) RecvMsg(m any) error {
+	if s.replayed {
+		return s.ServerStream.RecvMsg(m)
+	}
+	s.replayed = true
+	if s.err != nil {
+		return s.err
+	}
+	data, err := proto.Marshal(s.msg)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, m.(proto.Message))
+}
+
+// mockTypes finds compiled-in message types first, then dynamic ones.
+type mockTypes struct {
+	dynamic *dynamicpb.Types
+}
+
+func (t mockTypes) messageType(md protoreflect.MessageDescriptor) protoreflect.MessageType {
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName()); err == nil {
+		return mt
+	}
+	return dynamicpb.NewMessageType(md)
+}
+
+func (t mockTypes) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(name)
+	if err != nil && t.dynamic != nil {
+		return t.dynamic.FindMessageByName(name)
+	}
+	return mt, err
+}
+
+func (t mockTypes) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(url)
+	if err != nil && t.dynamic != nil {
+		return t.dynamic.FindMessageByURL(url)
+	}
+	return mt, err
+}
+
+func (t mockTypes) FindExtensionByName(name protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	xt, err := protoregistry.GlobalTypes.FindExtensionByName(name)
+	if err != nil && t.dynamic != nil {
+		return t.dynamic.FindExtensionByName(name)
+	}
+	return xt, err
+}
+
+func (t mockTypes) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	xt, err := protoregistry.GlobalTypes.FindExtensionByNumber(message, field)
+	if err != nil && t.dynamic != nil {
+		return t.dynamic.FindExtensionByNumber(message, field)
+	}
+	return xt, err
+}
@@ -0,0 +1,302 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+const mockRulesYAML = `rules:
+  - method: echo.v1.Echo/Echo
+    request:
+      message: hello
+    metadata:
+      x-user: alice
+    headers:
+      x-mock: "true"
+    trailers:
+      x-mock-rule: greet
+    response:
+      message: mocked hello
+  - method: /echo.v1.Echo/Echo
+    request:
+      message: {regex: "^missing-"}
+    status:
+      code: NOT_FOUND
+      message: no such message
+      details:
+        - "@type": type.googleapis.com/google.rpc.ErrorInfo
+          reason: MESSAGE_NOT_FOUND
+          domain: echo.example.com
+  - method: echo.v1.Echo/EchoWithDelay
+    delay: 1s
+  - method: echo.v1.Echo/ServerStream
+    request:
+      count: 2
+      intervalMs: 0
+    responses:
+      - message: first
+      - message: second
+    status:
+      code: 14
+      message: stream interrupted
+  - method: echo.v1.Echo/ClientStream
+    request:
+      message: mock
+      failure.after: 0
+    response:
+      message: mocked stream
+`
+
+func setupMockTestServer(t *testing.T, rulesYAML string) pb.EchoClient {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(rulesYAML), 0o600); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+	rules, err := LoadMockRules(path, nil)
+	if err != nil {
+		t.Fatalf("LoadMockRules failed: %v", err)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(rules.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(rules.StreamInterceptor()),
+	)
+	pb.RegisterEchoServer(s, NewEchoServer())
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		s.Stop()
+	})
+	return pb.NewEchoClient(conn)
+}
+
+func TestMock_UnaryResponse(t *testing.T) {
+	client := setupMockTestServer(t, mockRulesYAML)
+
+	tests := []struct {
+		name     string
+		message  string
+		user     string
+		expected string
+		mocked   bool
+	}{
+		{name: "matching rule", message: "hello", user: "alice", expected: "mocked hello", mocked: true},
+		{name: "metadata mismatch", message: "hello", user: "bob", expected: "hello"},
+		{name: "request mismatch", message: "bye", user: "alice", expected: "bye"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user", tt.user)
+			var header, trailer metadata.MD
+			resp, err := client.Echo(ctx, &pb.EchoRequest{Message: tt.message}, grpc.Header(&header), grpc.Trailer(&trailer))
+			if err != nil {
+				t.Fatalf("Echo failed: %v", err)
+			}
+			if resp.Message != tt.expected {
+				t.Errorf("expected message %q, got %q", tt.expected, resp.Message)
+			}
+
+			if tt.mocked {
+				if got := header.Get("x-mock"); len(got) != 1 || got[0] != "true" {
+					t.Errorf("expected header x-mock, got %v", header)
+				}
+				if got := trailer.Get("x-mock-rule"); len(got) != 1 || got[0] != "greet" {
+					t.Errorf("expected trailer x-mock-rule, got %v", trailer)
+				}
+			} else if len(header.Get("x-mock")) != 0 {
+				t.Errorf("expected no x-mock header, got %v", header)
+			}
+		})
+	}
+}
+
+func TestMock_UnaryStatus(t *testing.T) {
+	client := setupMockTestServer(t, mockRulesYAML)
+
+	_, err := client.Echo(context.Background(), &pb.EchoRequest{Message: "missing-42"})
+	st := status.Convert(err)
+	if st.Code() != codes.NotFound || st.Message() != "no such message" {
+		t.Fatalf("expected NotFound 'no such message', got %v", err)
+	}
+
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("expected 1 detail, got %d", len(details))
+	}
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("expected ErrorInfo, got %T", details[0])
+	}
+	if info.Reason != "MESSAGE_NOT_FOUND" || info.Domain != "echo.example.com" {
+		t.Errorf("unexpected ErrorInfo: %v", info)
+	}
+}
+
+func TestMock_Delay(t *testing.T) {
+	client := setupMockTestServer(t, mockRulesYAML)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.EchoWithDelay(ctx, &pb.EchoWithDelayRequest{Message: "hello"})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestMock_ServerStream(t *testing.T) {
+	client := setupMockTestServer(t, mockRulesYAML)
+
+	stream, err := client.ServerStream(context.Background(), &pb.ServerStreamRequest{Message: "hello", Count: 2})
+	if err != nil {
+		t.Fatalf("ServerStream failed: %v", err)
+	}
+
+	var messages []string
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if status.Code(err) != codes.Unavailable {
+				t.Errorf("expected Unavailable, got %v", err)
+			}
+			break
+		}
+		messages = append(messages, resp.Message)
+	}
+	if strings.Join(messages, ",") != "first,second" {
+		t.Errorf("expected first,second, got %v", messages)
+	}
+}
+
+func TestMock_ClientStream(t *testing.T) {
+	client := setupMockTestServer(t, mockRulesYAML)
+
+	tests := []struct {
+		name     string
+		messages []string
+		expected string
+	}{
+		{name: "first request matches", messages: []string{"mock", "a"}, expected: "mocked stream"},
+		{name: "first request replayed", messages: []string{"a", "mock"}, expected: "a, mock"},
+		{name: "no requests", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.ClientStream(context.Background())
+			if err != nil {
+				t.Fatalf("ClientStream failed: %v", err)
+			}
+			for _, message := range tt.messages {
+				if err := stream.Send(&pb.EchoRequest{Message: message}); err != nil && !errors.Is(err, io.EOF) {
+					t.Fatalf("Send failed: %v", err)
+				}
+			}
+
+			resp, err := stream.CloseAndRecv()
+			if err != nil {
+				t.Fatalf("CloseAndRecv failed: %v", err)
+			}
+			if resp.Message != tt.expected {
+				t.Errorf("expected message %q, got %q", tt.expected, resp.Message)
+			}
+		})
+	}
+}
+
+func TestLoadMockRules_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{name: "invalid yaml", rules: "rules: [\n"},
+		{name: "unknown key", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    responce: {}\n"},
+		{name: "unknown service", rules: "rules:\n  - method: echo.v1.Missing/Echo\n"},
+		{name: "unknown method", rules: "rules:\n  - method: echo.v1.Echo/Missing\n"},
+		{name: "unknown field", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    request: {missing: x}\n"},
+		{name: "message field", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    request: {failure: x}\n"},
+		{name: "object matcher", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    request: {message: {equals: {a: b}}}\n"},
+		{name: "invalid regex", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    request: {message: {regex: \"(\"}}\n"},
+		{name: "invalid response", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    response: {missing: x}\n"},
+		{name: "unary responses", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    responses: [{}, {}]\n"},
+		{name: "invalid delay", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    delay: soon\n"},
+		{name: "unknown code", rules: "rules:\n  - method: echo.v1.Echo/Echo\n    status: {code: BROKEN}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.yaml")
+			if err := os.WriteFile(path, []byte(tt.rules), 0o600); err != nil {
+				t.Fatalf("failed to write rules: %v", err)
+			}
+			if _, err := LoadMockRules(path, nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestMock_DynamicService(t *testing.T) {
+	files := loadGreeter(t)
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	rules := `{"rules": [{"method": "greeter.v1.Greeter/Hello", "request": {"tags.1": "b", "counts.x": 1}, "response": {"greeting": "hi"}}]}`
+	if err := os.WriteFile(path, []byte(rules), 0o600); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+	mock, err := LoadMockRules(path, files)
+	if err != nil {
+		t.Fatalf("LoadMockRules failed: %v", err)
+	}
+	// Dynamic methods are registered as streams, even unary ones
+	conn := setupDynamicTestServer(t, files, grpc.StreamInterceptor(mock.StreamInterceptor()))
+
+	reply := greeterMessage(t, files, "HelloReply")
+	resp := dynamicpb.NewMessage(reply)
+	if err := conn.Invoke(context.Background(), "/greeter.v1.Greeter/Hello", newHelloRequest(t, files, "alice"), resp); err != nil {
+		t.Fatalf("Hello failed: %v", err)
+	}
+	if got := resp.Get(reply.Fields().ByName("greeting")).String(); got != "hi" {
+		t.Errorf("expected greeting 'hi', got %q", got)
+	}
+	// The mocked response replaces the dynamic one
+	if resp.Has(reply.Fields().ByName("name")) {
+		t.Errorf("expected name to be unset, got %v", resp)
+	}
+}
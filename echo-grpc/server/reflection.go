@@ -25,18 +25,25 @@ import (
 //
 // The disableV1 and disableV1Alpha flags allow selective disabling of specific
 // reflection API versions for compatibility testing.
-func RegisterReflection(s *grpc.Server, includeDeps, disableV1, disableV1Alpha bool) {
+//
+// Descriptors are looked up in files, which may be nil, after the ones
+// compiled into the server, so dynamically served services can be reflected.
+func RegisterReflection(s *grpc.Server, files *protoregistry.Files, includeDeps, disableV1, disableV1Alpha bool) {
 	if disableV1 && disableV1Alpha {
 		// Both versions disabled, skip registration
 		return
 	}
 
+	desc := descriptorResolver(files)
+
 	if includeDeps {
-		reflection.Register(s)
+		opts := reflection.ServerOptions{Services: s, DescriptorResolver: desc}
+		reflectionv1.RegisterServerReflectionServer(s, reflection.NewServerV1(opts))
+		reflectionv1alpha.RegisterServerReflectionServer(s, reflection.NewServer(opts))
 		return
 	}
 
-	svr := newReflectionServer(s, includeDeps)
+	svr := newReflectionServer(s, desc, includeDeps)
 
 	if !disableV1 {
 		reflectionv1.RegisterServerReflectionServer(s, svr)
@@ -60,15 +67,22 @@ type extensionResolver interface {
 	RangeExtensionsByMessage(message protoreflect.FullName, f func(protoreflect.ExtensionType) bool)
 }
 
-func newReflectionServer(s *grpc.Server, includeDeps bool) *reflectionServer {
+func newReflectionServer(s *grpc.Server, desc protodesc.Resolver, includeDeps bool) *reflectionServer {
 	return &reflectionServer{
 		includeDeps: includeDeps,
 		services:    s.GetServiceInfo(),
-		desc:        protoregistry.GlobalFiles,
+		desc:        desc,
 		ext:         protoregistry.GlobalTypes,
 	}
 }
 
+func descriptorResolver(files *protoregistry.Files) protodesc.Resolver {
+	if files == nil {
+		return protoregistry.GlobalFiles
+	}
+	return combinedResolver{protoregistry.GlobalFiles, files}
+}
+
 func (s *reflectionServer) ServerReflectionInfo(stream reflectionv1.ServerReflection_ServerReflectionInfoServer) error {
 	sent := make(map[string]bool)
 
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"strconv"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// tlsMetadata describes the TLS connection of the calling peer as response
+// metadata entries. It returns nil for plaintext connections.
+func tlsMetadata(ctx context.Context) map[string]string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+
+	state := info.State
+	md := map[string]string{
+		"x-tls-version":             tls.VersionName(state.Version),
+		"x-tls-cipher-suite":        tls.CipherSuiteName(state.CipherSuite),
+		"x-tls-server-name":         state.ServerName,
+		"x-tls-negotiated-protocol": state.NegotiatedProtocol,
+		"x-tls-peer-verified":       strconv.FormatBool(len(state.VerifiedChains) > 0),
+	}
+
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		fingerprint := sha256.Sum256(cert.Raw)
+		md["x-tls-peer-subject"] = cert.Subject.String()
+		md["x-tls-peer-issuer"] = cert.Issuer.String()
+		md["x-tls-peer-serial"] = cert.SerialNumber.String()
+		md["x-tls-peer-fingerprint-sha256"] = hex.EncodeToString(fingerprint[:])
+	}
+
+	return md
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+func newTestCertificate(t *testing.T, commonName string, usage x509.ExtKeyUsage) (tls.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{usage},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, cert
+}
+
+func TestEcho_IncludesTLSPeerMetadata(t *testing.T) {
+	serverCert, serverX509 := newTestCertificate(t, "echo.local", x509.ExtKeyUsageServerAuth)
+	clientCert, clientX509 := newTestCertificate(t, "echo-client", x509.ExtKeyUsageClientAuth)
+
+	serverRoots := x509.NewCertPool()
+	serverRoots.AddCert(serverX509)
+	clientRoots := x509.NewCertPool()
+	clientRoots.AddCert(clientX509)
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientRoots,
+	})))
+	pb.RegisterEchoServer(s, NewEchoServer())
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{clientCert},
+			RootCAs:      serverRoots,
+			ServerName:   "echo.local",
+		})),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	resp, err := pb.NewEchoClient(conn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+	if err != nil {
+		t.Fatalf("Echo failed: %v", err)
+	}
+
+	if resp.Metadata["x-tls-version"] != "TLS 1.3" {
+		t.Errorf("expected x-tls-version=%q, got %q", "TLS 1.3", resp.Metadata["x-tls-version"])
+	}
+	if resp.Metadata["x-tls-server-name"] != "echo.local" {
+		t.Errorf("expected x-tls-server-name=%q, got %q", "echo.local", resp.Metadata["x-tls-server-name"])
+	}
+	if resp.Metadata["x-tls-negotiated-protocol"] != "h2" {
+		t.Errorf("expected x-tls-negotiated-protocol=%q, got %q", "h2", resp.Metadata["x-tls-negotiated-protocol"])
+	}
+	if resp.Metadata["x-tls-peer-verified"] != "true" {
+		t.Errorf("expected x-tls-peer-verified=%q, got %q", "true", resp.Metadata["x-tls-peer-verified"])
+	}
+	if resp.Metadata["x-tls-peer-subject"] != "CN=echo-client" {
+		t.Errorf("expected x-tls-peer-subject=%q, got %q", "CN=echo-client", resp.Metadata["x-tls-peer-subject"])
+	}
+}
+
+func TestEcho_PlaintextOmitsTLSMetadata(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	resp, err := client.Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+	if err != nil {
+		t.Fatalf("Echo failed: %v", err)
+	}
+
+	if _, ok := resp.Metadata["x-tls-version"]; ok {
+		t.Error("expected no TLS metadata on plaintext connection")
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+// setWireStatus validates wire and asks NewHTTPHandler to rewrite the status
+// of the call as wire describes. raw_code needs no rewriting.
+func setWireStatus(ctx context.Context, wire *pb.WireStatus) error {
+	if wire.HttpStatus != 0 && (wire.HttpStatus < 200 || wire.HttpStatus > 599) {
+		return status.Error(codes.InvalidArgument, "wire.http_status must be between 200 and 599")
+	}
+	if strings.ContainsFunc(wire.GrpcStatus, func(r rune) bool { return r < ' ' || r == 0x7f }) {
+		return status.Error(codes.InvalidArgument, "wire.grpc_status must not contain control characters")
+	}
+	if wire.GrpcStatus == "" && !wire.OmitGrpcStatus && !wire.RawMessage && wire.HttpStatus == 0 {
+		return nil
+	}
+
+	call, ok := httpCallFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unimplemented, "wire.grpc_status, wire.omit_grpc_status, wire.raw_message and wire.http_status need a listener with transport=http")
+	}
+	call.wire.Store(wire)
+	return nil
+}
+
+// wireStatusWriter applies the WireStatus of the call, if any, to the
+// response written by grpc.Server.ServeHTTP. Its methods are only called
+// from the goroutine running ServeHTTP.
+type wireStatusWriter struct {
+	http.ResponseWriter
+	call *httpCall
+
+	wroteHeader bool
+	discard     bool // the response was replaced by a plain HTTP status
+}
+
+func (w *wireStatusWriter) WriteHeader(code int) {
+	if w.prepare() {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *wireStatusWriter) Write(p []byte) (int, error) {
+	if !w.prepare() {
+		return len(p), nil
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush is required by grpc.Server.ServeHTTP.
+func (w *wireStatusWriter) Flush() {
+	if w.prepare() {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+func (w *wireStatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// prepare replaces the response by a plain HTTP status before the headers
+// are sent, if the WireStatus asks for it, and reports whether grpc-go's
+// output should still be written.
+func (w *wireStatusWriter) prepare() bool {
+	if w.discard {
+		return false
+	}
+	if w.wroteHeader {
+		return true
+	}
+	w.wroteHeader = true
+
+	wire := w.call.wire.Load()
+	if wire == nil || wire.HttpStatus == 0 {
+		return true
+	}
+	w.discard = true
+	header := w.ResponseWriter.Header()
+	for key := range header {
+		delete(header, key)
+	}
+	code := int(wire.HttpStatus)
+	http.Error(w.ResponseWriter, http.StatusText(code), code)
+	return false
+}
+
+// finish applies the WireStatus to the trailers, which grpc-go sets once the
+// headers are sent and net/http only writes when the handler returns.
+func (w *wireStatusWriter) finish() {
+	wire := w.call.wire.Load()
+	if wire == nil {
+		return
+	}
+	header := w.ResponseWriter.Header()
+	if w.discard {
+		// Trailers set with http.TrailerPrefix are sent whatever the status
+		for key := range header {
+			if strings.HasPrefix(key, http.TrailerPrefix) {
+				delete(header, key)
+			}
+		}
+		return
+	}
+	if wire.OmitGrpcStatus {
+		delete(header, "Grpc-Status")
+	} else if wire.GrpcStatus != "" {
+		header["Grpc-Status"] = []string{wire.GrpcStatus}
+	}
+	if wire.RawMessage && len(header["Grpc-Message"]) > 0 {
+		header["Grpc-Message"] = []string{percentDecode(header["Grpc-Message"][0])}
+	}
+}
+
+// percentDecode undoes the percent-encoding of grpc-message values.
+func percentDecode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/jsr-probitas/echo-servers/echo-grpc/proto"
+)
+
+func TestEchoError_WireStatus(t *testing.T) {
+	client := setupHTTPTestServer(t)
+
+	tests := []struct {
+		name        string
+		code        int32
+		details     string
+		wire        *pb.WireStatus
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{
+			name:        "clamped code",
+			code:        42,
+			details:     "unknown code",
+			wantCode:    codes.Unknown,
+			wantMessage: "unknown code",
+		},
+		{
+			name:        "raw code",
+			code:        42,
+			details:     "unknown code",
+			wire:        &pb.WireStatus{RawCode: true},
+			wantCode:    codes.Code(42),
+			wantMessage: "unknown code",
+		},
+		{
+			name:        "non-numeric grpc-status",
+			code:        int32(codes.NotFound),
+			wire:        &pb.WireStatus{GrpcStatus: "NOT_FOUND"},
+			wantCode:    codes.Internal,
+			wantMessage: "malformed grpc-status",
+		},
+		{
+			name:     "missing grpc-status",
+			code:     int32(codes.NotFound),
+			wire:     &pb.WireStatus{OmitGrpcStatus: true},
+			wantCode: codes.Unknown,
+		},
+		{
+			name:        "percent-encoded message",
+			code:        int32(codes.NotFound),
+			details:     "50%25 off",
+			wantCode:    codes.NotFound,
+			wantMessage: "50%25 off",
+		},
+		{
+			name:        "raw message",
+			code:        int32(codes.NotFound),
+			details:     "50%25 off",
+			wire:        &pb.WireStatus{RawMessage: true},
+			wantCode:    codes.NotFound,
+			wantMessage: "50% off",
+		},
+		{
+			name:        "raw message with invalid UTF-8",
+			code:        int32(codes.NotFound),
+			details:     "bad %FF",
+			wire:        &pb.WireStatus{RawMessage: true},
+			wantCode:    codes.NotFound,
+			wantMessage: "bad \xff",
+		},
+		{
+			name:        "http status",
+			code:        int32(codes.NotFound),
+			wire:        &pb.WireStatus{HttpStatus: 503},
+			wantCode:    codes.Unavailable,
+			wantMessage: "503",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.EchoError(context.Background(), &pb.EchoErrorRequest{
+				Code:    tt.code,
+				Details: tt.details,
+				Wire:    tt.wire,
+			})
+
+			st := status.Convert(err)
+			if st.Code() != tt.wantCode {
+				t.Fatalf("expected code %v, got %v", tt.wantCode, err)
+			}
+			if !strings.Contains(st.Message(), tt.wantMessage) {
+				t.Errorf("expected message containing %q, got %q", tt.wantMessage, st.Message())
+			}
+		})
+	}
+}
+
+func TestEchoError_WireStatusInvalid(t *testing.T) {
+	client := setupHTTPTestServer(t)
+
+	for _, wire := range []*pb.WireStatus{{HttpStatus: 99}, {HttpStatus: 600}, {GrpcStatus: "5\n"}} {
+		_, err := client.EchoError(context.Background(), &pb.EchoErrorRequest{Code: 5, Wire: wire})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for %v, got %v", wire, err)
+		}
+	}
+}
+
+// TestEchoError_WireStatusNeedsHTTPTransport checks that grpc-go's own
+// transport, which always writes well-formed statuses, still sends raw codes
+// and rejects the rest.
+func TestEchoError_WireStatusNeedsHTTPTransport(t *testing.T) {
+	client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	_, err := client.EchoError(context.Background(), &pb.EchoErrorRequest{Code: 42, Wire: &pb.WireStatus{RawCode: true}})
+	if status.Code(err) != codes.Code(42) {
+		t.Errorf("expected code 42, got %v", err)
+	}
+
+	for _, wire := range []*pb.WireStatus{{GrpcStatus: "NOT_FOUND"}, {OmitGrpcStatus: true}, {RawMessage: true}, {HttpStatus: 503}} {
+		_, err := client.EchoError(context.Background(), &pb.EchoErrorRequest{Code: 5, Wire: wire})
+		if status.Code(err) != codes.Unimplemented {
+			t.Errorf("expected Unimplemented for %v, got %v", wire, err)
+		}
+	}
+}
@@ -1,14 +1,34 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"github.com/jsr-probitas/echo-servers/echo-http/handlers"
 )
 
 type Config struct {
-	Host string
-	Port string
+	Host                  string
+	Port                  string
+	Listen                string
+	TLSCertFile           string
+	TLSKeyFile            string
+	TLSClientCAFile       string
+	TLSClientAuth         string
+	TLSSelfSigned         bool
+	TLSSelfSignedDir      string
+	TLSSelfSignedHosts    string
+	RequestHistorySize    int
+	RequestHistoryMaxBody int
+	ChaosConfigFile       string
+	ChaosRules            string
+	ShutdownDrainPeriod   time.Duration
+	ShutdownTimeout       time.Duration
+	AccessLog             bool
 }
 
 func LoadConfig() *Config {
@@ -16,8 +36,23 @@ func LoadConfig() *Config {
 	_ = godotenv.Load()
 
 	return &Config{
-		Host: getEnv("HOST", "0.0.0.0"),
-		Port: getEnv("PORT", "80"),
+		Host:                  getEnv("HOST", "0.0.0.0"),
+		Port:                  getEnv("PORT", "80"),
+		Listen:                getEnv("LISTEN", ""),
+		TLSCertFile:           getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:            getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:       getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:         getEnv("TLS_CLIENT_AUTH", ""),
+		TLSSelfSigned:         getEnvBool("TLS_SELF_SIGNED", false),
+		TLSSelfSignedDir:      getEnv("TLS_SELF_SIGNED_DIR", "tls"),
+		TLSSelfSignedHosts:    getEnv("TLS_SELF_SIGNED_HOSTS", ""),
+		RequestHistorySize:    getEnvInt("REQUEST_HISTORY_SIZE", 100),
+		RequestHistoryMaxBody: getEnvInt("REQUEST_HISTORY_MAX_BODY", 64*1024),
+		ChaosConfigFile:       getEnv("CHAOS_CONFIG_FILE", ""),
+		ChaosRules:            getEnv("CHAOS_RULES", ""),
+		ShutdownDrainPeriod:   getEnvDuration("SHUTDOWN_DRAIN_PERIOD", 0),
+		ShutdownTimeout:       getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		AccessLog:             getEnvBool("ACCESS_LOG", true),
 	}
 }
 
@@ -25,9 +60,68 @@ func (c *Config) Addr() string {
 	return c.Host + ":" + c.Port
 }
 
+// LoadChaosRules reads the initial fault injection rules from CHAOS_CONFIG_FILE
+// or, if unset, from the inline CHAOS_RULES JSON.
+func (c *Config) LoadChaosRules() ([]handlers.ChaosRule, error) {
+	data := []byte(c.ChaosRules)
+	if c.ChaosConfigFile != "" {
+		var err error
+		data, err = os.ReadFile(c.ChaosConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("read chaos config: %w", err)
+		}
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return handlers.ParseChaosConfig(data)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	switch value {
+	case "1", "true", "TRUE", "True", "yes", "YES", "on", "ON":
+		return true
+	case "0", "false", "FALSE", "False", "no", "NO", "off", "OFF":
+		return false
+	default:
+		return defaultValue
+	}
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return defaultValue
+	}
+	return parsed
+}
@@ -17,6 +17,7 @@ type AnythingResponse struct {
 	JSON    any               `json:"json,omitempty"`
 	Form    map[string]string `json:"form,omitempty"`
 	Files   map[string]string `json:"files,omitempty"`
+	TLS     *TLSInfo          `json:"tls,omitempty"`
 }
 
 // AnythingHandler echoes any request information.
@@ -29,6 +30,7 @@ func AnythingHandler(w http.ResponseWriter, r *http.Request) {
 		Args:    make(map[string]string),
 		Headers: make(map[string]string),
 		Origin:  getClientIP(r),
+		TLS:     getTLSInfo(r),
 	}
 
 	for key, values := range r.URL.Query() {
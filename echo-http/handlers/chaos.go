@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxChaosLatencyMs = 30000
+
+// ChaosRule describes a fault injected into requests matching Path and Methods.
+//
+// A rule applies to every matching request when Probability is 0 or 1, to a
+// random fraction of them otherwise, or only to the first FailFirst matching
+// requests when FailFirst is set. Latency is added before the response; Status
+// replaces the handler response; Reset and Truncate cut the response body.
+type ChaosRule struct {
+	Path        string   `json:"path"`
+	Methods     []string `json:"methods,omitempty"`
+	Probability float64  `json:"probability,omitempty"`
+	FailFirst   int      `json:"fail_first,omitempty"`
+	Status      int      `json:"status,omitempty"`
+	LatencyMs   int      `json:"latency_ms,omitempty"`
+	JitterMs    int      `json:"jitter_ms,omitempty"`
+	Reset       bool     `json:"reset,omitempty"`
+	Truncate    bool     `json:"truncate,omitempty"`
+}
+
+type ChaosRuleState struct {
+	ChaosRule
+	Matched  int64 `json:"matched"`
+	Injected int64 `json:"injected"`
+}
+
+type ChaosConfig struct {
+	Rules []ChaosRule `json:"rules"`
+}
+
+type ChaosRulesResponse struct {
+	Rules []ChaosRuleState `json:"rules"`
+}
+
+type ChaosErrorResponse struct {
+	Error  string `json:"error"`
+	Status int    `json:"status"`
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// Validate reports whether the rule can be applied.
+func (rule ChaosRule) Validate() error {
+	if rule.Path == "" {
+		return errors.New("path is required")
+	}
+	if _, err := path.Match(rule.Path, "/"); err != nil {
+		return fmt.Errorf("invalid path pattern %q", rule.Path)
+	}
+	if rule.Probability < 0 || rule.Probability > 1 {
+		return errors.New("probability must be between 0 and 1")
+	}
+	if rule.FailFirst < 0 {
+		return errors.New("fail_first must not be negative")
+	}
+	if rule.Status != 0 && (rule.Status < 100 || rule.Status > 599) {
+		return errors.New("status must be between 100 and 599")
+	}
+	if rule.LatencyMs < 0 || rule.JitterMs < 0 {
+		return errors.New("latency_ms and jitter_ms must not be negative")
+	}
+	if rule.LatencyMs+rule.JitterMs > maxChaosLatencyMs {
+		return fmt.Errorf("latency_ms + jitter_ms must not exceed %d", maxChaosLatencyMs)
+	}
+	if rule.Reset && rule.Truncate {
+		return errors.New("reset and truncate are mutually exclusive")
+	}
+	return nil
+}
+
+func (rule ChaosRule) matches(r *http.Request) bool {
+	if len(rule.Methods) > 0 {
+		found := false
+		for _, m := range rule.Methods {
+			if strings.EqualFold(m, r.Method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if rule.Path == r.URL.Path {
+		return true
+	}
+	ok, _ := path.Match(rule.Path, r.URL.Path)
+	return ok
+}
+
+// ParseChaosConfig parses and validates a JSON chaos configuration of the
+// form {"rules": [...]}.
+func ParseChaosConfig(data []byte) ([]ChaosRule, error) {
+	var config ChaosConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid chaos config: %w", err)
+	}
+	for i, rule := range config.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid chaos rule %d: %w", i, err)
+		}
+	}
+	return config.Rules, nil
+}
+
+// Chaos injects faults into requests according to a mutable set of rules.
+type Chaos struct {
+	mu    sync.Mutex
+	rules []*ChaosRuleState
+}
+
+// NewChaos creates a Chaos middleware with the given rules.
+func NewChaos(rules []ChaosRule) *Chaos {
+	c := &Chaos{}
+	c.SetRules(rules)
+	return c
+}
+
+// SetRules replaces all rules and resets their counters.
+func (c *Chaos) SetRules(rules []ChaosRule) {
+	states := make([]*ChaosRuleState, len(rules))
+	for i, rule := range rules {
+		states[i] = &ChaosRuleState{ChaosRule: rule}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = states
+}
+
+// Rules returns the current rules with their counters.
+func (c *Chaos) Rules() []ChaosRuleState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make([]ChaosRuleState, len(c.rules))
+	for i, state := range c.rules {
+		result[i] = *state
+	}
+	return result
+}
+
+// pick returns the first rule matching r, or nil when r should be served
+// normally. Only the first matching rule is considered.
+func (c *Chaos) pick(r *http.Request) *ChaosRule {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, state := range c.rules {
+		if !state.matches(r) {
+			continue
+		}
+
+		state.Matched++
+		switch {
+		case state.FailFirst > 0:
+			if state.Matched > int64(state.FailFirst) {
+				return nil
+			}
+		case state.Probability > 0 && state.Probability < 1:
+			if rand.Float64() >= state.Probability {
+				return nil
+			}
+		}
+		state.Injected++
+
+		rule := state.ChaosRule
+		return &rule
+	}
+	return nil
+}
+
+// Middleware injects faults into requests matching the configured rules.
+// Admin endpoints, /metrics and /health are never affected.
+func (c *Chaos) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isAdminPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rule := c.pick(r)
+		if rule == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		latency := time.Duration(rule.LatencyMs) * time.Millisecond
+		if rule.JitterMs > 0 {
+			latency += time.Duration(rand.IntN(rule.JitterMs+1)) * time.Millisecond
+		}
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		handler := next
+		if rule.Status != 0 {
+			handler = chaosStatusHandler(rule.Status)
+		}
+
+		if !rule.Reset && !rule.Truncate {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		buf := newBufferedResponseWriter()
+		handler.ServeHTTP(buf, r)
+		writeCutResponse(w, buf, rule.Reset)
+	})
+}
+
+// ListHandler returns the active rules and their counters.
+// GET /_chaos
+func (c *Chaos) ListHandler(w http.ResponseWriter, r *http.Request) {
+	rules := c.Rules()
+
+	response := ChaosRulesResponse{
+		Rules: rules,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// ReplaceHandler replaces all rules with those in the request body.
+// PUT /_chaos {"rules": [...]}
+func (c *Chaos) ReplaceHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	rules, err := ParseChaosConfig(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.SetRules(rules)
+
+	c.ListHandler(w, r)
+}
+
+// AddHandler appends a single rule from the request body.
+// POST /_chaos {"path": "/get", "status": 503}
+func (c *Chaos) AddHandler(w http.ResponseWriter, r *http.Request) {
+	var rule ChaosRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, "Invalid chaos rule", http.StatusBadRequest)
+		return
+	}
+	if err := rule.Validate(); err != nil {
+		http.Error(w, "Invalid chaos rule: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	c.rules = append(c.rules, &ChaosRuleState{ChaosRule: rule})
+	c.mu.Unlock()
+
+	c.ListHandler(w, r)
+}
+
+// ClearHandler removes all rules.
+// DELETE /_chaos
+func (c *Chaos) ClearHandler(w http.ResponseWriter, r *http.Request) {
+	c.SetRules(nil)
+
+	c.ListHandler(w, r)
+}
+
+func chaosStatusHandler(status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := ChaosErrorResponse{
+			Error:  "chaos: injected fault",
+			Status: status,
+			Method: r.Method,
+			URL:    r.URL.RequestURI(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(response)
+	})
+}
+
+// writeCutResponse sends the buffered response with only half of its body.
+// The Content-Length still announces the full body. With reset the connection
+// is then aborted with a TCP RST (or a stream reset over HTTP/2); otherwise the
+// handler returns normally and the server closes the connection.
+func writeCutResponse(w http.ResponseWriter, buf *bufferedResponseWriter, reset bool) {
+	body := buf.body.Bytes()
+
+	for key, values := range buf.header {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Del("Transfer-Encoding")
+	w.WriteHeader(buf.status)
+	_, _ = w.Write(body[:len(body)/2])
+
+	rc := http.NewResponseController(w)
+	_ = rc.Flush()
+
+	if !reset {
+		return
+	}
+
+	conn, _, err := rc.Hijack()
+	if err != nil {
+		// HTTP/2 connections cannot be hijacked; aborting the handler makes
+		// the server reset the stream instead.
+		panic(http.ErrAbortHandler)
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
+// bufferedResponseWriter collects a complete response so it can be replayed
+// partially.
+type bufferedResponseWriter struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func newBufferedResponseWriter() *bufferedResponseWriter {
+	return &bufferedResponseWriter{
+		header: make(http.Header),
+		status: http.StatusOK,
+	}
+}
+
+func (b *bufferedResponseWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	if b.wroteHeader {
+		return
+	}
+	b.wroteHeader = true
+	b.status = status
+}
+
+// Flush is a no-op so streaming handlers can run against the buffer.
+func (b *bufferedResponseWriter) Flush() {}
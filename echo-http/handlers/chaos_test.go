@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"message":"hello world"}`))
+}
+
+func TestChaos_Middleware(t *testing.T) {
+	t.Run("injects status on matching path", func(t *testing.T) {
+		chaos := NewChaos([]ChaosRule{{Path: "/anything/*", Status: http.StatusServiceUnavailable}})
+		handler := chaos.Middleware(http.HandlerFunc(okHandler))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/anything/foo", nil))
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", rec.Code)
+		}
+
+		var resp ChaosErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.URL != "/anything/foo" {
+			t.Errorf("expected url /anything/foo, got %s", resp.URL)
+		}
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/get", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status 200 for non-matching path, got %d", rec.Code)
+		}
+	})
+
+	t.Run("respects methods", func(t *testing.T) {
+		chaos := NewChaos([]ChaosRule{{Path: "/post", Methods: []string{"post"}, Status: http.StatusInternalServerError}})
+		handler := chaos.Middleware(http.HandlerFunc(okHandler))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/post", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status 200 for GET, got %d", rec.Code)
+		}
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/post", nil))
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("expected status 500 for POST, got %d", rec.Code)
+		}
+	})
+
+	t.Run("fails first n requests then succeeds", func(t *testing.T) {
+		chaos := NewChaos([]ChaosRule{{Path: "/get", FailFirst: 2, Status: http.StatusServiceUnavailable}})
+		handler := chaos.Middleware(http.HandlerFunc(okHandler))
+
+		expected := []int{503, 503, 200, 200}
+		for i, want := range expected {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/get", nil))
+			if rec.Code != want {
+				t.Errorf("request %d: expected status %d, got %d", i+1, want, rec.Code)
+			}
+		}
+
+		rules := chaos.Rules()
+		if rules[0].Matched != 4 || rules[0].Injected != 2 {
+			t.Errorf("expected matched=4 injected=2, got matched=%d injected=%d", rules[0].Matched, rules[0].Injected)
+		}
+	})
+
+	t.Run("adds latency", func(t *testing.T) {
+		chaos := NewChaos([]ChaosRule{{Path: "/get", LatencyMs: 50, JitterMs: 10}})
+		handler := chaos.Middleware(http.HandlerFunc(okHandler))
+
+		start := time.Now()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/get", nil))
+		elapsed := time.Since(start)
+
+		if elapsed < 50*time.Millisecond {
+			t.Errorf("expected at least 50ms latency, got %v", elapsed)
+		}
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", rec.Code)
+		}
+	})
+
+	t.Run("never affects admin endpoints", func(t *testing.T) {
+		chaos := NewChaos([]ChaosRule{{Path: "*", Status: http.StatusInternalServerError}})
+		handler := chaos.Middleware(http.HandlerFunc(okHandler))
+
+		for _, path := range []string{"/_chaos", "/metrics", "/health"} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("%s: expected status 200, got %d", path, rec.Code)
+			}
+		}
+	})
+}
+
+func TestChaos_CutResponses(t *testing.T) {
+	tests := []struct {
+		name string
+		rule ChaosRule
+	}{
+		{"truncate", ChaosRule{Path: "/get", Truncate: true}},
+		{"reset", ChaosRule{Path: "/get", Reset: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(NewChaos([]ChaosRule{tt.rule}).Middleware(http.HandlerFunc(okHandler)))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/get")
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("expected status 200, got %d", resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err == nil {
+				t.Error("expected error reading cut body")
+			}
+			if !strings.HasPrefix(`{"message":"hello world"}`, string(body)) || len(body) == 0 {
+				t.Errorf("expected a prefix of the original body, got %q", body)
+			}
+		})
+	}
+}
+
+func TestChaos_AdminHandlers(t *testing.T) {
+	chaos := NewChaos(nil)
+
+	t.Run("POST adds a rule", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		chaos.AddHandler(rec, httptest.NewRequest(http.MethodPost, "/_chaos", strings.NewReader(`{"path":"/get","status":503}`)))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+		if rules := chaos.Rules(); len(rules) != 1 || rules[0].Status != 503 {
+			t.Errorf("expected one rule with status 503, got %+v", rules)
+		}
+	})
+
+	t.Run("PUT replaces rules", func(t *testing.T) {
+		body := `{"rules":[{"path":"/a","status":500},{"path":"/b","latency_ms":10}]}`
+		rec := httptest.NewRecorder()
+		chaos.ReplaceHandler(rec, httptest.NewRequest(http.MethodPut, "/_chaos", strings.NewReader(body)))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+
+		var resp ChaosRulesResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(resp.Rules) != 2 || resp.Rules[0].Path != "/a" {
+			t.Errorf("expected rules [/a /b], got %+v", resp.Rules)
+		}
+	})
+
+	t.Run("rejects invalid rules", func(t *testing.T) {
+		invalid := []string{
+			`{"status":503}`,
+			`{"path":"/get","probability":2}`,
+			`{"path":"/get","status":42}`,
+			`{"path":"/get","reset":true,"truncate":true}`,
+			`{"path":"[","status":503}`,
+		}
+
+		for _, body := range invalid {
+			rec := httptest.NewRecorder()
+			chaos.AddHandler(rec, httptest.NewRequest(http.MethodPost, "/_chaos", strings.NewReader(body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400 for %s, got %d", body, rec.Code)
+			}
+		}
+	})
+
+	t.Run("DELETE clears rules", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		chaos.ClearHandler(rec, httptest.NewRequest(http.MethodDelete, "/_chaos", nil))
+
+		if len(chaos.Rules()) != 0 {
+			t.Errorf("expected no rules, got %d", len(chaos.Rules()))
+		}
+	})
+}
@@ -15,6 +15,7 @@ type EchoResponse struct {
 	Data    string            `json:"data,omitempty"`
 	JSON    any               `json:"json,omitempty"`
 	Form    map[string]string `json:"form,omitempty"`
+	TLS     *TLSInfo          `json:"tls,omitempty"`
 }
 
 func EchoHandler(w http.ResponseWriter, r *http.Request) {
@@ -23,6 +24,7 @@ func EchoHandler(w http.ResponseWriter, r *http.Request) {
 		URL:     r.URL.RequestURI(),
 		Args:    make(map[string]string),
 		Headers: make(map[string]string),
+		TLS:     getTLSInfo(r),
 	}
 
 	for key, values := range r.URL.Query() {
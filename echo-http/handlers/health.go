@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Health reports whether the server is accepting traffic. It starts healthy
+// and turns unhealthy once Shutdown is called, so load balancers stop routing
+// to the server while it drains.
+type Health struct {
+	shuttingDown atomic.Bool
+}
+
+// NewHealth creates a Health that reports the server as healthy.
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// Shutdown makes the health check fail from now on.
+func (h *Health) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Handler serves the health check.
+// GET /health - 200 {"status":"ok"}, or 503 {"status":"shutting_down"} while draining
+func (h *Health) Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.shuttingDown.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"status":"shutting_down"}`))
+		return
+	}
+	_, _ = w.Write([]byte(`{"status":"ok"}`))
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthHandler(t *testing.T) {
+	h := NewHealth()
+
+	rec := httptest.NewRecorder()
+	h.Handler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+	if rec.Body.String() != `{"status":"ok"}` {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+
+	h.Shutdown()
+
+	rec = httptest.NewRecorder()
+	h.Handler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 after shutdown, got %d", rec.Code)
+	}
+	if rec.Body.String() != `{"status":"shutting_down"}` {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
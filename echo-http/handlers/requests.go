@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RecordedRequest is a request captured by RequestRecorder.
+type RecordedRequest struct {
+	ID            int64               `json:"id"`
+	Method        string              `json:"method"`
+	URL           string              `json:"url"`
+	Path          string              `json:"path"`
+	Proto         string              `json:"proto"`
+	Host          string              `json:"host"`
+	Headers       map[string][]string `json:"headers"`
+	Body          string              `json:"body,omitempty"`
+	BodyTruncated bool                `json:"body_truncated,omitempty"`
+	RemoteAddr    string              `json:"remote_addr"`
+	Status        int                 `json:"status"`
+	ReceivedAt    time.Time           `json:"received_at"`
+	DurationMs    float64             `json:"duration_ms"`
+}
+
+type RecordedRequestsResponse struct {
+	Count    int               `json:"count"`
+	Requests []RecordedRequest `json:"requests"`
+}
+
+type ClearRequestsResponse struct {
+	Cleared int `json:"cleared"`
+}
+
+// RequestRecorder keeps the most recent requests in a bounded ring buffer so
+// tests can assert on what the server actually received.
+type RequestRecorder struct {
+	mu          sync.Mutex
+	entries     []RecordedRequest
+	start       int
+	count       int
+	nextID      int64
+	maxBodySize int
+}
+
+// NewRequestRecorder creates a recorder that retains up to size requests and
+// captures at most maxBodySize bytes of each request body.
+func NewRequestRecorder(size, maxBodySize int) *RequestRecorder {
+	return &RequestRecorder{
+		entries:     make([]RecordedRequest, size),
+		nextID:      1,
+		maxBodySize: maxBodySize,
+	}
+}
+
+// isAdminPath reports whether path is one of the endpoints for operators
+// rather than for the client under test: the admin endpoints under /_,
+// /metrics and /health. They are neither recorded nor affected by chaos rules.
+func isAdminPath(path string) bool {
+	return strings.HasPrefix(path, "/_") || path == "/metrics" || path == "/health"
+}
+
+// Middleware records every request except the admin endpoints.
+func (rr *RequestRecorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(rr.entries) == 0 || isAdminPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		entry := RecordedRequest{
+			Method:     r.Method,
+			URL:        r.URL.RequestURI(),
+			Path:       r.URL.Path,
+			Proto:      r.Proto,
+			Host:       r.Host,
+			Headers:    r.Header.Clone(),
+			RemoteAddr: r.RemoteAddr,
+			ReceivedAt: time.Now(),
+		}
+
+		// Capture the body up front so it is recorded even if the handler
+		// never reads it, then hand the full body on to the handler.
+		if r.Body != nil && r.Body != http.NoBody {
+			captured, err := io.ReadAll(io.LimitReader(r.Body, int64(rr.maxBodySize)+1))
+			if err == nil {
+				if len(captured) > rr.maxBodySize {
+					entry.BodyTruncated = true
+					entry.Body = string(captured[:rr.maxBodySize])
+				} else {
+					entry.Body = string(captured)
+				}
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(captured), r.Body), r.Body}
+			}
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			entry.Status = ww.Status()
+			if entry.Status == 0 {
+				entry.Status = http.StatusOK
+			}
+			entry.DurationMs = float64(time.Since(entry.ReceivedAt).Microseconds()) / 1000
+			rr.add(entry)
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+func (rr *RequestRecorder) add(entry RecordedRequest) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	entry.ID = rr.nextID
+	rr.nextID++
+
+	if rr.count < len(rr.entries) {
+		rr.entries[(rr.start+rr.count)%len(rr.entries)] = entry
+		rr.count++
+		return
+	}
+	rr.entries[rr.start] = entry
+	rr.start = (rr.start + 1) % len(rr.entries)
+}
+
+// Requests returns the recorded requests, oldest first.
+func (rr *RequestRecorder) Requests() []RecordedRequest {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	result := make([]RecordedRequest, rr.count)
+	for i := range rr.count {
+		result[i] = rr.entries[(rr.start+i)%len(rr.entries)]
+	}
+	return result
+}
+
+// Clear removes all recorded requests and returns how many were removed.
+func (rr *RequestRecorder) Clear() int {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	cleared := rr.count
+	for i := range rr.entries {
+		rr.entries[i] = RecordedRequest{}
+	}
+	rr.start = 0
+	rr.count = 0
+	return cleared
+}
+
+// ListHandler returns recorded requests, optionally filtered.
+// GET /_requests?path={glob}&method={m}&header={name[:value]}&limit={n}
+func (rr *RequestRecorder) ListHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if l := query.Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	pathPattern := query.Get("path")
+	if pathPattern != "" {
+		if _, err := path.Match(pathPattern, "/"); err != nil {
+			http.Error(w, "Invalid path pattern", http.StatusBadRequest)
+			return
+		}
+	}
+	method := query.Get("method")
+	headerFilters := query["header"]
+
+	matched := []RecordedRequest{}
+	for _, entry := range rr.Requests() {
+		if pathPattern != "" {
+			if ok, _ := path.Match(pathPattern, entry.Path); !ok {
+				continue
+			}
+		}
+		if method != "" && !strings.EqualFold(method, entry.Method) {
+			continue
+		}
+		if !matchHeaders(entry.Headers, headerFilters) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+
+	response := RecordedRequestsResponse{
+		Count:    len(matched),
+		Requests: matched,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// ClearHandler removes all recorded requests.
+// DELETE /_requests
+func (rr *RequestRecorder) ClearHandler(w http.ResponseWriter, r *http.Request) {
+	response := ClearRequestsResponse{
+		Cleared: rr.Clear(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// matchHeaders reports whether headers satisfy every filter. A filter is
+// either "Name" (header present) or "Name:value" (any value matches exactly).
+func matchHeaders(headers http.Header, filters []string) bool {
+	for _, filter := range filters {
+		name, value, hasValue := strings.Cut(filter, ":")
+		values := headers.Values(strings.TrimSpace(name))
+		if len(values) == 0 {
+			return false
+		}
+		if !hasValue {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		found := false
+		for _, v := range values {
+			if v == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
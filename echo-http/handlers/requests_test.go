@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func recordRequest(t *testing.T, rr *RequestRecorder, req *http.Request, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	rr.Middleware(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func listRequests(t *testing.T, rr *RequestRecorder, target string) RecordedRequestsResponse {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	rr.ListHandler(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var resp RecordedRequestsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp
+}
+
+func TestRequestRecorder_Middleware(t *testing.T) {
+	t.Run("records request details and status", func(t *testing.T) {
+		rr := NewRequestRecorder(10, 1024)
+
+		req := httptest.NewRequest(http.MethodPost, "/post?foo=bar", strings.NewReader("hello"))
+		req.Header.Add("X-Retry", "1")
+		req.Header.Add("X-Retry", "2")
+		recordRequest(t, rr, req, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		requests := rr.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("expected 1 recorded request, got %d", len(requests))
+		}
+
+		got := requests[0]
+		if got.ID != 1 {
+			t.Errorf("expected id 1, got %d", got.ID)
+		}
+		if got.Method != "POST" {
+			t.Errorf("expected method POST, got %s", got.Method)
+		}
+		if got.URL != "/post?foo=bar" {
+			t.Errorf("expected url /post?foo=bar, got %s", got.URL)
+		}
+		if got.Body != "hello" {
+			t.Errorf("expected body hello, got %q", got.Body)
+		}
+		if got.Status != http.StatusCreated {
+			t.Errorf("expected status 201, got %d", got.Status)
+		}
+		if len(got.Headers["X-Retry"]) != 2 {
+			t.Errorf("expected 2 X-Retry values, got %v", got.Headers["X-Retry"])
+		}
+		if got.ReceivedAt.IsZero() {
+			t.Error("expected received_at to be set")
+		}
+	})
+
+	t.Run("handler still sees the full body", func(t *testing.T) {
+		rr := NewRequestRecorder(10, 4)
+
+		var seen string
+		req := httptest.NewRequest(http.MethodPost, "/post", strings.NewReader("hello world"))
+		recordRequest(t, rr, req, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			seen = string(body)
+		})
+
+		if seen != "hello world" {
+			t.Errorf("expected handler to read full body, got %q", seen)
+		}
+
+		got := rr.Requests()[0]
+		if got.Body != "hell" {
+			t.Errorf("expected truncated body hell, got %q", got.Body)
+		}
+		if !got.BodyTruncated {
+			t.Error("expected body_truncated to be true")
+		}
+	})
+
+	t.Run("skips admin endpoints", func(t *testing.T) {
+		rr := NewRequestRecorder(10, 1024)
+
+		for _, path := range []string{"/_requests", "/metrics", "/health"} {
+			recordRequest(t, rr, httptest.NewRequest(http.MethodGet, path, nil), func(w http.ResponseWriter, r *http.Request) {})
+		}
+
+		if len(rr.Requests()) != 0 {
+			t.Errorf("expected no recorded requests, got %d", len(rr.Requests()))
+		}
+	})
+
+	t.Run("evicts oldest requests when full", func(t *testing.T) {
+		rr := NewRequestRecorder(2, 1024)
+
+		for _, path := range []string{"/a", "/b", "/c"} {
+			recordRequest(t, rr, httptest.NewRequest(http.MethodGet, path, nil), func(w http.ResponseWriter, r *http.Request) {})
+		}
+
+		requests := rr.Requests()
+		if len(requests) != 2 {
+			t.Fatalf("expected 2 recorded requests, got %d", len(requests))
+		}
+		if requests[0].Path != "/b" || requests[1].Path != "/c" {
+			t.Errorf("expected paths [/b /c], got [%s %s]", requests[0].Path, requests[1].Path)
+		}
+		if requests[1].ID != 3 {
+			t.Errorf("expected id 3, got %d", requests[1].ID)
+		}
+	})
+
+	t.Run("size zero disables recording", func(t *testing.T) {
+		rr := NewRequestRecorder(0, 1024)
+
+		recordRequest(t, rr, httptest.NewRequest(http.MethodGet, "/get", nil), func(w http.ResponseWriter, r *http.Request) {})
+
+		if len(rr.Requests()) != 0 {
+			t.Errorf("expected no recorded requests, got %d", len(rr.Requests()))
+		}
+	})
+}
+
+func TestRequestRecorder_ListHandler(t *testing.T) {
+	rr := NewRequestRecorder(10, 1024)
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	recordRequest(t, rr, httptest.NewRequest(http.MethodGet, "/get", nil), noop)
+	post := httptest.NewRequest(http.MethodPost, "/anything/a", nil)
+	post.Header.Set("X-Attempt", "1")
+	recordRequest(t, rr, post, noop)
+	post = httptest.NewRequest(http.MethodPost, "/anything/b", nil)
+	post.Header.Set("X-Attempt", "2")
+	recordRequest(t, rr, post, noop)
+
+	tests := []struct {
+		name     string
+		target   string
+		expected []string
+	}{
+		{"no filters", "/_requests", []string{"/get", "/anything/a", "/anything/b"}},
+		{"exact path", "/_requests?path=/get", []string{"/get"}},
+		{"path glob", "/_requests?path=/anything/*", []string{"/anything/a", "/anything/b"}},
+		{"method", "/_requests?method=post", []string{"/anything/a", "/anything/b"}},
+		{"header present", "/_requests?header=X-Attempt", []string{"/anything/a", "/anything/b"}},
+		{"header value", "/_requests?header=X-Attempt:2", []string{"/anything/b"}},
+		{"limit", "/_requests?limit=1", []string{"/anything/b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := listRequests(t, rr, tt.target)
+
+			if resp.Count != len(tt.expected) {
+				t.Fatalf("expected count %d, got %d", len(tt.expected), resp.Count)
+			}
+			for i, path := range tt.expected {
+				if resp.Requests[i].Path != path {
+					t.Errorf("expected requests[%d].path=%s, got %s", i, path, resp.Requests[i].Path)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid limit", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		rr.ListHandler(rec, httptest.NewRequest(http.MethodGet, "/_requests?limit=abc", nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestRequestRecorder_ClearHandler(t *testing.T) {
+	rr := NewRequestRecorder(10, 1024)
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	recordRequest(t, rr, httptest.NewRequest(http.MethodGet, "/get", nil), noop)
+	recordRequest(t, rr, httptest.NewRequest(http.MethodGet, "/get", nil), noop)
+
+	rec := httptest.NewRecorder()
+	rr.ClearHandler(rec, httptest.NewRequest(http.MethodDelete, "/_requests", nil))
+
+	var resp ClearRequestsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Cleared != 2 {
+		t.Errorf("expected cleared 2, got %d", resp.Cleared)
+	}
+
+	if got := listRequests(t, rr, "/_requests"); got.Count != 0 {
+		t.Errorf("expected count 0 after clear, got %d", got.Count)
+	}
+}
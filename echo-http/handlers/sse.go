@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxSSEEvents   = 100
+	maxSSEInterval = 60      // 60 seconds
+	maxSSERetry    = 3600000 // 1 hour in milliseconds
+	defaultSSEWait = time.Second
+)
+
+// SSEEvent is the JSON payload carried in the data field of each event.
+type SSEEvent struct {
+	ID          int    `json:"id"`
+	Event       string `json:"event,omitempty"`
+	Count       int    `json:"count"`
+	LastEventID string `json:"last_event_id,omitempty"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// SSEHandler streams Server-Sent Events.
+// GET /sse?count={n}&interval={s}&event={names}&retry={ms}&keepalive={s}&multiline={bool}&close_after={n}
+//
+// Events are numbered 1..count and carry their number in the id field. A
+// reconnecting client sending Last-Event-ID resumes after that event; once
+// all events have been delivered the server answers 204 so EventSource stops
+// reconnecting.
+func SSEHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	count := 10 // default 10 events
+	if c := query.Get("count"); c != "" {
+		parsed, err := strconv.Atoi(c)
+		if err != nil || parsed < 0 || parsed > maxSSEEvents {
+			http.Error(w, fmt.Sprintf("Invalid count (must be 0-%d)", maxSSEEvents), http.StatusBadRequest)
+			return
+		}
+		count = parsed
+	}
+
+	interval := defaultSSEWait
+	if i := query.Get("interval"); i != "" {
+		parsed, err := strconv.ParseFloat(i, 64)
+		if err != nil || parsed < 0 || parsed > maxSSEInterval {
+			http.Error(w, fmt.Sprintf("Invalid interval (must be 0-%d seconds)", maxSSEInterval), http.StatusBadRequest)
+			return
+		}
+		interval = time.Duration(parsed * float64(time.Second))
+	}
+
+	var keepalive time.Duration // default no keep-alive comments
+	if k := query.Get("keepalive"); k != "" {
+		parsed, err := strconv.ParseFloat(k, 64)
+		if err != nil || parsed < 0 || parsed > maxSSEInterval {
+			http.Error(w, fmt.Sprintf("Invalid keepalive (must be 0-%d seconds)", maxSSEInterval), http.StatusBadRequest)
+			return
+		}
+		keepalive = time.Duration(parsed * float64(time.Second))
+	}
+
+	retry := -1 // default no retry hint
+	if rt := query.Get("retry"); rt != "" {
+		parsed, err := strconv.Atoi(rt)
+		if err != nil || parsed < 0 || parsed > maxSSERetry {
+			http.Error(w, fmt.Sprintf("Invalid retry (must be 0-%d milliseconds)", maxSSERetry), http.StatusBadRequest)
+			return
+		}
+		retry = parsed
+	}
+
+	closeAfter := 0 // default send all remaining events
+	if c := query.Get("close_after"); c != "" {
+		parsed, err := strconv.Atoi(c)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid close_after (must be >= 0)", http.StatusBadRequest)
+			return
+		}
+		closeAfter = parsed
+	}
+
+	multiline := false
+	if m := query.Get("multiline"); m != "" {
+		parsed, err := strconv.ParseBool(m)
+		if err != nil {
+			http.Error(w, "Invalid multiline (must be true or false)", http.StatusBadRequest)
+			return
+		}
+		multiline = parsed
+	}
+
+	var names []string
+	if e := query.Get("event"); e != "" {
+		names = strings.Split(e, ",")
+	}
+
+	// EventSource sends Last-Event-ID on reconnect; the query parameter is
+	// for clients that cannot set headers
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("last_event_id")
+	}
+	start := 1
+	if lastEventID != "" {
+		parsed, err := strconv.Atoi(lastEventID)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid Last-Event-ID (must be a non-negative integer)", http.StatusBadRequest)
+			return
+		}
+		start = parsed + 1
+	}
+
+	if start > count {
+		// 204 tells EventSource to stop reconnecting
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if retry >= 0 {
+		_, _ = fmt.Fprintf(w, "retry: %d\n\n", retry)
+	}
+	flusher.Flush()
+
+	sent := 0
+	for id := start; id <= count; id++ {
+		if closeAfter > 0 && sent >= closeAfter {
+			return
+		}
+		if sent > 0 && !waitSSE(w, flusher, r, interval, keepalive) {
+			return
+		}
+
+		event := SSEEvent{
+			ID:          id,
+			Count:       count,
+			LastEventID: lastEventID,
+			Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		}
+		if len(names) > 0 {
+			event.Event = names[(id-1)%len(names)]
+		}
+
+		_, _ = w.Write(formatSSEEvent(event, multiline))
+		flusher.Flush()
+		sent++
+	}
+}
+
+// waitSSE sleeps for interval, writing a comment every keepalive. It returns
+// false if the client went away.
+func waitSSE(w http.ResponseWriter, flusher http.Flusher, r *http.Request, interval, keepalive time.Duration) bool {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	var tick <-chan time.Time
+	if keepalive > 0 {
+		ticker := time.NewTicker(keepalive)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return false
+		case <-timer.C:
+			return true
+		case <-tick:
+			_, _ = w.Write([]byte(": keep-alive\n\n"))
+			flusher.Flush()
+		}
+	}
+}
+
+// formatSSEEvent encodes event as an SSE block. With multiline the JSON is
+// indented so the payload spans several data lines.
+func formatSSEEvent(event SSEEvent, multiline bool) []byte {
+	var data []byte
+	if multiline {
+		data, _ = json.MarshalIndent(event, "", "  ")
+	} else {
+		data, _ = json.Marshal(event)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "id: %d\n", event.ID)
+	if event.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event.Event)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	return []byte(b.String())
+}
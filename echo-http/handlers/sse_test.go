@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type parsedSSE struct {
+	id     string
+	event  string
+	data   string
+	retry  string
+	isNote bool
+}
+
+// parseSSE splits an event stream into blocks, joining data lines with "\n"
+// the way EventSource does.
+func parseSSE(t *testing.T, body string) []parsedSSE {
+	t.Helper()
+
+	var blocks []parsedSSE
+	var cur parsedSSE
+	var data []string
+	seen := false
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if seen {
+				cur.data = strings.Join(data, "\n")
+				blocks = append(blocks, cur)
+			}
+			cur, data, seen = parsedSSE{}, nil, false
+			continue
+		}
+		seen = true
+		if strings.HasPrefix(line, ":") {
+			cur.isNote = true
+			continue
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			cur.id = value
+		case "event":
+			cur.event = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			cur.retry = value
+		default:
+			t.Errorf("unexpected field %q", field)
+		}
+	}
+	return blocks
+}
+
+func TestSSEHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		lastEventID    string
+		expectedStatus int
+		expectedIDs    []string
+	}{
+		{
+			name:           "count",
+			query:          "?count=3&interval=0",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"1", "2", "3"},
+		},
+		{
+			name:           "resumes after Last-Event-ID",
+			query:          "?count=5&interval=0",
+			lastEventID:    "3",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"4", "5"},
+		},
+		{
+			name:           "resumes after last_event_id query",
+			query:          "?count=5&interval=0&last_event_id=4",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"5"},
+		},
+		{
+			name:           "close_after ends the connection early",
+			query:          "?count=5&interval=0&close_after=2",
+			lastEventID:    "1",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"2", "3"},
+		},
+		{
+			name:           "all events delivered returns 204",
+			query:          "?count=3&interval=0",
+			lastEventID:    "3",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid Last-Event-ID returns 400",
+			query:          "?count=3&interval=0",
+			lastEventID:    "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "count over max returns 400",
+			query:          "?count=101",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "negative interval returns 400",
+			query:          "?interval=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid retry returns 400",
+			query:          "?retry=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid multiline returns 400",
+			query:          "?multiline=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/sse"+tt.query, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			rec := httptest.NewRecorder()
+
+			SSEHandler(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+				t.Errorf("expected Content-Type text/event-stream, got %s", ct)
+			}
+
+			events := parseSSE(t, rec.Body.String())
+			if len(events) != len(tt.expectedIDs) {
+				t.Fatalf("expected %d events, got %d", len(tt.expectedIDs), len(events))
+			}
+			for i, event := range events {
+				if event.id != tt.expectedIDs[i] {
+					t.Errorf("expected id %s, got %s", tt.expectedIDs[i], event.id)
+				}
+			}
+		})
+	}
+}
+
+func TestSSEHandlerFields(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/sse?count=3&interval=0&event=tick,tock&retry=1500&multiline=true", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	rec := httptest.NewRecorder()
+
+	SSEHandler(rec, req)
+
+	blocks := parseSSE(t, rec.Body.String())
+	if len(blocks) != 4 {
+		t.Fatalf("expected retry block and 3 events, got %d blocks", len(blocks))
+	}
+
+	if blocks[0].retry != "1500" {
+		t.Errorf("expected retry 1500, got %q", blocks[0].retry)
+	}
+
+	expectedNames := []string{"tick", "tock", "tick"}
+	for i, block := range blocks[1:] {
+		if block.event != expectedNames[i] {
+			t.Errorf("expected event %s, got %s", expectedNames[i], block.event)
+		}
+		if !strings.Contains(block.data, "\n") {
+			t.Errorf("expected multi-line data, got %q", block.data)
+		}
+
+		var event SSEEvent
+		if err := json.Unmarshal([]byte(block.data), &event); err != nil {
+			t.Fatalf("failed to parse data: %v", err)
+		}
+		if event.ID != i+1 {
+			t.Errorf("expected id %d, got %d", i+1, event.ID)
+		}
+		if event.Event != expectedNames[i] {
+			t.Errorf("expected event %s in data, got %s", expectedNames[i], event.Event)
+		}
+		if event.LastEventID != "0" {
+			t.Errorf("expected last_event_id 0, got %q", event.LastEventID)
+		}
+	}
+}
+
+func TestSSEHandlerKeepAlive(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/sse?count=2&interval=0.1&keepalive=0.02", nil)
+	rec := httptest.NewRecorder()
+
+	SSEHandler(rec, req)
+
+	comments := 0
+	for _, block := range parseSSE(t, rec.Body.String()) {
+		if block.isNote {
+			comments++
+		}
+	}
+	if comments == 0 {
+		t.Error("expected keep-alive comments between events")
+	}
+}
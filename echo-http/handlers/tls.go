@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// TLSInfo describes the TLS connection a request arrived on.
+type TLSInfo struct {
+	Version            string            `json:"version"`
+	CipherSuite        string            `json:"cipher_suite"`
+	ServerName         string            `json:"server_name,omitempty"`
+	NegotiatedProtocol string            `json:"negotiated_protocol,omitempty"`
+	Verified           bool              `json:"verified"`
+	PeerCertificates   []PeerCertificate `json:"peer_certificates,omitempty"`
+}
+
+// PeerCertificate summarizes a certificate presented by the client.
+type PeerCertificate struct {
+	Subject           string   `json:"subject"`
+	Issuer            string   `json:"issuer"`
+	SerialNumber      string   `json:"serial_number"`
+	NotBefore         string   `json:"not_before"`
+	NotAfter          string   `json:"not_after"`
+	DNSNames          []string `json:"dns_names,omitempty"`
+	FingerprintSHA256 string   `json:"fingerprint_sha256"`
+}
+
+// getTLSInfo returns the TLS details of the request, or nil for plaintext requests.
+// Verified is true when the client certificate chain was verified against the
+// configured client CA.
+func getTLSInfo(r *http.Request) *TLSInfo {
+	if r.TLS == nil {
+		return nil
+	}
+
+	info := &TLSInfo{
+		Version:            tls.VersionName(r.TLS.Version),
+		CipherSuite:        tls.CipherSuiteName(r.TLS.CipherSuite),
+		ServerName:         r.TLS.ServerName,
+		NegotiatedProtocol: r.TLS.NegotiatedProtocol,
+		Verified:           len(r.TLS.VerifiedChains) > 0,
+	}
+
+	for _, cert := range r.TLS.PeerCertificates {
+		info.PeerCertificates = append(info.PeerCertificates, newPeerCertificate(cert))
+	}
+
+	return info
+}
+
+func newPeerCertificate(cert *x509.Certificate) PeerCertificate {
+	fingerprint := sha256.Sum256(cert.Raw)
+	return PeerCertificate{
+		Subject:           cert.Subject.String(),
+		Issuer:            cert.Issuer.String(),
+		SerialNumber:      cert.SerialNumber.String(),
+		NotBefore:         cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:          cert.NotAfter.UTC().Format(time.RFC3339),
+		DNSNames:          cert.DNSNames,
+		FingerprintSHA256: hex.EncodeToString(fingerprint[:]),
+	}
+}
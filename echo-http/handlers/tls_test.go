@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestAnythingHandlerTLS(t *testing.T) {
+	r := chi.NewRouter()
+	r.HandleFunc("/anything", AnythingHandler)
+
+	peer := &x509.Certificate{
+		Raw:          []byte("fake-der"),
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "echo-client"},
+		Issuer:       pkix.Name{CommonName: "echo-servers test CA"},
+		NotBefore:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		DNSNames:     []string{"client.local"},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/anything", nil)
+	req.TLS = &tls.ConnectionState{
+		Version:            tls.VersionTLS13,
+		CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+		ServerName:         "echo.local",
+		NegotiatedProtocol: "h2",
+		PeerCertificates:   []*x509.Certificate{peer},
+		VerifiedChains:     [][]*x509.Certificate{{peer}},
+	}
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	var response AnythingResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if response.TLS == nil {
+		t.Fatal("expected tls block to be populated")
+	}
+	if response.TLS.Version != "TLS 1.3" {
+		t.Errorf("expected version TLS 1.3, got %s", response.TLS.Version)
+	}
+	if response.TLS.ServerName != "echo.local" {
+		t.Errorf("expected server_name echo.local, got %s", response.TLS.ServerName)
+	}
+	if response.TLS.NegotiatedProtocol != "h2" {
+		t.Errorf("expected negotiated_protocol h2, got %s", response.TLS.NegotiatedProtocol)
+	}
+	if !response.TLS.Verified {
+		t.Error("expected verified to be true")
+	}
+	if len(response.TLS.PeerCertificates) != 1 {
+		t.Fatalf("expected 1 peer certificate, got %d", len(response.TLS.PeerCertificates))
+	}
+	cert := response.TLS.PeerCertificates[0]
+	if cert.Subject != "CN=echo-client" {
+		t.Errorf("expected subject CN=echo-client, got %s", cert.Subject)
+	}
+	if cert.SerialNumber != "42" {
+		t.Errorf("expected serial 42, got %s", cert.SerialNumber)
+	}
+}
+
+func TestAnythingHandlerPlaintextOmitsTLS(t *testing.T) {
+	r := chi.NewRouter()
+	r.HandleFunc("/anything", AnythingHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/anything", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	var raw map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&raw); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if _, ok := raw["tls"]; ok {
+		t.Error("expected tls block to be omitted for plaintext requests")
+	}
+}
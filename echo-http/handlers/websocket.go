@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxWebSocketPing     = 60 // 60 seconds
+	maxWebSocketFragment = 64 * 1024
+	maxCloseReasonLength = 123 // 125-byte control frame minus the 2-byte code
+	webSocketWriteWait   = 5 * time.Second
+)
+
+// defaultSubprotocols are offered when the request does not list any.
+var defaultSubprotocols = []string{"echo"}
+
+// webSocketOptions holds the per-connection behavior parsed from the query.
+type webSocketOptions struct {
+	subprotocols []string
+	ping         time.Duration
+	fragment     int
+	closeAfter   int
+	closeCode    int
+	closeReason  string
+	drop         bool
+}
+
+// validCloseCode reports whether code may be sent in a close frame: the codes
+// RFC 6455 and IANA assign for use on the wire, and the 3000-4999 registered
+// and private ranges. 1005 is accepted too and sends a close frame without a
+// status code; 1004, 1006, 1015 and the unassigned 1016-2999 are rejected.
+func validCloseCode(code int) bool {
+	switch {
+	case code >= 1000 && code <= 1003, code == websocket.CloseNoStatusReceived:
+		return true
+	case code >= 1007 && code <= 1014:
+		return true
+	default:
+		return code >= 3000 && code <= 4999
+	}
+}
+
+// WebSocketHandler echoes WebSocket messages.
+// GET /ws?subprotocols={list}&ping={s}&fragment={n}&close_after={n}&close={code}&reason={text}&drop={bool}
+//
+// Text and binary messages are echoed back with the same type. After
+// close_after messages (or right away when close_after is 0 and close or
+// drop is set) the server either sends a close frame with the chosen code and
+// reason, or with drop drops the TCP connection without one.
+func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	opts, ok := parseWebSocketOptions(w, r)
+	if !ok {
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		Subprotocols: opts.subprotocols,
+		CheckOrigin:  func(*http.Request) bool { return true },
+	}
+	if opts.fragment > 0 {
+		// gorilla flushes a frame whenever the write buffer fills up, so the
+		// buffer size is the fragment size
+		upgrader.WriteBufferSize = opts.fragment
+	}
+
+	// Upgrade replies with an HTTP error itself on failure
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	done := make(chan struct{})
+	defer close(done)
+	if opts.ping > 0 {
+		go pingWebSocket(conn, opts.ping, done)
+	}
+
+	terminate := opts.drop || opts.closeCode != 0
+	for echoed := 0; ; echoed++ {
+		if terminate && echoed >= opts.closeAfter {
+			closeWebSocket(conn, opts)
+			return
+		}
+
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if err := writeWebSocketMessage(conn, messageType, data); err != nil {
+			return
+		}
+	}
+}
+
+// parseWebSocketOptions replies with 400 and returns false if a query
+// parameter is invalid.
+func parseWebSocketOptions(w http.ResponseWriter, r *http.Request) (webSocketOptions, bool) {
+	query := r.URL.Query()
+	opts := webSocketOptions{subprotocols: defaultSubprotocols}
+
+	if s := query.Get("subprotocols"); s != "" {
+		opts.subprotocols = strings.Split(s, ",")
+	}
+
+	if p := query.Get("ping"); p != "" {
+		parsed, err := strconv.ParseFloat(p, 64)
+		if err != nil || parsed < 0 || parsed > maxWebSocketPing {
+			http.Error(w, fmt.Sprintf("Invalid ping (must be 0-%d seconds)", maxWebSocketPing), http.StatusBadRequest)
+			return opts, false
+		}
+		opts.ping = time.Duration(parsed * float64(time.Second))
+	}
+
+	if f := query.Get("fragment"); f != "" {
+		parsed, err := strconv.Atoi(f)
+		if err != nil || parsed < 0 || parsed > maxWebSocketFragment {
+			http.Error(w, fmt.Sprintf("Invalid fragment (must be 0-%d bytes)", maxWebSocketFragment), http.StatusBadRequest)
+			return opts, false
+		}
+		opts.fragment = parsed
+	}
+
+	if c := query.Get("close_after"); c != "" {
+		parsed, err := strconv.Atoi(c)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid close_after (must be >= 0)", http.StatusBadRequest)
+			return opts, false
+		}
+		opts.closeAfter = parsed
+	}
+
+	if c := query.Get("close"); c != "" {
+		parsed, err := strconv.Atoi(c)
+		if err != nil || !validCloseCode(parsed) {
+			http.Error(w, "Invalid close code (must be 1000-1003, 1005, 1007-1014 or 3000-4999)", http.StatusBadRequest)
+			return opts, false
+		}
+		opts.closeCode = parsed
+	}
+
+	opts.closeReason = query.Get("reason")
+	if len(opts.closeReason) > maxCloseReasonLength {
+		http.Error(w, fmt.Sprintf("Invalid reason (must be at most %d bytes)", maxCloseReasonLength), http.StatusBadRequest)
+		return opts, false
+	}
+
+	if d := query.Get("drop"); d != "" {
+		parsed, err := strconv.ParseBool(d)
+		if err != nil {
+			http.Error(w, "Invalid drop (must be true or false)", http.StatusBadRequest)
+			return opts, false
+		}
+		opts.drop = parsed
+	}
+
+	// close_after alone ends with a normal closure
+	if opts.closeAfter > 0 && opts.closeCode == 0 && !opts.drop {
+		opts.closeCode = websocket.CloseNormalClosure
+	}
+
+	return opts, true
+}
+
+// writeWebSocketMessage echoes data through NextWriter so that a small write
+// buffer splits it into continuation frames.
+func writeWebSocketMessage(conn *websocket.Conn, messageType int, data []byte) error {
+	writer, err := conn.NextWriter(messageType)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func pingWebSocket(conn *websocket.Conn, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			// WriteControl may be called concurrently with the echo writes
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func closeWebSocket(conn *websocket.Conn, opts webSocketOptions) {
+	if opts.drop {
+		// Close the TCP connection without a close frame
+		_ = conn.NetConn().Close()
+		return
+	}
+
+	msg := websocket.FormatCloseMessage(opts.closeCode, opts.closeReason)
+	if err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(webSocketWriteWait)); err != nil {
+		return
+	}
+
+	// Give the client a chance to answer with its own close frame
+	_ = conn.SetReadDeadline(time.Now().Add(webSocketWriteWait))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func dialWebSocket(t *testing.T, query string, dialer *websocket.Dialer) (*websocket.Conn, *http.Response) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(WebSocketHandler))
+	t.Cleanup(srv.Close)
+
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws" + query
+	conn, resp, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, resp
+}
+
+func TestWebSocketHandlerEcho(t *testing.T) {
+	conn, _ := dialWebSocket(t, "", nil)
+
+	messages := []struct {
+		messageType int
+		data        []byte
+	}{
+		{websocket.TextMessage, []byte("hello")},
+		{websocket.BinaryMessage, []byte{0x00, 0xff, 0x10}},
+	}
+
+	for _, msg := range messages {
+		if err := conn.WriteMessage(msg.messageType, msg.data); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		if messageType != msg.messageType {
+			t.Errorf("expected message type %d, got %d", msg.messageType, messageType)
+		}
+		if !bytes.Equal(data, msg.data) {
+			t.Errorf("expected %q, got %q", msg.data, data)
+		}
+	}
+}
+
+func TestWebSocketHandlerSubprotocols(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		offered  []string
+		expected string
+	}{
+		{
+			name:     "default echo subprotocol",
+			offered:  []string{"chat", "echo"},
+			expected: "echo",
+		},
+		{
+			name:     "custom subprotocols",
+			query:    "?subprotocols=v2.chat,v1.chat",
+			offered:  []string{"v1.chat", "v2.chat"},
+			expected: "v2.chat",
+		},
+		{
+			name:     "no match",
+			query:    "?subprotocols=v2.chat",
+			offered:  []string{"v1.chat"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialer := &websocket.Dialer{Subprotocols: tt.offered}
+			conn, _ := dialWebSocket(t, tt.query, dialer)
+
+			if conn.Subprotocol() != tt.expected {
+				t.Errorf("expected subprotocol %q, got %q", tt.expected, conn.Subprotocol())
+			}
+		})
+	}
+}
+
+func TestWebSocketHandlerClose(t *testing.T) {
+	conn, _ := dialWebSocket(t, "?close_after=1&close=4000&reason=bye", nil)
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("hello")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if _, data, err := conn.ReadMessage(); err != nil || string(data) != "hello" {
+		t.Fatalf("expected echo before close, got %q, %v", data, err)
+	}
+
+	_, _, err := conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		t.Fatalf("expected close error, got %v", err)
+	}
+	if closeErr.Code != 4000 || closeErr.Text != "bye" {
+		t.Errorf("expected close 4000 bye, got %d %s", closeErr.Code, closeErr.Text)
+	}
+}
+
+func TestWebSocketHandlerDrop(t *testing.T) {
+	conn, _ := dialWebSocket(t, "?drop=true", nil)
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseAbnormalClosure) {
+		t.Errorf("expected abnormal closure without close frame, got %v", err)
+	}
+}
+
+func TestWebSocketHandlerPing(t *testing.T) {
+	conn, _ := dialWebSocket(t, "?ping=0.02", nil)
+
+	var pings atomic.Int32
+	conn.SetPingHandler(func(string) error {
+		pings.Add(1)
+		return nil
+	})
+
+	// Control frames are handled while reading
+	_ = conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	_, _, _ = conn.ReadMessage()
+
+	if pings.Load() == 0 {
+		t.Error("expected server-initiated pings")
+	}
+}
+
+// frameRecorder keeps the raw bytes the client reads from the server.
+type frameRecorder struct {
+	net.Conn
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (f *frameRecorder) Read(p []byte) (int, error) {
+	n, err := f.Conn.Read(p)
+	f.mu.Lock()
+	f.buf.Write(p[:n])
+	f.mu.Unlock()
+	return n, err
+}
+
+// opcodes returns the opcode of every server frame after the handshake.
+func (f *frameRecorder) opcodes(t *testing.T) []byte {
+	t.Helper()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, frames, ok := bytes.Cut(f.buf.Bytes(), []byte("\r\n\r\n"))
+	if !ok {
+		t.Fatal("handshake response not found")
+	}
+
+	var opcodes []byte
+	for len(frames) >= 2 {
+		opcodes = append(opcodes, frames[0]&0x0f)
+		length, header := int(frames[1]&0x7f), 2
+		switch length {
+		case 126:
+			length, header = int(binary.BigEndian.Uint16(frames[2:4])), 4
+		case 127:
+			length, header = int(binary.BigEndian.Uint64(frames[2:10])), 10
+		}
+		frames = frames[header+length:]
+	}
+	return opcodes
+}
+
+func TestWebSocketHandlerFragment(t *testing.T) {
+	recorder := &frameRecorder{}
+	dialer := &websocket.Dialer{
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			recorder.Conn = conn
+			return recorder, err
+		},
+	}
+	conn, _ := dialWebSocket(t, "?fragment=4", dialer)
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("hello world")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if string(data) != "hello world" {
+		t.Errorf("expected reassembled message, got %q", data)
+	}
+
+	// 11 bytes in 4-byte fragments: text, continuation, continuation
+	expected := []byte{websocket.TextMessage, 0, 0}
+	if opcodes := recorder.opcodes(t); !bytes.Equal(opcodes, expected) {
+		t.Errorf("expected opcodes %v, got %v", expected, opcodes)
+	}
+}
+
+func TestWebSocketHandlerInvalidParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "invalid ping", query: "?ping=-1"},
+		{name: "fragment over max", query: "?fragment=100000"},
+		{name: "invalid close_after", query: "?close_after=abc"},
+		{name: "close code out of range", query: "?close=999"},
+		{name: "reserved close code", query: "?close=1004"},
+		{name: "abnormal closure", query: "?close=1006"},
+		{name: "TLS handshake close code", query: "?close=1015"},
+		{name: "unassigned close code", query: "?close=2999"},
+		{name: "close code over max", query: "?close=5000"},
+		{name: "reason too long", query: "?close=4000&reason=" + strings.Repeat("x", 124)},
+		{name: "invalid drop", query: "?drop=maybe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ws"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			WebSocketHandler(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestValidCloseCode(t *testing.T) {
+	for _, code := range []int{1000, 1003, 1005, 1007, 1011, 1014, 3000, 4999} {
+		if !validCloseCode(code) {
+			t.Errorf("expected %d to be valid", code)
+		}
+	}
+	for _, code := range []int{999, 1004, 1006, 1015, 1016, 2999, 5000} {
+		if validCloseCode(code) {
+			t.Errorf("expected %d to be invalid", code)
+		}
+	}
+}
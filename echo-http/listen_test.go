@@ -0,0 +1,216 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseListen(t *testing.T) {
+	tests := []struct {
+		value       string
+		wantNetwork string
+		wantAddress string
+		wantErr     string
+	}{
+		{value: "tcp://127.0.0.1:8080", wantNetwork: "tcp", wantAddress: "127.0.0.1:8080"},
+		{value: "tcp://[::1]:8080", wantNetwork: "tcp", wantAddress: "[::1]:8080"},
+		{value: "tcp://:8080", wantNetwork: "tcp", wantAddress: ":8080"},
+		{value: "unix:echo.sock", wantNetwork: "unix", wantAddress: "echo.sock"},
+		{value: "unix:/run/echo.sock", wantNetwork: "unix", wantAddress: "/run/echo.sock"},
+		{value: "unix:///run/echo.sock", wantNetwork: "unix", wantAddress: "/run/echo.sock"},
+		{value: "unix-abstract:echo", wantNetwork: "unix", wantAddress: "@echo"},
+		{value: "tcp://127.0.0.1", wantErr: "missing port in address"},
+		{value: "unix:", wantErr: "missing socket path"},
+		{value: "unix://run/echo.sock", wantErr: "unix:// needs an absolute path"},
+		{value: "unix-abstract:", wantErr: "missing socket name"},
+		{value: "127.0.0.1:8080", wantErr: "want tcp://HOST:PORT, unix:PATH or unix-abstract:NAME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			network, address, err := parseListen(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if network != tt.wantNetwork || address != tt.wantAddress {
+				t.Errorf("expected %s %s, got %s %s", tt.wantNetwork, tt.wantAddress, network, address)
+			}
+		})
+	}
+}
+
+func TestConfig_ParseListener(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		tls     bool
+		want    Listener
+		wantErr string
+	}{
+		{
+			name:  "plain",
+			entry: "tcp://127.0.0.1:8080",
+			want:  Listener{Name: "tcp://127.0.0.1:8080", Network: "tcp", Address: "127.0.0.1:8080"},
+		},
+		{
+			name:  "TLS by default when configured",
+			entry: "tcp://127.0.0.1:8443",
+			tls:   true,
+			want:  Listener{Name: "tcp://127.0.0.1:8443", Network: "tcp", Address: "127.0.0.1:8443", TLS: true},
+		},
+		{
+			name:  "tls=false",
+			entry: "unix:echo.sock?tls=false",
+			tls:   true,
+			want:  Listener{Name: "unix:echo.sock", Network: "unix", Address: "echo.sock"},
+		},
+		{
+			name:  "h2 with TLS",
+			entry: "tcp://127.0.0.1:8443?tls=true&proto=h2",
+			tls:   true,
+			want:  Listener{Name: "tcp://127.0.0.1:8443", Network: "tcp", Address: "127.0.0.1:8443", TLS: true, Proto: "h2"},
+		},
+		{
+			name:  "h2c",
+			entry: "unix-abstract:echo?proto=h2c",
+			want:  Listener{Name: "unix-abstract:echo", Network: "unix", Address: "@echo", Proto: "h2c"},
+		},
+		{
+			name:  "h1",
+			entry: "unix:///run/echo.sock?proto=h1",
+			want:  Listener{Name: "unix:///run/echo.sock", Network: "unix", Address: "/run/echo.sock", Proto: "h1"},
+		},
+		{
+			name:  "last value wins",
+			entry: "tcp://127.0.0.1:8080?proto=h2&proto=h1",
+			want:  Listener{Name: "tcp://127.0.0.1:8080", Network: "tcp", Address: "127.0.0.1:8080", Proto: "h1"},
+		},
+		{
+			name:    "invalid address",
+			entry:   "localhost:8080?tls=false",
+			wantErr: `invalid LISTEN "localhost:8080": want tcp://HOST:PORT, unix:PATH or unix-abstract:NAME`,
+		},
+		{
+			name:    "invalid query",
+			entry:   "tcp://127.0.0.1:8080?tls=%zz",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:8080?tls=%zz": invalid URL escape`,
+		},
+		{
+			name:    "invalid tls",
+			entry:   "tcp://127.0.0.1:8080?tls=yes",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:8080?tls=yes": tls must be true or false`,
+		},
+		{
+			name:    "tls=true without TLS",
+			entry:   "tcp://127.0.0.1:8080?tls=true",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:8080?tls=true": tls=true needs TLS_CERT_FILE or TLS_SELF_SIGNED`,
+		},
+		{
+			name:    "invalid proto",
+			entry:   "tcp://127.0.0.1:8080?proto=h3",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:8080?proto=h3": proto must be h1, h2 or h2c`,
+		},
+		{
+			name:    "h2 without TLS",
+			entry:   "tcp://127.0.0.1:8080?proto=h2",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:8080?proto=h2": proto=h2 needs TLS, use h2c without it`,
+		},
+		{
+			name:    "h2c with TLS",
+			entry:   "tcp://127.0.0.1:8443?proto=h2c",
+			tls:     true,
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:8443?proto=h2c": proto=h2c is for cleartext, use h2 with TLS`,
+		},
+		{
+			name:    "unknown option",
+			entry:   "tcp://127.0.0.1:8080?mode=fast",
+			wantErr: `invalid LISTEN "tcp://127.0.0.1:8080?mode=fast": unknown option "mode"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{TLSSelfSigned: tt.tls}
+			l, err := cfg.parseListener(tt.entry)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if l != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, l)
+			}
+		})
+	}
+}
+
+func TestConfig_Listeners(t *testing.T) {
+	t.Run("defaults to HOST and PORT", func(t *testing.T) {
+		cfg := &Config{Host: "127.0.0.1", Port: "8080", TLSSelfSigned: true}
+		listeners, err := cfg.Listeners()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := Listener{Name: "127.0.0.1:8080", Network: "tcp", Address: "127.0.0.1:8080", TLS: true}
+		if len(listeners) != 1 || listeners[0] != want {
+			t.Errorf("expected [%+v], got %+v", want, listeners)
+		}
+	})
+
+	t.Run("parses every address", func(t *testing.T) {
+		cfg := &Config{Host: "127.0.0.1", Port: "8080", Listen: " tcp://:8080 ,, unix:echo.sock?proto=h2c,"}
+		listeners, err := cfg.Listeners()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []Listener{
+			{Name: "tcp://:8080", Network: "tcp", Address: ":8080"},
+			{Name: "unix:echo.sock", Network: "unix", Address: "echo.sock", Proto: "h2c"},
+		}
+		if len(listeners) != len(want) || listeners[0] != want[0] || listeners[1] != want[1] {
+			t.Errorf("expected %+v, got %+v", want, listeners)
+		}
+	})
+
+	t.Run("fails on the first invalid address", func(t *testing.T) {
+		cfg := &Config{Listen: "tcp://:8080,udp://:8080"}
+		if _, err := cfg.Listeners(); err == nil || !strings.Contains(err.Error(), `invalid LISTEN "udp://:8080"`) {
+			t.Errorf("expected error for udp://:8080, got %v", err)
+		}
+	})
+
+	t.Run("no addresses", func(t *testing.T) {
+		cfg := &Config{Listen: " , "}
+		if _, err := cfg.Listeners(); err == nil || err.Error() != "LISTEN has no addresses" {
+			t.Errorf("expected LISTEN has no addresses, got %v", err)
+		}
+	})
+}
+
+func TestListener_String(t *testing.T) {
+	tests := []struct {
+		listener Listener
+		want     string
+	}{
+		{Listener{Name: "tcp://:8080"}, "tcp://:8080"},
+		{Listener{Name: "tcp://:8443", TLS: true}, "tcp://:8443 (TLS)"},
+		{Listener{Name: "tcp://:8443", TLS: true, Proto: "h2"}, "tcp://:8443 (TLS, h2)"},
+		{Listener{Name: "unix:echo.sock", Proto: "h2c"}, "unix:echo.sock (h2c)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.listener.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
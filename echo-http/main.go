@@ -1,21 +1,44 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"github.com/jsr-probitas/echo-servers/echo-http/accesslog"
 	"github.com/jsr-probitas/echo-servers/echo-http/handlers"
+	"github.com/jsr-probitas/echo-servers/echo-http/metrics"
 )
 
 func main() {
 	cfg := LoadConfig()
 
+	m := metrics.New()
+	recorder := handlers.NewRequestRecorder(cfg.RequestHistorySize, cfg.RequestHistoryMaxBody)
+
+	chaosRules, err := cfg.LoadChaosRules()
+	if err != nil {
+		log.Fatalf("Failed to load chaos rules: %v", err)
+	}
+	chaos := handlers.NewChaos(chaosRules)
+	health := handlers.NewHealth()
+
 	r := chi.NewRouter()
-	r.Use(middleware.Logger)
+	// Log each request as a JSON line correlated by X-Request-ID
+	if cfg.AccessLog {
+		r.Use(accesslog.New(os.Stdout).Middleware)
+	}
 	r.Use(middleware.Recoverer)
+	r.Use(m.Middleware)
+	r.Use(recorder.Middleware)
+	r.Use(chaos.Middleware)
 
 	// Echo endpoints
 	r.Get("/get", handlers.EchoHandler)
@@ -61,20 +84,84 @@ func main() {
 	// Streaming endpoints
 	r.Get("/stream/{n}", handlers.StreamHandler)
 	r.Get("/drip", handlers.DripHandler)
+	r.Get("/sse", handlers.SSEHandler)
+
+	// WebSocket endpoint
+	r.Get("/ws", handlers.WebSocketHandler)
 
 	// Compression endpoints
 	r.Get("/gzip", handlers.GzipHandler)
 	r.Get("/deflate", handlers.DeflateHandler)
 	r.Get("/brotli", handlers.BrotliHandler)
 
+	// Request history endpoints
+	r.Get("/_requests", recorder.ListHandler)
+	r.Delete("/_requests", recorder.ClearHandler)
+
+	// Fault injection endpoints
+	r.Get("/_chaos", chaos.ListHandler)
+	r.Put("/_chaos", chaos.ReplaceHandler)
+	r.Post("/_chaos", chaos.AddHandler)
+	r.Delete("/_chaos", chaos.ClearHandler)
+
+	// Prometheus metrics endpoint
+	r.Method(http.MethodGet, "/metrics", m.Handler())
+
 	// Health check endpoint
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	r.Get("/health", health.Handler)
+
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		log.Fatalf("Failed to configure TLS: %v", err)
+	}
+
+	// Serve the same routes on every listener, each with its own TLS and
+	// HTTP version settings
+	listeners, err := cfg.Listeners()
+	if err != nil {
+		log.Fatalf("Failed to configure listeners: %v", err)
+	}
+	servers, err := newHTTPServers(listeners, func(l Listener) (*http.Server, error) {
+		srv := &http.Server{Handler: r, Protocols: l.Protocols()}
+		if l.TLS {
+			srv.TLSConfig = tlsConfig.Clone()
+		}
+		return srv, nil
 	})
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
 
-	log.Printf("Starting server on %s", cfg.Addr())
-	if err := http.ListenAndServe(cfg.Addr(), r); err != nil {
+	// Graceful shutdown: fail health checks, drain, then wait for in-flight
+	// requests (HTTP/2 clients receive GOAWAY)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
+
+		log.Println("Shutting down server...")
+		health.Shutdown()
+		if cfg.ShutdownDrainPeriod > 0 {
+			log.Printf("Draining for %s", cfg.ShutdownDrainPeriod)
+			time.Sleep(cfg.ShutdownDrainPeriod)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		if err := servers.Shutdown(ctx); err != nil {
+			log.Printf("Server shutdown error: %v", err)
+			servers.Close()
+		}
+	}()
+
+	if err := servers.Serve(); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
+
+	<-stopped
+	log.Println("Server stopped")
 }
@@ -0,0 +1,192 @@
+// Package metrics exposes Prometheus metrics for the echo HTTP server.
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the collectors recorded by Middleware.
+type Metrics struct {
+	registry      *prometheus.Registry
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	inFlight      *prometheus.GaugeVec
+	activeStreams *prometheus.GaugeVec
+	requestBytes  *prometheus.CounterVec
+	responseBytes *prometheus.CounterVec
+}
+
+// New creates a Metrics instance backed by its own registry, which also
+// includes the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route and method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served by method.",
+		}, []string{"method"}),
+		activeStreams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_active_streams",
+			Help: "Number of streaming responses (flushed or hijacked before completion) by route.",
+		}, []string{"route"}),
+		requestBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_request_bytes_total",
+			Help: "Total request body bytes received by route and method.",
+		}, []string{"route", "method"}),
+		responseBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_response_bytes_total",
+			Help: "Total response body bytes sent by route and method.",
+		}, []string{"route", "method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.inFlight,
+		m.activeStreams,
+		m.requestBytes,
+		m.responseBytes,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records request metrics. The route label is the chi route
+// pattern (e.g. /status/{code}) so path parameters do not explode cardinality.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		inFlight := m.inFlight.WithLabelValues(r.Method)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		body := &countingReader{ReadCloser: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+
+		rw := &responseWriter{ResponseWriter: w, metrics: m, request: r}
+		defer func() {
+			route := routePattern(r)
+			if rw.streaming {
+				m.activeStreams.WithLabelValues(rw.streamRoute).Dec()
+			}
+
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			m.requests.WithLabelValues(route, r.Method, strconv.Itoa(status)).Inc()
+			m.duration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+			m.requestBytes.WithLabelValues(route, r.Method).Add(float64(body.n.Load()))
+			m.responseBytes.WithLabelValues(route, r.Method).Add(float64(rw.bytes))
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return "unmatched"
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// responseWriter captures the status and body size of a response and marks it
+// as a stream once the handler flushes or hijacks the connection.
+type responseWriter struct {
+	http.ResponseWriter
+	metrics     *Metrics
+	request     *http.Request
+	status      int
+	bytes       int64
+	streaming   bool
+	streamRoute string
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+func (rw *responseWriter) Flush() {
+	rw.markStreaming()
+	_ = http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+	if err == nil {
+		if rw.status == 0 {
+			rw.status = http.StatusSwitchingProtocols
+		}
+		rw.markStreaming()
+	}
+	return conn, brw, err
+}
+
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+func (rw *responseWriter) markStreaming() {
+	if rw.streaming {
+		return
+	}
+	rw.streaming = true
+	rw.streamRoute = routePattern(rw.request)
+	rw.metrics.activeStreams.WithLabelValues(rw.streamRoute).Inc()
+}
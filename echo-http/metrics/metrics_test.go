@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func setupRouter(m *Metrics) http.Handler {
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Post("/post", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	})
+	r.Get("/status/{code}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	r.Get("/stream", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("chunk"))
+		w.(http.Flusher).Flush()
+	})
+	return r
+}
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestMiddleware_RecordsRequests(t *testing.T) {
+	m := New()
+	router := setupRouter(m)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/post", strings.NewReader("hello")))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status/418", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status/418", nil))
+
+	body := scrape(t, m)
+
+	expected := []string{
+		`http_requests_total{code="200",method="POST",route="/post"} 1`,
+		`http_requests_total{code="418",method="GET",route="/status/{code}"} 2`,
+		`http_request_bytes_total{method="POST",route="/post"} 5`,
+		`http_response_bytes_total{method="POST",route="/post"} 5`,
+		`http_request_duration_seconds_count{method="GET",route="/status/{code}"} 2`,
+		`http_requests_in_flight{method="GET"} 0`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected metrics to contain %q", line)
+		}
+	}
+}
+
+func TestMiddleware_TracksStreams(t *testing.T) {
+	m := New()
+	router := setupRouter(m)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	body := scrape(t, m)
+	if !strings.Contains(body, `http_active_streams{route="/stream"} 0`) {
+		t.Error("expected http_active_streams for /stream to return to 0")
+	}
+}
+
+func TestMiddleware_UnmatchedRoute(t *testing.T) {
+	m := New()
+	router := setupRouter(m)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	body := scrape(t, m)
+	if !strings.Contains(body, `http_requests_total{code="404",method="GET",route="unmatched"} 1`) {
+		t.Error("expected unmatched request to be counted")
+	}
+}
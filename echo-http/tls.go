@@ -0,0 +1,228 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TLSEnabled reports whether the server should serve TLS instead of plaintext.
+func (c *Config) TLSEnabled() bool {
+	return c.TLSSelfSigned || c.TLSCertFile != ""
+}
+
+// TLSConfig builds the server TLS configuration from the TLS_* settings.
+// It returns nil when TLS is disabled.
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	if !c.TLSEnabled() {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	var clientCAs *x509.CertPool
+
+	if c.TLSSelfSigned {
+		bundle, err := generateSelfSigned(c.TLSSelfSignedDir, c.TLSSelfSignedHosts)
+		if err != nil {
+			return nil, fmt.Errorf("generate self-signed certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{bundle.server}
+		clientCAs = bundle.caPool
+	} else {
+		if c.TLSKeyFile == "" {
+			return nil, errors.New("TLS_KEY_FILE is required when TLS_CERT_FILE is set")
+		}
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.TLSClientCAFile != "" {
+		caPEM, err := os.ReadFile(c.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CA: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in %s", c.TLSClientCAFile)
+		}
+	}
+
+	clientAuth, err := parseClientAuth(c.TLSClientAuth, c.TLSClientCAFile != "")
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth >= tls.VerifyClientCertIfGiven && clientCAs == nil {
+		return nil, errors.New("TLS_CLIENT_CA_FILE is required to verify client certificates")
+	}
+	tlsConfig.ClientAuth = clientAuth
+	tlsConfig.ClientCAs = clientCAs
+
+	return tlsConfig, nil
+}
+
+// parseClientAuth maps TLS_CLIENT_AUTH to a tls.ClientAuthType. When unset,
+// client certificates are required and verified only if a client CA is given.
+func parseClientAuth(value string, hasClientCA bool) (tls.ClientAuthType, error) {
+	switch value {
+	case "":
+		if hasClientCA {
+			return tls.RequireAndVerifyClientCert, nil
+		}
+		return tls.NoClientCert, nil
+	case "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "require":
+		return tls.RequireAnyClientCert, nil
+	case "verify-if-given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require-and-verify":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("invalid TLS_CLIENT_AUTH %q", value)
+	}
+}
+
+type selfSignedBundle struct {
+	server tls.Certificate
+	caPool *x509.CertPool
+}
+
+// generateSelfSigned creates a throwaway CA plus a server certificate and a
+// client certificate signed by it. The CA (ca.pem) and the client key pair
+// (client.pem, client-key.pem) are written to dir so test clients can trust
+// the server and authenticate with mTLS.
+func generateSelfSigned(dir, extraHosts string) (*selfSignedBundle, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate, err := certTemplate("echo-servers test CA")
+	if err != nil {
+		return nil, err
+	}
+	caTemplate.IsCA = true
+	caTemplate.BasicConstraintsValid = true
+	caTemplate.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		hosts = append(hosts, hostname)
+	}
+	for _, h := range strings.Split(extraHosts, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+
+	serverTemplate, err := certTemplate(hosts[0])
+	if err != nil {
+		return nil, err
+	}
+	serverTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, h)
+		}
+	}
+	serverCertPEM, serverKeyPEM, err := issueCert(serverTemplate, caCert, caKey)
+	if err != nil {
+		return nil, err
+	}
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	clientTemplate, err := certTemplate("echo-client")
+	if err != nil {
+		return nil, err
+	}
+	clientTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	clientCertPEM, clientKeyPEM, err := issueCert(clientTemplate, caCert, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	files := []struct {
+		name string
+		data []byte
+		perm os.FileMode
+	}{
+		{"ca.pem", caPEM, 0o644},
+		{"client.pem", clientCertPEM, 0o644},
+		{"client-key.pem", clientKeyPEM, 0o600},
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.name), f.data, f.perm); err != nil {
+			return nil, err
+		}
+	}
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(caCert)
+
+	return &selfSignedBundle{server: serverCert, caPool: caPool}, nil
+}
+
+func certTemplate(commonName string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"jsr-probitas"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}, nil
+}
+
+func issueCert(template, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}